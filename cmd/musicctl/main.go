@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/jumayevgadam/music-app/internal/config"
+	"github.com/jumayevgadam/music-app/internal/connection"
+	"github.com/jumayevgadam/music-app/internal/database/postgres"
+	"github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/internal/music/service"
+	"github.com/jumayevgadam/music-app/pkg/auth"
+	"github.com/jumayevgadam/music-app/pkg/blobstore"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+)
+
+// musicctl is admin tool for catalogue operations, it uses the same config,
+// database connection and music service as the HTTP server.
+
+const usage = `musicctl is admin tool for music catalogue.
+
+Usage:
+  musicctl [-o table|json] [-actor name] <command> [flags] [args]
+
+Commands:
+  add       add song
+  get       get song by id
+  list      list songs
+  update    update fields of song
+  delete    delete song by id
+  import    import songs from csv or ndjson file
+  export    export songs as csv, ndjson or json
+  migrate   run migrations: up, down [steps], version
+
+Run "musicctl <command> -h" to see flags of command.
+`
+
+// app keeps dependencies of commands
+type app struct {
+	db     *connection.Database
+	songs  music.Service
+	output outputFormat
+}
+
+// command runs with its own flags and args
+type command func(ctx context.Context, a *app, args []string) error
+
+var commands = map[string]command{
+	"add":     addSong,
+	"get":     getSong,
+	"list":    listSongs,
+	"update":  updateSong,
+	"delete":  deleteSong,
+	"import":  importSongs,
+	"export":  exportSongs,
+	"migrate": migrate,
+}
+
+func main() {
+	output := flag.String("o", string(outputTable), "output format: table or json")
+	actor := flag.String("actor", "musicctl:"+os.Getenv("USER"), "name recorded in song history")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(cmd, outputFormat(*output), *actor, flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", describe(err))
+		os.Exit(1)
+	}
+}
+
+// run connects to database and runs command
+func run(cmd command, output outputFormat, actor string, args []string) error {
+	if output != outputTable && output != outputJSON {
+		return fmt.Errorf("unknown output format %q", output)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = auth.WithActor(ctx, actor)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	pagination.SetCursorSecret([]byte(cfg.Pagination.CursorSecret))
+
+	db, err := connection.GetDBClient(ctx, cfg.Postgres)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// songs deleted from command line release their audio too
+	blobs, err := blobstore.NewLocal(cfg.Storage.Dir)
+	if err != nil {
+		return err
+	}
+
+	a := &app{
+		db:     db,
+		songs:  service.NewSongService(postgres.NewDataStore(db), blobs),
+		output: output,
+	}
+
+	return cmd(ctx, a, args)
+}
+
+// describe returns same status and message API would give for error
+func describe(err error) string {
+	restErr := errlst.ParseErrors(err)
+
+	return fmt.Sprintf("%s (%d): %v", http.StatusText(restErr.Status()), restErr.Status(), restErr.Causes())
+}
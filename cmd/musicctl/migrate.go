@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/jumayevgadam/music-app/internal/migrations"
+)
+
+func migrate(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: musicctl migrate up | down [steps] | version")
+	}
+	_ = fs.Parse(args)
+
+	switch fs.Arg(0) {
+	case "up":
+		applied, err := migrations.Up(ctx, a.db)
+		if err != nil {
+			return err
+		}
+		return a.printMessage("applied migrations: %v", applied)
+
+	case "down":
+		steps := 1
+		if fs.NArg() > 1 {
+			n, err := strconv.Atoi(fs.Arg(1))
+			if err != nil || n < 1 {
+				return fmt.Errorf("steps must be a positive number, got %q", fs.Arg(1))
+			}
+			steps = n
+		}
+
+		reverted, err := migrations.Down(ctx, a.db, steps)
+		if err != nil {
+			return err
+		}
+		return a.printMessage("reverted migrations: %v", reverted)
+
+	case "version":
+		version, dirty, err := migrations.Version(ctx, a.db)
+		if err != nil {
+			return err
+		}
+
+		if a.output == outputJSON {
+			return printJSON(map[string]interface{}{"version": version, "dirty": dirty})
+		}
+		return a.printMessage("version %d (dirty: %t)", version, dirty)
+
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown migrate command %q", fs.Arg(0))
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+)
+
+// outputFormat is
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+)
+
+// printJSON writes v as indented JSON to stdout
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(v)
+}
+
+// printSongs prints songs as table or JSON
+func (a *app) printSongs(songs ...*songModel.DTO) error {
+	if a.output == outputJSON {
+		if len(songs) == 1 {
+			return printJSON(songs[0])
+		}
+		return printJSON(songs)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tGROUP\tTITLE\tRELEASE DATE\tLINK")
+	for _, song := range songs {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", song.ID, song.Group, song.Title, song.ReleaseDate, song.Link)
+	}
+
+	return w.Flush()
+}
+
+// printMessage prints result of command without data
+func (a *app) printMessage(format string, args ...interface{}) error {
+	message := fmt.Sprintf(format, args...)
+	if a.output == outputJSON {
+		return printJSON(map[string]string{"message": message})
+	}
+
+	_, err := fmt.Println(message)
+	return err
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/pkg/filter"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+	"github.com/jumayevgadam/music-app/pkg/reqvalidator"
+)
+
+// songFlags registers flags of song fields, used by add and update
+func songFlags(fs *flag.FlagSet, song *songModel.DTO) {
+	fs.StringVar(&song.Group, "group", song.Group, "group (artist) of song")
+	fs.StringVar(&song.Title, "title", song.Title, "title of song")
+	fs.TextVar(&song.ReleaseDate, "release-date", song.ReleaseDate, "release date, e.g. 16.07.2006, 2006-07-16, 2006-07 or 2006")
+	fs.StringVar(&song.Text, "text", song.Text, "lyrics of song")
+	fs.StringVar(&song.Link, "link", song.Link, "link to song")
+}
+
+// songIDArg parses single song id argument
+func songIDArg(fs *flag.FlagSet) (int, error) {
+	if fs.NArg() != 1 {
+		return 0, fmt.Errorf("%s expects song id", fs.Name())
+	}
+
+	songID, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return 0, fmt.Errorf("song id must be a number, got %q", fs.Arg(0))
+	}
+
+	return songID, nil
+}
+
+func addSong(ctx context.Context, a *app, args []string) error {
+	var song songModel.DTO
+
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	songFlags(fs, &song)
+	_ = fs.Parse(args)
+
+	if err := reqvalidator.ValidateStruct(ctx, &song); err != nil {
+		return err
+	}
+
+	songID, err := a.songs.AddSong(ctx, &song)
+	if err != nil {
+		return err
+	}
+	song.ID = songID
+
+	return a.printSongs(&song)
+}
+
+func getSong(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	songID, err := songIDArg(fs)
+	if err != nil {
+		return err
+	}
+
+	song, err := a.songs.GetSong(ctx, songID)
+	if err != nil {
+		return err
+	}
+
+	return a.printSongs(song)
+}
+
+func listSongs(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	rawFilter := fs.String("filter", "", "filter expression, e.g. release_date>=2000-01-01;group~muse")
+	orderBy := fs.String("order-by", "", "sort fields, e.g. -release_date,title")
+	size := fs.String("size", "", "page size")
+	page := fs.String("page", "", "page number")
+	cursor := fs.String("cursor", "", "cursor from previous list")
+	_ = fs.Parse(args)
+
+	songFilter, err := filter.Parse(*rawFilter, music.SongFilterFields)
+	if err != nil {
+		return err
+	}
+
+	paginationQuery := &pagination.PaginationQuery{}
+	if err := paginationQuery.SetSize(*size); err != nil {
+		return err
+	}
+	if err := paginationQuery.SetPage(*page); err != nil {
+		return err
+	}
+	if err := paginationQuery.SetCursor(*cursor); err != nil {
+		return err
+	}
+	paginationQuery.SetOrderBy(*orderBy)
+	if err := paginationQuery.SetSort(music.SongSortFields, music.DefaultSongSort); err != nil {
+		return err
+	}
+
+	list, err := a.songs.ListSongs(ctx, songFilter, paginationQuery)
+	if err != nil {
+		return err
+	}
+
+	if a.output == outputJSON {
+		return printJSON(list)
+	}
+
+	if err := a.printSongs(list.Songs...); err != nil {
+		return err
+	}
+
+	if list.TotalCount > 0 {
+		fmt.Fprintf(os.Stderr, "\npage %d of %d, %d songs\n", list.Page, list.TotalPages, list.TotalCount)
+	}
+	if list.NextCursor != "" {
+		fmt.Fprintf(os.Stderr, "next: -cursor %s\n", list.NextCursor)
+	}
+
+	return nil
+}
+
+func updateSong(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("update", flag.ExitOnError)
+
+	// only given flags change song
+	var (
+		values songModel.DTO
+		patch  songModel.SongPatchDTO
+	)
+	songFlags(fs, &values)
+	version := fs.Int("version", 0, "version song must still have, 0 skips the check")
+	_ = fs.Parse(args)
+
+	songID, err := songIDArg(fs)
+	if err != nil {
+		return err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "group":
+			patch.Group = &values.Group
+		case "title":
+			patch.Title = &values.Title
+		case "release-date":
+			patch.ReleaseDate = &values.ReleaseDate
+		case "text":
+			patch.Text = &values.Text
+		case "link":
+			patch.Link = &values.Link
+		}
+	})
+
+	song, err := a.songs.PatchSong(ctx, songID, *version, &patch)
+	if err != nil {
+		return err
+	}
+
+	return a.printSongs(song)
+}
+
+func deleteSong(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ExitOnError)
+	version := fs.Int("version", 0, "version song must still have, 0 skips the check")
+	_ = fs.Parse(args)
+
+	songID, err := songIDArg(fs)
+	if err != nil {
+		return err
+	}
+
+	if err := a.songs.DeleteSong(ctx, songID, *version); err != nil {
+		return err
+	}
+
+	return a.printMessage("song %d is deleted", songID)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/internal/music/songio"
+	"github.com/jumayevgadam/music-app/pkg/filter"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+)
+
+func importSongs(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "-", "csv or ndjson file, - reads stdin")
+	formatName := fs.String("format", "", "csv or ndjson, default is taken from file extension")
+	dryRun := fs.Bool("dry-run", false, "only validate rows, don't write them")
+	_ = fs.Parse(args)
+
+	if *formatName == "" {
+		*formatName = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+
+	format, err := songio.ParseFormat(*formatName, "")
+	if err != nil {
+		return err
+	}
+
+	var input io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		input = f
+	}
+
+	rows, err := songio.NewReader(format, bufio.NewReader(input))
+	if err != nil {
+		return err
+	}
+
+	report, err := a.songs.ImportSongs(ctx, rows, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if a.output == outputJSON {
+		return printJSON(report)
+	}
+
+	for _, r := range report.Rejected {
+		fmt.Printf("rejected  row %d: %s\n", r.Row, r.Reason)
+	}
+	for _, d := range report.Duplicates {
+		if d.ExistingID != 0 {
+			fmt.Printf("duplicate row %d: %s - %s exists as song %d\n", d.Row, d.Group, d.Title, d.ExistingID)
+		} else {
+			fmt.Printf("duplicate row %d: %s - %s repeats row %d\n", d.Row, d.Group, d.Title, d.DuplicateOfRow)
+		}
+	}
+
+	return a.printMessage("accepted %d, rejected %d, duplicates %d (dry run: %t)",
+		report.Accepted, report.RejectedCount, report.DuplicateCount, report.DryRun)
+}
+
+func exportSongs(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	file := fs.String("out", "-", "output file, - writes to stdout")
+	formatName := fs.String("format", "", "csv, ndjson or json, default is taken from file extension or csv")
+	rawColumns := fs.String("columns", "", "comma separated columns, default is all")
+	rawFilter := fs.String("filter", "", "filter expression, e.g. release_date>=2000-01-01;group~muse")
+	orderBy := fs.String("order-by", "id", "sort fields, e.g. -release_date,title")
+	_ = fs.Parse(args)
+
+	if *formatName == "" {
+		*formatName = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+	if *formatName == "" {
+		*formatName = string(songio.FormatCSV)
+	}
+
+	format, err := songio.ParseFormat(*formatName, "")
+	if err != nil {
+		return err
+	}
+
+	columns, err := songio.ParseColumns(*rawColumns)
+	if err != nil {
+		return err
+	}
+
+	songFilter, err := filter.Parse(*rawFilter, music.SongFilterFields)
+	if err != nil {
+		return err
+	}
+
+	sort, err := pagination.ParseSort(*orderBy, music.SongSortFields, "id")
+	if err != nil {
+		return err
+	}
+
+	var output io.Writer = os.Stdout
+	if *file != "-" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		output = f
+	}
+
+	buffered := bufio.NewWriter(output)
+	writer, err := songio.NewWriter(format, buffered, columns)
+	if err != nil {
+		return err
+	}
+
+	if err := a.songs.ExportSongs(ctx, songFilter, sort, columns, writer); err != nil {
+		return err
+	}
+
+	if format == songio.FormatJSON && *file == "-" {
+		fmt.Fprintln(buffered)
+	}
+
+	return buffered.Flush()
+}
@@ -1,10 +1,29 @@
 package config
 
+import "time"
+
 // Config struct keeps all needed configurations for application
 type Config struct {
-	Postgres Postgres
-	Server   struct {
+	Postgres   Postgres
+	Pagination struct {
+		// CursorSecret signs keyset pagination cursors, old placeholder of .env is refused
+		CursorSecret string `envconfig:"CURSOR_SECRET" validate:"required,ne=change-me-in-production"`
+	}
+	Auth struct {
+		// Tokens are comma separated name:token pairs of API clients, empty disables auth
+		Tokens []string `envconfig:"AUTH_TOKENS"`
+	}
+	Webhook     Webhook
+	Idempotency Idempotency
+	Links       Links
+	LinkCheck   LinkCheck
+	Storage     Storage
+	Plays       Plays
+	Server      struct {
 		HttpPort string `envconfig:"HTTP_PORT" validate:"required"`
+		GrpcPort string `envconfig:"GRPC_PORT" validate:"required"`
+		// ErrorFormat is "problem" for application/problem+json or "legacy" for old clients
+		ErrorFormat string `envconfig:"ERROR_FORMAT" default:"problem" validate:"oneof=problem legacy"`
 	}
 }
 
@@ -16,4 +35,96 @@ type Postgres struct {
 	Password string `envconfig:"DB_PASSWORD" validate:"required"`
 	Name     string `envconfig:"DB_NAME" validate:"required"`
 	SslMode  string `envconfig:"DB_SSLMODE" validate:"required"`
+	// Replicas keeps comma separated DSNs of read replicas, can be empty
+	Replicas []string `envconfig:"DB_REPLICAS"`
+	// ReplicaCheckInterval is how often replicas are pinged for health
+	ReplicaCheckInterval time.Duration `envconfig:"DB_REPLICA_CHECK_INTERVAL" default:"5s"`
+	// ReadYourWritesWindow is how long after its write client reads from primary,
+	// it should be longer than usual replication lag
+	ReadYourWritesWindow time.Duration `envconfig:"DB_READ_YOUR_WRITES_WINDOW" default:"5s"`
+}
+
+// Webhook struct configures dispatcher of song events
+type Webhook struct {
+	// PollInterval is how often outbox and due deliveries are checked
+	PollInterval time.Duration `envconfig:"WEBHOOK_POLL_INTERVAL" default:"2s" validate:"gt=0"`
+	// Timeout limits one delivery request
+	Timeout time.Duration `envconfig:"WEBHOOK_TIMEOUT" default:"10s" validate:"gt=0"`
+	// MaxAttempts is count of attempts after which delivery is dead
+	MaxAttempts int `envconfig:"WEBHOOK_MAX_ATTEMPTS" default:"10" validate:"min=1"`
+	// MinBackoff and MaxBackoff bound exponential delay between attempts
+	MinBackoff time.Duration `envconfig:"WEBHOOK_MIN_BACKOFF" default:"30s" validate:"gt=0"`
+	MaxBackoff time.Duration `envconfig:"WEBHOOK_MAX_BACKOFF" default:"6h" validate:"gtefield=MinBackoff"`
+	// BatchSize is count of events or deliveries taken at once
+	BatchSize int `envconfig:"WEBHOOK_BATCH_SIZE" default:"50" validate:"min=1"`
+	// Concurrency is count of deliveries sent in parallel
+	Concurrency int `envconfig:"WEBHOOK_CONCURRENCY" default:"8" validate:"min=1"`
+}
+
+// Idempotency struct configures Idempotency-Key handling of create requests
+type Idempotency struct {
+	// TTL is how long stored response is replayed for same key
+	TTL time.Duration `envconfig:"IDEMPOTENCY_TTL" default:"24h" validate:"gt=0"`
+	// LockTimeout is after how long key of request which never finished can be taken again
+	LockTimeout time.Duration `envconfig:"IDEMPOTENCY_LOCK_TIMEOUT" default:"1m" validate:"gt=0"`
+	// PurgeInterval is how often expired keys are deleted
+	PurgeInterval time.Duration `envconfig:"IDEMPOTENCY_PURGE_INTERVAL" default:"1h" validate:"gt=0"`
+}
+
+// Links struct configures enricher of song links, empty oEmbed URL disables provider
+type Links struct {
+	// PollInterval is how often songs with new links are checked
+	PollInterval time.Duration `envconfig:"LINKS_POLL_INTERVAL" default:"30s" validate:"gt=0"`
+	// Timeout limits one oEmbed request
+	Timeout time.Duration `envconfig:"LINKS_TIMEOUT" default:"10s" validate:"gt=0"`
+	// BatchSize is count of songs taken at once
+	BatchSize           int    `envconfig:"LINKS_BATCH_SIZE" default:"50" validate:"min=1"`
+	YouTubeOEmbedURL    string `envconfig:"LINKS_YOUTUBE_OEMBED_URL" default:"https://www.youtube.com/oembed"`
+	SoundCloudOEmbedURL string `envconfig:"LINKS_SOUNDCLOUD_OEMBED_URL" default:"https://soundcloud.com/oembed"`
+	SpotifyOEmbedURL    string `envconfig:"LINKS_SPOTIFY_OEMBED_URL" default:"https://open.spotify.com/oembed"`
+	// BandcampOEmbedURL is empty by default, Bandcamp has no public oEmbed endpoint
+	BandcampOEmbedURL string `envconfig:"LINKS_BANDCAMP_OEMBED_URL"`
+}
+
+// LinkCheck struct configures background health checker of song links
+type LinkCheck struct {
+	// Interval is how often every link is checked
+	Interval time.Duration `envconfig:"LINK_CHECK_INTERVAL" default:"24h" validate:"gt=0"`
+	// PollInterval is how often links due for check are looked for
+	PollInterval time.Duration `envconfig:"LINK_CHECK_POLL_INTERVAL" default:"1m" validate:"gt=0"`
+	// Timeout limits one check request
+	Timeout time.Duration `envconfig:"LINK_CHECK_TIMEOUT" default:"10s" validate:"gt=0"`
+	// BatchSize is count of links taken at once
+	BatchSize int `envconfig:"LINK_CHECK_BATCH_SIZE" default:"100" validate:"min=1"`
+	// Concurrency is count of hosts checked in parallel, one host gets one request at a time
+	Concurrency int `envconfig:"LINK_CHECK_CONCURRENCY" default:"8" validate:"min=1"`
+	// HostDelay is pause between requests to the same host
+	HostDelay time.Duration `envconfig:"LINK_CHECK_HOST_DELAY" default:"1s" validate:"gte=0"`
+	// BrokenAfter is count of failed checks in a row after which link is broken
+	BrokenAfter int `envconfig:"LINK_CHECK_BROKEN_AFTER" default:"3" validate:"min=1"`
+}
+
+// Storage struct configures blob store of uploaded files
+type Storage struct {
+	// Dir is root directory of local blob store
+	Dir string `envconfig:"STORAGE_DIR" default:"./data/blobs" validate:"required"`
+	// MaxAudioSize limits uploaded audio file, in bytes
+	MaxAudioSize int64 `envconfig:"STORAGE_MAX_AUDIO_SIZE" default:"104857600" validate:"min=1"`
+	// MaxCoverSize limits uploaded cover image, in bytes
+	MaxCoverSize int64 `envconfig:"STORAGE_MAX_COVER_SIZE" default:"10485760" validate:"min=1"`
+	// URLSecret signs stream URLs given to players without token, old placeholder of .env is refused
+	URLSecret string `envconfig:"STORAGE_URL_SECRET" validate:"required,ne=change-me-in-production"`
+	// SignedURLTTL is default lifetime of signed URL, clients can ask for up to MaxSignedURLTTL
+	SignedURLTTL    time.Duration `envconfig:"STORAGE_SIGNED_URL_TTL" default:"1h" validate:"gt=0"`
+	MaxSignedURLTTL time.Duration `envconfig:"STORAGE_MAX_SIGNED_URL_TTL" default:"24h" validate:"gtefield=SignedURLTTL"`
+}
+
+// Plays struct configures rollups of play events
+type Plays struct {
+	// RollupInterval is how often new plays are added to hourly and daily counts
+	RollupInterval time.Duration `envconfig:"PLAYS_ROLLUP_INTERVAL" default:"1m" validate:"gt=0"`
+	// RollupLag keeps rollup behind now, so plays still being inserted aren't skipped
+	RollupLag time.Duration `envconfig:"PLAYS_ROLLUP_LAG" default:"1m" validate:"gte=0"`
+	// RollupSpan limits period rolled up in one transaction, after downtime rollup catches up by spans
+	RollupSpan time.Duration `envconfig:"PLAYS_ROLLUP_SPAN" default:"6h" validate:"gt=0"`
 }
@@ -3,6 +3,10 @@ package connection
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/jackc/pgx/v5"
@@ -30,11 +34,15 @@ var (
 
 // DB interface for general database operations
 type DB interface {
+	// Primary returns querier of primary, it is passed to Get and Select
+	// of statements which write and return rows, e.g. UPDATE ... RETURNING
+	Primary() Querier
 	Get(ctx context.Context, db Querier, dest interface{}, query string, args ...interface{}) error
 	Select(ctx context.Context, db Querier, dest interface{}, query string, args ...interface{}) error
 	QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row
 	Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error)
 	Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error)
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
 }
 
 // DBops interface for general database operations with Transaction
@@ -44,47 +52,208 @@ type DBops interface {
 	Close() error
 }
 
-// Database struct implementing the DBops interface
+// pool is part of pgxpool.Pool used by Database
+type pool interface {
+	Querier
+	Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error)
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	BeginTx(ctx context.Context, txOpts pgx.TxOptions) (pgx.Tx, error)
+	Ping(ctx context.Context) error
+	Close()
+}
+
+var _ pool = &pgxpool.Pool{}
+
+// Database struct implementing the DBops interface.
+// Writes, QueryRow and transactions always go to the primary pool,
+// Get, Select and Query are sent to a healthy replica when one exists,
+// unless caller passes Primary() as querier or context is marked with WithPrimary.
 type Database struct {
-	db *pgxpool.Pool
+	db       pool
+	replicas []*replica
+	next     atomic.Uint64
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// replica keeps read-only pool with its last known health
+type replica struct {
+	pool    pool
+	healthy atomic.Bool
+}
+
+// primaryKey is context key of WithPrimary
+type primaryKey struct{}
+
+// WithPrimary returns context whose reads are sent to primary as well,
+// it is used where caller must see its own or very recent writes.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// UsesPrimary reports whether ctx was marked with WithPrimary
+func UsesPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
 }
 
 // GetDBClient initializes and returns a new Database instance
 func GetDBClient(ctx context.Context, cfg config.Postgres) (*Database, error) {
-	db, err := pgxpool.New(ctx, fmt.Sprintf(
+	primaryDSN := fmt.Sprintf(
 		"postgres://%s:%s@%s:%s/%s?sslmode=%s",
 		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name, cfg.SslMode,
-	))
+	)
 
+	return NewDatabase(ctx, cfg.ReplicaCheckInterval, primaryDSN, cfg.Replicas...)
+}
+
+// NewDatabase connects to primary and to every given replica DSN.
+// Unreachable replicas don't fail startup, they are marked unhealthy
+// and will be picked up again by health checker when they come back.
+func NewDatabase(ctx context.Context, checkInterval time.Duration, primaryDSN string, replicaDSNs ...string) (*Database, error) {
+	db, err := pgxpool.New(ctx, primaryDSN)
 	if err != nil {
 		return nil, fmt.Errorf("connection.GetDBClient: %w", err)
 	}
 
 	if err := db.Ping(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("connection.GetDBClient.Ping: %w", err)
 	}
 
-	return &Database{db: db}, nil
+	var replicas []pool
+
+	for _, dsn := range replicaDSNs {
+		dsn = strings.TrimSpace(dsn)
+		if dsn == "" {
+			continue
+		}
+
+		replicaPool, err := pgxpool.New(ctx, dsn)
+		if err != nil {
+			for _, r := range replicas {
+				r.Close()
+			}
+			db.Close()
+			return nil, fmt.Errorf("connection.GetDBClient.Replica: %w", err)
+		}
+
+		replicas = append(replicas, replicaPool)
+	}
+
+	return newDatabase(ctx, checkInterval, db, replicas...), nil
+}
+
+// newDatabase wraps connected pools, replicas are pinged once here and
+// then every checkInterval when it is positive
+func newDatabase(ctx context.Context, checkInterval time.Duration, primary pool, replicas ...pool) *Database {
+	d := &Database{db: primary, stop: make(chan struct{})}
+
+	for _, replicaPool := range replicas {
+		r := &replica{pool: replicaPool}
+		r.healthy.Store(replicaPool.Ping(ctx) == nil)
+		d.replicas = append(d.replicas, r)
+	}
+
+	if len(d.replicas) > 0 && checkInterval > 0 {
+		d.wg.Add(1)
+		go d.checkReplicas(checkInterval)
+	}
+
+	return d
+}
+
+// checkReplicas pings every replica periodically and updates its health flag
+func (d *Database) checkReplicas(interval time.Duration) {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+			d.pingReplicas(interval)
+		}
+	}
+}
+
+// pingReplicas updates health flag of every replica, each ping is limited by timeout
+func (d *Database) pingReplicas(timeout time.Duration) {
+	for i, r := range d.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := r.pool.Ping(ctx)
+		cancel()
+
+		if wasHealthy := r.healthy.Swap(err == nil); wasHealthy != (err == nil) {
+			if err != nil {
+				logrus.Warnf("[connection][pingReplicas]: replica %d is unhealthy: %v", i, err)
+			} else {
+				logrus.Infof("[connection][pingReplicas]: replica %d is healthy again", i)
+			}
+		}
+	}
+}
+
+// reader returns next healthy replica in round-robin order, primary otherwise
+// or when ctx was marked with WithPrimary
+func (d *Database) reader(ctx context.Context) Querier {
+	n := uint64(len(d.replicas))
+	if n == 0 || UsesPrimary(ctx) {
+		return d.db
+	}
+
+	start := d.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if r := d.replicas[(start+i)%n]; r.healthy.Load() {
+			return r.pool
+		}
+	}
+
+	return d.db
+}
+
+// querier routes to reader when caller passes Database itself (or nil),
+// any other explicit querier, e.g. Primary(), is respected as it is.
+func (d *Database) querier(ctx context.Context, db Querier) Querier {
+	if db == nil {
+		return d.reader(ctx)
+	}
+
+	if self, ok := db.(*Database); ok && self == d {
+		return d.reader(ctx)
+	}
+
+	return db
+}
+
+// Primary returns primary pool
+func (d *Database) Primary() Querier {
+	return d.db
 }
 
 // Get implements the DB interface
 func (d *Database) Get(ctx context.Context, db Querier, dest interface{}, query string, args ...interface{}) error {
-	return pgxscan.Get(ctx, db, dest, query, args...)
+	return pgxscan.Get(ctx, d.querier(ctx, db), dest, query, args...)
 }
 
 // Select retrieves multiple records and scans them into dest
 func (d *Database) Select(ctx context.Context, db Querier, dest interface{}, query string, args ...interface{}) error {
-	return pgxscan.Select(ctx, db, dest, query, args...)
+	return pgxscan.Select(ctx, d.querier(ctx, db), dest, query, args...)
 }
 
-// QueryRow executes a query expected to return at most one row
+// QueryRow executes a query expected to return at most one row,
+// it stays on primary because it is used for INSERT ... RETURNING as well
 func (d *Database) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
 	return d.db.QueryRow(ctx, query, args...)
 }
 
-// Query executes a query that returns multiple rows
+// Query executes a read-only query that returns multiple rows
 func (d *Database) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
-	return d.db.Query(ctx, query, args...)
+	return d.reader(ctx).Query(ctx, query, args...)
 }
 
 // Exec executes a query that doesn't return rows
@@ -92,28 +261,36 @@ func (d *Database) Exec(ctx context.Context, query string, args ...interface{})
 	return d.db.Exec(ctx, query, args...)
 }
 
-// Begin starts a new transaction
+// CopyFrom bulk inserts rows with COPY protocol
+func (d *Database) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return d.db.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+// Begin starts a new transaction on primary
 func (d *Database) Begin(ctx context.Context, txOpts pgx.TxOptions) (TxOps, error) {
 	if d == nil {
 		return nil, fmt.Errorf("cannot start transaction")
 	}
 
-	c, err := d.db.Acquire(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	tx, err := d.db.BeginTx(ctx, txOpts)
 	if err != nil {
-		c.Release()
 		logrus.Printf("Failed to begin transaction: %v", err)
 		return nil, fmt.Errorf("connection.Database.Begin: %w", err)
 	}
 	return &Transaction{Tx: tx}, nil
 }
 
-// Close closes the database connection pool
+// Close stops health checker and closes primary and replica pools,
+// calls after first one do nothing
 func (d *Database) Close() error {
-	d.db.Close()
+	d.stopOnce.Do(func() {
+		close(d.stop)
+		d.wg.Wait()
+
+		for _, r := range d.replicas {
+			r.pool.Close()
+		}
+		d.db.Close()
+	})
 	return nil
 }
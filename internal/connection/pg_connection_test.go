@@ -0,0 +1,161 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakePool is pool whose ping result can be changed, methods which tests
+// don't expect panic through embedded nil interface
+type fakePool struct {
+	pool
+	name string
+
+	mu      sync.Mutex
+	pingErr error
+	closed  int
+}
+
+func (p *fakePool) Ping(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pingErr
+}
+
+func (p *fakePool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed++
+}
+
+func (p *fakePool) setPingErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pingErr = err
+}
+
+func (p *fakePool) closeCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+var errPing = errors.New("connection refused")
+
+// readers returns how many times each pool was picked by n calls of reader
+func readers(d *Database, ctx context.Context, n int) map[Querier]int {
+	picked := make(map[Querier]int)
+	for i := 0; i < n; i++ {
+		picked[d.reader(ctx)]++
+	}
+	return picked
+}
+
+func TestReaderRoundRobin(t *testing.T) {
+	primary, r1, r2 := &fakePool{name: "primary"}, &fakePool{name: "r1"}, &fakePool{name: "r2"}
+	d := newDatabase(context.Background(), 0, primary, r1, r2)
+	defer d.Close()
+
+	picked := readers(d, context.Background(), 4)
+	if picked[r1] != 2 || picked[r2] != 2 || picked[primary] != 0 {
+		t.Fatalf("picked r1 %d, r2 %d, primary %d times, want 2, 2, 0", picked[r1], picked[r2], picked[primary])
+	}
+}
+
+func TestReaderWithoutReplicas(t *testing.T) {
+	primary := &fakePool{name: "primary"}
+	d := newDatabase(context.Background(), 0, primary)
+	defer d.Close()
+
+	if got := d.reader(context.Background()); got != primary {
+		t.Fatalf("reader = %v, want primary", got)
+	}
+}
+
+func TestReaderSkipsUnhealthyReplicas(t *testing.T) {
+	primary, r1, r2 := &fakePool{name: "primary"}, &fakePool{name: "r1", pingErr: errPing}, &fakePool{name: "r2"}
+	d := newDatabase(context.Background(), 0, primary, r1, r2)
+	defer d.Close()
+
+	if picked := readers(d, context.Background(), 4); picked[r2] != 4 {
+		t.Fatalf("picked %v, want only r2", picked)
+	}
+
+	r2.setPingErr(errPing)
+	d.pingReplicas(time.Second)
+
+	if picked := readers(d, context.Background(), 4); picked[primary] != 4 {
+		t.Fatalf("picked %v, want primary when no replica is healthy", picked)
+	}
+}
+
+func TestPingReplicasRestoresReplica(t *testing.T) {
+	primary, r1, r2 := &fakePool{name: "primary"}, &fakePool{name: "r1"}, &fakePool{name: "r2"}
+	d := newDatabase(context.Background(), 0, primary, r1, r2)
+	defer d.Close()
+
+	r1.setPingErr(errPing)
+	d.pingReplicas(time.Second)
+
+	if picked := readers(d, context.Background(), 4); picked[r2] != 4 {
+		t.Fatalf("picked %v after failed ping of r1, want only r2", picked)
+	}
+
+	r1.setPingErr(nil)
+	d.pingReplicas(time.Second)
+
+	if picked := readers(d, context.Background(), 4); picked[r1] != 2 || picked[r2] != 2 {
+		t.Fatalf("picked %v after r1 came back, want r1 and r2 twice", picked)
+	}
+}
+
+func TestReaderUsesPrimary(t *testing.T) {
+	primary, r1, other := &fakePool{name: "primary"}, &fakePool{name: "r1"}, &fakePool{name: "other"}
+	d := newDatabase(context.Background(), 0, primary, r1)
+	defer d.Close()
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		db   Querier
+		want Querier
+	}{
+		{name: "database itself", ctx: ctx, db: d, want: r1},
+		{name: "nil querier", ctx: ctx, db: nil, want: r1},
+		{name: "primary querier", ctx: ctx, db: d.Primary(), want: primary},
+		{name: "explicit querier", ctx: ctx, db: other, want: other},
+		{name: "marked context", ctx: WithPrimary(ctx), db: d, want: primary},
+		{name: "marked context with explicit querier", ctx: WithPrimary(ctx), db: other, want: other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.querier(tt.ctx, tt.db); got != tt.want {
+				t.Fatalf("querier = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloseTwice(t *testing.T) {
+	primary, r1 := &fakePool{name: "primary"}, &fakePool{name: "r1"}
+	d := newDatabase(context.Background(), time.Millisecond, primary, r1)
+
+	// let health checker ping at least once
+	time.Sleep(5 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		if err := d.Close(); err != nil {
+			t.Fatalf("Close #%d: %v", i+1, err)
+		}
+	}
+
+	if primary.closeCount() != 1 || r1.closeCount() != 1 {
+		t.Fatalf("closed primary %d, replica %d times, want once", primary.closeCount(), r1.closeCount())
+	}
+}
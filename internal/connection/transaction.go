@@ -22,6 +22,11 @@ type Transaction struct {
 	Conn *pgxpool.Conn
 }
 
+// Primary returns transaction itself, it always runs on primary
+func (tx *Transaction) Primary() Querier {
+	return tx.Tx
+}
+
 // Get retrieves a single record and scans it into dest
 func (tx *Transaction) Get(ctx context.Context, db Querier, dest interface{}, query string, args ...interface{}) error {
 	return pgxscan.Get(ctx, tx.Tx, dest, query, args...)
@@ -47,6 +52,11 @@ func (tx *Transaction) Exec(ctx context.Context, query string, args ...interface
 	return tx.Tx.Exec(ctx, query, args...)
 }
 
+// CopyFrom bulk inserts rows with COPY protocol inside the transaction
+func (tx *Transaction) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return tx.Tx.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
 // Commit commits the transaction
 func (tx *Transaction) Commit(ctx context.Context) error {
 	return tx.Tx.Commit(ctx)
@@ -2,7 +2,9 @@ package database
 
 import (
 	"context"
+	"github.com/jumayevgadam/music-app/internal/idempotency"
 	"github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/internal/webhook"
 )
 
 // We want to use clean way implementing 'Transaction' with callback function
@@ -14,4 +16,6 @@ type Transaction func(db DataStore) error
 type DataStore interface {
 	WithTransaction(ctx context.Context, tx Transaction) error
 	SongRepo() music.Repository
+	WebhookRepo() webhook.Repository
+	IdempotencyRepo() idempotency.Repository
 }
@@ -2,14 +2,17 @@ package postgres
 
 import (
 	"context"
-	"fmt"
 	"sync"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jumayevgadam/music-app/internal/connection"
 	"github.com/jumayevgadam/music-app/internal/database"
+	"github.com/jumayevgadam/music-app/internal/idempotency"
+	idempotencyRepository "github.com/jumayevgadam/music-app/internal/idempotency/repository"
 	"github.com/jumayevgadam/music-app/internal/music"
 	musicRepository "github.com/jumayevgadam/music-app/internal/music/repository"
+	"github.com/jumayevgadam/music-app/internal/webhook"
+	webhookRepository "github.com/jumayevgadam/music-app/internal/webhook/repository"
 	"github.com/jumayevgadam/music-app/pkg/errlst"
 	"github.com/sirupsen/logrus"
 )
@@ -18,9 +21,13 @@ var _ database.DataStore = (*DataStore)(nil)
 
 // DataStore is
 type DataStore struct {
-	db        connection.DB
-	music     music.Repository
-	musicInit sync.Once
+	db          connection.DB
+	music       music.Repository
+	musicInit   sync.Once
+	webhook     webhook.Repository
+	webhookInit sync.Once
+	keys        idempotency.Repository
+	keysInit    sync.Once
 }
 
 // NewDataStore is
@@ -37,41 +44,54 @@ func (d *DataStore) SongRepo() music.Repository {
 	return d.music
 }
 
+// WebhookRepo is
+func (d *DataStore) WebhookRepo() webhook.Repository {
+	d.webhookInit.Do(func() {
+		d.webhook = webhookRepository.NewWebhookRepository(d.db)
+	})
+
+	return d.webhook
+}
+
+// IdempotencyRepo is
+func (d *DataStore) IdempotencyRepo() idempotency.Repository {
+	d.keysInit.Do(func() {
+		d.keys = idempotencyRepository.NewIdempotencyRepository(d.db)
+	})
+
+	return d.keys
+}
+
 // WithTransaction method is
 func (d *DataStore) WithTransaction(ctx context.Context, transactionFn database.Transaction) error {
 	db, ok := d.db.(connection.DBops)
 	if !ok {
-		return fmt.Errorf("got error to start transaction")
+		return errlst.NewDomainError(errlst.KindInternal, "nested transactions are not supported", errlst.ErrTransactionFailed)
 	}
 
 	// begin transaction
 	tx, err := db.Begin(ctx, pgx.TxOptions{})
 	if err != nil {
 		logrus.Errorf("db.Begin: %v", err)
-		return errlst.ParseErrors(err)
+		return errlst.FromPostgres(err)
 	}
 
-	defer func() {
-		if err != nil {
-			// RollBack the transaction if an error occured
-			if err = tx.Rollback(ctx); err != nil {
-				logrus.Printf("[postgres][WithTransaction]: failed to rollback transaction: %v", err)
-			}
-			logrus.Errorf("[postgres][WithTransaction]: transaction failed: %v", err)
-		}
-	}()
-
 	// transactionalDB is
 	transactionalDB := &DataStore{db: tx}
 	if err := transactionFn(transactionalDB); err != nil {
-		logrus.Println("[postgres][WithTransaction]: transactionFn: %w", err)
-		return errlst.ParseErrors(err)
+		// RollBack the transaction if an error occured
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			logrus.Printf("[postgres][WithTransaction]: failed to rollback transaction: %v", rbErr)
+		}
+		logrus.Errorf("[postgres][WithTransaction]: transaction failed: %v", err)
+
+		return errlst.FromPostgres(err)
 	}
 
 	// Commit the transaction if no error occurred during the transactionFn execution
 	if err := tx.Commit(ctx); err != nil {
-		logrus.Println("[postgres][WithTransaction]: tx.Commit: %w", err)
-		return errlst.ParseErrors(err)
+		logrus.Errorf("[postgres][WithTransaction]: tx.Commit: %v", err)
+		return errlst.FromPostgres(err)
 	}
 
 	return nil
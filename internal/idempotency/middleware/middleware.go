@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jumayevgadam/music-app/internal/config"
+	"github.com/jumayevgadam/music-app/internal/database"
+	idempotencyModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/pkg/auth"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Middleware makes retried requests with the same Idempotency-Key safe. First
+// request claims the key, its response is stored and replayed for every retry
+// till key expires. Keys are scoped by actor, so clients can't read each other's
+// responses. Requests without the header are passed as they are.
+
+const (
+	// HeaderIdempotencyKey is
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set to "true" on stored responses
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// maxKeyLength is length of key column
+	maxKeyLength = 255
+	// maxBodySize limits body kept in memory for hashing
+	maxBodySize = 1 << 20
+)
+
+// Middleware struct is
+type Middleware struct {
+	dataStore database.DataStore
+	cfg       config.Idempotency
+}
+
+// NewMiddleware method is
+func NewMiddleware(dataStore database.DataStore, cfg config.Idempotency) *Middleware {
+	return &Middleware{dataStore: dataStore, cfg: cfg}
+}
+
+// Handle is echo middleware of idempotent endpoint
+func (m *Middleware) Handle(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return next(c)
+		}
+
+		if len(key) > maxKeyLength {
+			return errlst.Write(c, errlst.NewBadRequestError("Idempotency-Key must be at most "+strconv.Itoa(maxKeyLength)+" characters"))
+		}
+
+		requestHash, err := hashRequest(c)
+		if err != nil {
+			return errlst.Write(c, err)
+		}
+
+		ctx := c.Request().Context()
+		stored, err := m.dataStore.IdempotencyRepo().ClaimKey(ctx, &idempotencyModel.IdempotencyKeyDAO{
+			Scope:       auth.ActorFromContext(ctx),
+			Key:         key,
+			RequestHash: requestHash,
+		}, m.cfg.TTL, m.cfg.LockTimeout)
+		if err != nil {
+			return errlst.Write(c, err)
+		}
+
+		if !stored.Claimed {
+			return replay(c, stored, requestHash)
+		}
+
+		return m.run(c, next, stored)
+	}
+}
+
+// replay writes stored response of key
+func replay(c echo.Context, stored *idempotencyModel.IdempotencyKeyDAO, requestHash string) error {
+	if stored.RequestHash != requestHash {
+		return errlst.Write(c, errlst.NewUnprocessableEntityError("Idempotency-Key was already used with another request"))
+	}
+
+	if !stored.Completed() {
+		return errlst.Write(c, errlst.NewConflictError("request with this Idempotency-Key is in progress"))
+	}
+
+	c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	contentType := echo.MIMEApplicationJSON
+	if stored.ContentType != nil {
+		contentType = *stored.ContentType
+	}
+
+	return c.Blob(*stored.StatusCode, contentType, stored.ResponseBody)
+}
+
+// run calls handler of claimed key and stores its response. Server errors
+// aren't stored, key is released so client can retry.
+func (m *Middleware) run(c echo.Context, next echo.HandlerFunc, stored *idempotencyModel.IdempotencyKeyDAO) error {
+	response := c.Response()
+	recorder := &responseRecorder{ResponseWriter: response.Writer}
+	response.Writer = recorder
+
+	err := next(c)
+	response.Writer = recorder.ResponseWriter
+
+	// key must be saved or released even if client went away
+	ctx := context.WithoutCancel(c.Request().Context())
+	if err != nil || !response.Committed || response.Status >= http.StatusInternalServerError {
+		if releaseErr := m.dataStore.IdempotencyRepo().ReleaseKey(ctx, stored.Scope, stored.Key); releaseErr != nil {
+			logrus.Errorf("[idempotency][ReleaseKey]: %v", releaseErr)
+		}
+		return err
+	}
+
+	status := response.Status
+	contentType := response.Header().Get(echo.HeaderContentType)
+	stored.StatusCode, stored.ContentType, stored.ResponseBody = &status, &contentType, recorder.body.Bytes()
+
+	if err := m.dataStore.IdempotencyRepo().SaveResponse(ctx, stored); err != nil {
+		// response is already sent, retry will wait for lock timeout and run again
+		logrus.Errorf("[idempotency][SaveResponse]: %v", err)
+	}
+
+	return nil
+}
+
+// PurgeExpired deletes expired keys every purge interval till ctx is done
+func (m *Middleware) PurgeExpired(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deleted, err := m.dataStore.IdempotencyRepo().DeleteExpiredKeys(ctx)
+		if err != nil {
+			logrus.Errorf("[idempotency][DeleteExpiredKeys]: %v", err)
+			continue
+		}
+		if deleted > 0 {
+			logrus.Infof("[idempotency][PurgeExpired]: deleted %d expired keys", deleted)
+		}
+	}
+}
+
+// hashRequest returns hex sha256 of method, path and body, body is put back for handler
+func hashRequest(c echo.Context) (string, error) {
+	request := c.Request()
+
+	body, err := io.ReadAll(io.LimitReader(request.Body, maxBodySize+1))
+	if err != nil {
+		return "", errlst.NewBadRequestError("can't read request body")
+	}
+	if len(body) > maxBodySize {
+		return "", errlst.NewRestError(http.StatusRequestEntityTooLarge, "request entity too large", "body of idempotent request must be at most 1MiB")
+	}
+	request.Body = io.NopCloser(bytes.NewReader(body))
+
+	hash := sha256.New()
+	hash.Write([]byte(request.Method + " " + request.URL.Path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// responseRecorder copies response body while it is written
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write is
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush is
+func (r *responseRecorder) Flush() {
+	http.NewResponseController(r.ResponseWriter).Flush()
+}
@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jumayevgadam/music-app/internal/config"
+	"github.com/jumayevgadam/music-app/internal/database"
+	"github.com/jumayevgadam/music-app/internal/idempotency"
+	idempotencyModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/pkg/auth"
+	"github.com/labstack/echo/v4"
+)
+
+// fakeStore is DataStore with in-memory keys, methods tests don't
+// need panic through nil embedded interface
+type fakeStore struct {
+	database.DataStore
+	keys *fakeKeyRepo
+}
+
+func (f *fakeStore) IdempotencyRepo() idempotency.Repository {
+	return f.keys
+}
+
+// fakeKeyRepo keeps keys by scope and key
+type fakeKeyRepo struct {
+	idempotency.Repository
+	keys map[[2]string]*idempotencyModel.IdempotencyKeyDAO
+}
+
+func (r *fakeKeyRepo) ClaimKey(
+	ctx context.Context,
+	daoModel *idempotencyModel.IdempotencyKeyDAO,
+	ttl, lockTimeout time.Duration,
+) (*idempotencyModel.IdempotencyKeyDAO, error) {
+	id := [2]string{daoModel.Scope, daoModel.Key}
+
+	if stored, ok := r.keys[id]; ok {
+		held := *stored
+		held.Claimed = false
+		return &held, nil
+	}
+
+	claimed := *daoModel
+	r.keys[id] = &claimed
+
+	held := claimed
+	held.Claimed = true
+	return &held, nil
+}
+
+func (r *fakeKeyRepo) SaveResponse(ctx context.Context, daoModel *idempotencyModel.IdempotencyKeyDAO) error {
+	stored := *daoModel
+	r.keys[[2]string{daoModel.Scope, daoModel.Key}] = &stored
+
+	return nil
+}
+
+func (r *fakeKeyRepo) ReleaseKey(ctx context.Context, scope, key string) error {
+	if stored, ok := r.keys[[2]string{scope, key}]; ok && !stored.Completed() {
+		delete(r.keys, [2]string{scope, key})
+	}
+
+	return nil
+}
+
+// testEndpoint is handler behind middleware counting its calls
+type testEndpoint struct {
+	calls  int
+	status int
+}
+
+func newTestEndpoint(t *testing.T) (*testEndpoint, *fakeKeyRepo, echo.HandlerFunc) {
+	t.Helper()
+
+	keys := &fakeKeyRepo{keys: make(map[[2]string]*idempotencyModel.IdempotencyKeyDAO)}
+	endpoint := &testEndpoint{status: http.StatusCreated}
+
+	handler := NewMiddleware(&fakeStore{keys: keys}, config.Idempotency{TTL: time.Hour, LockTimeout: time.Minute}).
+		Handle(func(c echo.Context) error {
+			endpoint.calls++
+			return c.JSON(endpoint.status, map[string]int{"id": endpoint.calls})
+		})
+
+	return endpoint, keys, handler
+}
+
+// post sends body with Idempotency-Key as actor
+func post(t *testing.T, handler echo.HandlerFunc, actor, key, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/song/create", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	if actor != "" {
+		req = req.WithContext(auth.WithActor(req.Context(), actor))
+	}
+
+	rec := httptest.NewRecorder()
+	e := echo.New()
+	c := e.NewContext(req, rec)
+
+	if err := handler(c); err != nil {
+		e.HTTPErrorHandler(err, c)
+	}
+
+	return rec
+}
+
+func TestReplay(t *testing.T) {
+	endpoint, _, handler := newTestEndpoint(t)
+
+	first := post(t, handler, "editor", "key-1", `{"title":"Hysteria"}`)
+	if first.Code != http.StatusCreated || first.Header().Get(HeaderIdempotentReplayed) != "" {
+		t.Fatalf("first request = %d, replayed %q", first.Code, first.Header().Get(HeaderIdempotentReplayed))
+	}
+
+	retry := post(t, handler, "editor", "key-1", `{"title":"Hysteria"}`)
+	if retry.Code != http.StatusCreated || retry.Header().Get(HeaderIdempotentReplayed) != "true" ||
+		retry.Body.String() != first.Body.String() ||
+		retry.Header().Get(echo.HeaderContentType) != first.Header().Get(echo.HeaderContentType) {
+		t.Fatalf("retry = %d %q %s, want replay of %s", retry.Code, retry.Header(), retry.Body, first.Body)
+	}
+
+	if endpoint.calls != 1 {
+		t.Fatalf("handler called %d times, want 1", endpoint.calls)
+	}
+}
+
+func TestKeysAreScopedByActor(t *testing.T) {
+	endpoint, _, handler := newTestEndpoint(t)
+
+	post(t, handler, "editor", "key-1", `{}`)
+	if rec := post(t, handler, "other", "key-1", `{}`); rec.Header().Get(HeaderIdempotentReplayed) != "" {
+		t.Fatal("response of editor was replayed to other actor")
+	}
+
+	if endpoint.calls != 2 {
+		t.Fatalf("handler called %d times, want 2", endpoint.calls)
+	}
+}
+
+func TestBodyMismatch(t *testing.T) {
+	endpoint, _, handler := newTestEndpoint(t)
+
+	post(t, handler, "", "key-1", `{"title":"Hysteria"}`)
+	rec := post(t, handler, "", "key-1", `{"title":"Uprising"}`)
+
+	if rec.Code != http.StatusUnprocessableEntity || endpoint.calls != 1 {
+		t.Fatalf("reused key = %d after %d calls, want 422 after 1", rec.Code, endpoint.calls)
+	}
+}
+
+func TestInProgress(t *testing.T) {
+	endpoint, keys, handler := newTestEndpoint(t)
+
+	// key is claimed by request which didn't finish yet
+	keys.keys[[2]string{"", "key-1"}] = &idempotencyModel.IdempotencyKeyDAO{
+		Key:         "key-1",
+		RequestHash: hashOf(t, `{}`),
+	}
+
+	if rec := post(t, handler, "", "key-1", `{}`); rec.Code != http.StatusConflict || endpoint.calls != 0 {
+		t.Fatalf("request of key in progress = %d after %d calls, want 409 without call", rec.Code, endpoint.calls)
+	}
+}
+
+func TestServerErrorReleasesKey(t *testing.T) {
+	endpoint, keys, handler := newTestEndpoint(t)
+
+	endpoint.status = http.StatusServiceUnavailable
+	if rec := post(t, handler, "", "key-1", `{}`); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("first request = %d, want 503", rec.Code)
+	}
+	if len(keys.keys) != 0 {
+		t.Fatalf("key of failed request is kept: %v", keys.keys)
+	}
+
+	// retry runs handler again and its response is stored
+	endpoint.status = http.StatusCreated
+	if rec := post(t, handler, "", "key-1", `{}`); rec.Code != http.StatusCreated || rec.Header().Get(HeaderIdempotentReplayed) != "" {
+		t.Fatalf("retry = %d, want new 201", rec.Code)
+	}
+	if rec := post(t, handler, "", "key-1", `{}`); rec.Header().Get(HeaderIdempotentReplayed) != "true" || endpoint.calls != 2 {
+		t.Fatalf("second retry replayed %q after %d calls", rec.Header().Get(HeaderIdempotentReplayed), endpoint.calls)
+	}
+}
+
+func TestClientErrorIsStored(t *testing.T) {
+	endpoint, _, handler := newTestEndpoint(t)
+	endpoint.status = http.StatusBadRequest
+
+	post(t, handler, "", "key-1", `{}`)
+	rec := post(t, handler, "", "key-1", `{}`)
+
+	if rec.Code != http.StatusBadRequest || rec.Header().Get(HeaderIdempotentReplayed) != "true" || endpoint.calls != 1 {
+		t.Fatalf("retry of 400 = %d, replayed %q after %d calls", rec.Code, rec.Header().Get(HeaderIdempotentReplayed), endpoint.calls)
+	}
+}
+
+func TestWithoutKey(t *testing.T) {
+	endpoint, keys, handler := newTestEndpoint(t)
+
+	post(t, handler, "", "", `{}`)
+	post(t, handler, "", "", `{}`)
+
+	if endpoint.calls != 2 || len(keys.keys) != 0 {
+		t.Fatalf("requests without key: %d calls, %d keys", endpoint.calls, len(keys.keys))
+	}
+}
+
+func TestKeyTooLong(t *testing.T) {
+	endpoint, _, handler := newTestEndpoint(t)
+
+	rec := post(t, handler, "", strings.Repeat("k", maxKeyLength+1), `{}`)
+	if rec.Code != http.StatusBadRequest || endpoint.calls != 0 {
+		t.Fatalf("too long key = %d after %d calls, want 400", rec.Code, endpoint.calls)
+	}
+}
+
+// hashOf returns request hash of POST /song/create with body
+func hashOf(t *testing.T, body string) string {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/song/create", strings.NewReader(body))
+	hash, err := hashRequest(echo.New().NewContext(req, httptest.NewRecorder()))
+	if err != nil {
+		t.Fatalf("hashRequest: %v", err)
+	}
+
+	return hash
+}
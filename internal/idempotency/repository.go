@@ -0,0 +1,18 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	idempotencyModel "github.com/jumayevgadam/music-app/internal/models"
+)
+
+// write needed methods for repository layer
+
+// Repository is
+type Repository interface {
+	ClaimKey(ctx context.Context, daoModel *idempotencyModel.IdempotencyKeyDAO, ttl, lockTimeout time.Duration) (*idempotencyModel.IdempotencyKeyDAO, error)
+	SaveResponse(ctx context.Context, daoModel *idempotencyModel.IdempotencyKeyDAO) error
+	ReleaseKey(ctx context.Context, scope, key string) error
+	DeleteExpiredKeys(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jumayevgadam/music-app/internal/connection"
+	idempotencyModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+)
+
+// IdempotencyRepository struct is
+type IdempotencyRepository struct {
+	psqlDB connection.DB
+}
+
+// NewIdempotencyRepository method is
+func NewIdempotencyRepository(psqlDB connection.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{psqlDB: psqlDB}
+}
+
+// ClaimKey repo stores key for request or returns row of request already holding it.
+// It reads with QueryRow, so key written a moment ago isn't missed on lagging replica.
+func (ir *IdempotencyRepository) ClaimKey(
+	ctx context.Context,
+	daoModel *idempotencyModel.IdempotencyKeyDAO,
+	ttl, lockTimeout time.Duration,
+) (*idempotencyModel.IdempotencyKeyDAO, error) {
+	var stored idempotencyModel.IdempotencyKeyDAO
+
+	err := ir.psqlDB.QueryRow(
+		ctx,
+		claimKeyQuery,
+		daoModel.Scope,
+		daoModel.Key,
+		daoModel.RequestHash,
+		ttl.Seconds(),
+		lockTimeout.Seconds(),
+	).Scan(
+		&stored.Scope,
+		&stored.Key,
+		&stored.RequestHash,
+		&stored.StatusCode,
+		&stored.ContentType,
+		&stored.ResponseBody,
+		&stored.Claimed,
+	)
+	// other request inserted key after snapshot of statement was taken
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errlst.Conflict("request with this idempotency key is in progress", errlst.ErrConflict)
+	}
+	if err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return &stored, nil
+}
+
+// SaveResponse repo is
+func (ir *IdempotencyRepository) SaveResponse(ctx context.Context, daoModel *idempotencyModel.IdempotencyKeyDAO) error {
+	if _, err := ir.psqlDB.Exec(
+		ctx,
+		saveResponseQuery,
+		daoModel.Scope,
+		daoModel.Key,
+		daoModel.StatusCode,
+		daoModel.ContentType,
+		daoModel.ResponseBody,
+	); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// ReleaseKey repo is
+func (ir *IdempotencyRepository) ReleaseKey(ctx context.Context, scope, key string) error {
+	if _, err := ir.psqlDB.Exec(ctx, releaseKeyQuery, scope, key); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredKeys repo returns count of deleted keys
+func (ir *IdempotencyRepository) DeleteExpiredKeys(ctx context.Context) (int64, error) {
+	tag, err := ir.psqlDB.Exec(ctx, deleteExpiredKeysQuery)
+	if err != nil {
+		return -1, errlst.FromPostgres(err)
+	}
+
+	return tag.RowsAffected(), nil
+}
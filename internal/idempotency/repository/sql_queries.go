@@ -0,0 +1,44 @@
+package repository
+
+const (
+	// claimKeyQuery inserts key for request, expired key and key abandoned in progress
+	// longer than $5 seconds are taken over. When key is held by other request its
+	// stored row is returned with claimed = false.
+	claimKeyQuery = `
+		WITH claimed AS (
+			INSERT INTO idempotency_keys (scope, key, request_hash, expires_at)
+			VALUES ($1, $2, $3, now() + make_interval(secs => $4))
+			ON CONFLICT (scope, key) DO UPDATE
+			SET request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = NULL,
+				response_body = NULL, created_at = now(), expires_at = EXCLUDED.expires_at
+			WHERE idempotency_keys.expires_at < now()
+				OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < now() - make_interval(secs => $5))
+			RETURNING scope, key, request_hash, status_code, content_type, response_body
+		)
+		SELECT scope, key, request_hash, status_code, content_type, response_body, TRUE AS claimed
+		FROM claimed
+		UNION ALL
+		SELECT scope, key, request_hash, status_code, content_type, response_body, FALSE AS claimed
+		FROM idempotency_keys
+		WHERE scope = $1 AND key = $2 AND NOT EXISTS (SELECT 1 FROM claimed);
+	`
+
+	// saveResponseQuery is
+	saveResponseQuery = `
+		UPDATE idempotency_keys
+		SET status_code = $3, content_type = $4, response_body = $5
+		WHERE scope = $1 AND key = $2;
+	`
+
+	// releaseKeyQuery drops key of failed request, so it can be retried
+	releaseKeyQuery = `
+		DELETE FROM idempotency_keys
+		WHERE scope = $1 AND key = $2 AND status_code IS NULL;
+	`
+
+	// deleteExpiredKeysQuery is
+	deleteExpiredKeysQuery = `
+		DELETE FROM idempotency_keys
+		WHERE expires_at < now();
+	`
+)
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS song_history;
\ No newline at end of file
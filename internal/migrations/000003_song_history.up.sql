@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS song_history (
+    id BIGSERIAL PRIMARY KEY,
+    song_id INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('created', 'updated', 'deleted', 'restored')),
+    actor VARCHAR(255) NOT NULL,
+    diff JSONB NOT NULL DEFAULT '{}',
+    snapshot JSONB NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS song_history_song_id_idx ON song_history (song_id, id);
@@ -0,0 +1,12 @@
+-- merged entries are kept, old check is added without validating them
+ALTER TABLE song_history DROP CONSTRAINT IF EXISTS song_history_action_check;
+ALTER TABLE song_history ADD CONSTRAINT song_history_action_check
+    CHECK (action IN ('created', 'updated', 'deleted', 'restored')) NOT VALID;
+
+ALTER TABLE song_history
+    DROP COLUMN IF EXISTS merged_from,
+    DROP COLUMN IF EXISTS merged_into;
+
+DROP INDEX IF EXISTS songs_link_idx;
+DROP INDEX IF EXISTS songs_key_idx;
+DROP INDEX IF EXISTS songs_text_trgm_idx;
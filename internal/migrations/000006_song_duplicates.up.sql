@@ -0,0 +1,13 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS songs_text_trgm_idx ON songs USING gin (text gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS songs_key_idx ON songs (lower(trim("group")), lower(trim(title)));
+CREATE INDEX IF NOT EXISTS songs_link_idx ON songs (lower(trim(link)));
+
+ALTER TABLE song_history
+    ADD COLUMN IF NOT EXISTS merged_into INTEGER,
+    ADD COLUMN IF NOT EXISTS merged_from INTEGER[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE song_history DROP CONSTRAINT IF EXISTS song_history_action_check;
+ALTER TABLE song_history ADD CONSTRAINT song_history_action_check
+    CHECK (action IN ('created', 'updated', 'deleted', 'restored', 'merged'));
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS songs_link_provider_idx;
+DROP INDEX IF EXISTS songs_link_unenriched_idx;
+
+ALTER TABLE songs
+    DROP COLUMN IF EXISTS link_enriched_at,
+    DROP COLUMN IF EXISTS link_thumbnail_url,
+    DROP COLUMN IF EXISTS link_duration,
+    DROP COLUMN IF EXISTS link_id,
+    DROP COLUMN IF EXISTS link_provider;
@@ -0,0 +1,10 @@
+-- existing songs are classified by link enricher, it picks every song without link_enriched_at
+ALTER TABLE songs
+    ADD COLUMN IF NOT EXISTS link_provider VARCHAR(20) NOT NULL DEFAULT 'generic',
+    ADD COLUMN IF NOT EXISTS link_id VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS link_duration INTEGER,
+    ADD COLUMN IF NOT EXISTS link_thumbnail_url VARCHAR(2048),
+    ADD COLUMN IF NOT EXISTS link_enriched_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS songs_link_unenriched_idx ON songs (id) WHERE link_enriched_at IS NULL;
+CREATE INDEX IF NOT EXISTS songs_link_provider_idx ON songs (link_provider, link_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS songs_link_status_idx;
+DROP INDEX IF EXISTS songs_link_checked_at_idx;
+
+ALTER TABLE songs
+    DROP COLUMN IF EXISTS link_checked_at,
+    DROP COLUMN IF EXISTS link_failures,
+    DROP COLUMN IF EXISTS link_status_code,
+    DROP COLUMN IF EXISTS link_status;
@@ -0,0 +1,9 @@
+ALTER TABLE songs
+    ADD COLUMN IF NOT EXISTS link_status VARCHAR(20) NOT NULL DEFAULT 'unchecked'
+        CHECK (link_status IN ('unchecked', 'ok', 'failing', 'broken')),
+    ADD COLUMN IF NOT EXISTS link_status_code INTEGER,
+    ADD COLUMN IF NOT EXISTS link_failures INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS link_checked_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS songs_link_checked_at_idx ON songs (link_checked_at NULLS FIRST, id);
+CREATE INDEX IF NOT EXISTS songs_link_status_idx ON songs (link_status);
@@ -0,0 +1,5 @@
+ALTER TABLE songs
+    ALTER COLUMN created_at DROP NOT NULL,
+    ALTER COLUMN updated_at DROP NOT NULL;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS release_date_precision;
@@ -0,0 +1,19 @@
+ALTER TABLE songs
+    ADD COLUMN IF NOT EXISTS release_date_precision VARCHAR(10) NOT NULL DEFAULT 'day'
+        CHECK (release_date_precision IN ('day', 'month', 'year'));
+
+UPDATE songs SET created_at = COALESCE(created_at, now()), updated_at = COALESCE(updated_at, created_at, now())
+WHERE created_at IS NULL OR updated_at IS NULL;
+
+ALTER TABLE songs
+    ALTER COLUMN created_at SET NOT NULL,
+    ALTER COLUMN updated_at SET NOT NULL;
+
+-- snapshots stored timestamps as PostgreSQL text, they are rewritten in RFC 3339
+UPDATE song_history
+SET snapshot = snapshot || jsonb_build_object(
+    'createdAt', to_jsonb(NULLIF(snapshot->>'createdAt', '')::timestamptz),
+    'updatedAt', to_jsonb(NULLIF(snapshot->>'updatedAt', '')::timestamptz),
+    'link_checked_at', to_jsonb(NULLIF(snapshot->>'link_checked_at', '')::timestamptz)
+)
+WHERE jsonb_typeof(snapshot) = 'object';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS song_lyric_lines;
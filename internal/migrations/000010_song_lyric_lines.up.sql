@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS song_lyric_lines (
+    song_id INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    time_ms BIGINT NOT NULL CHECK (time_ms >= 0),
+    text TEXT NOT NULL,
+    PRIMARY KEY (song_id, position)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS song_lyric_variants (
+    song_id INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    language VARCHAR(35) NOT NULL,
+    text TEXT NOT NULL,
+    original BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (song_id, language)
+);
+
+-- only one variant of song is original, its text is kept in songs.text
+CREATE UNIQUE INDEX IF NOT EXISTS song_lyric_variants_original_idx ON song_lyric_variants (song_id) WHERE original;
+CREATE INDEX IF NOT EXISTS song_lyric_variants_text_trgm_idx ON song_lyric_variants USING gin (text gin_trgm_ops);
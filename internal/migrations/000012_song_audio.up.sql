@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS song_audio (
+    song_id INTEGER PRIMARY KEY REFERENCES songs (id) ON DELETE CASCADE,
+    -- blob_key is content addressed, songs uploading same file share blob
+    blob_key TEXT NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    sha256 CHAR(64) NOT NULL,
+    filename TEXT NOT NULL DEFAULT '',
+    -- tags are read from file when it is uploaded, empty when file has none
+    tag_format VARCHAR(10) NOT NULL DEFAULT '',
+    tag_title TEXT NOT NULL DEFAULT '',
+    tag_artist TEXT NOT NULL DEFAULT '',
+    tag_album TEXT NOT NULL DEFAULT '',
+    tag_date TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS song_audio_blob_key_idx ON song_audio (blob_key);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS song_play_rollups;
+DROP TABLE IF EXISTS song_plays_daily;
+DROP TABLE IF EXISTS song_plays_hourly;
+DROP TABLE IF EXISTS song_plays;
@@ -0,0 +1,39 @@
+-- song_plays is append-only log of play events, charts read rollups of it
+CREATE TABLE IF NOT EXISTS song_plays (
+    id BIGSERIAL PRIMARY KEY,
+    song_id INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    played_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    duration_ms INTEGER CHECK (duration_ms >= 0),
+    source VARCHAR(50) NOT NULL DEFAULT '',
+    actor TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS song_plays_played_at_idx ON song_plays (played_at);
+CREATE INDEX IF NOT EXISTS song_plays_song_id_idx ON song_plays (song_id);
+
+CREATE TABLE IF NOT EXISTS song_plays_hourly (
+    song_id INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    hour TIMESTAMP WITH TIME ZONE NOT NULL,
+    plays BIGINT NOT NULL CHECK (plays > 0),
+    PRIMARY KEY (song_id, hour)
+);
+
+CREATE INDEX IF NOT EXISTS song_plays_hourly_hour_idx ON song_plays_hourly (hour);
+
+-- day is UTC date
+CREATE TABLE IF NOT EXISTS song_plays_daily (
+    song_id INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    plays BIGINT NOT NULL CHECK (plays > 0),
+    PRIMARY KEY (song_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS song_plays_daily_day_idx ON song_plays_daily (day);
+
+-- rolled_until is end of period already added to rollups, plays before it are counted
+CREATE TABLE IF NOT EXISTS song_play_rollups (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    rolled_until TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+INSERT INTO song_play_rollups (rolled_until) VALUES (CURRENT_TIMESTAMP) ON CONFLICT DO NOTHING;
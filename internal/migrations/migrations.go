@@ -0,0 +1,219 @@
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jumayevgadam/music-app/internal/connection"
+)
+
+// Migrations are embedded in binaries, so musicctl can run them without
+// migrate CLI. Version is kept in schema_migrations table of same shape
+// migrate CLI uses, both tools can be used on the same database.
+
+//go:embed *.sql
+var files embed.FS
+
+const (
+	createVersionTableQuery = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			dirty BOOLEAN NOT NULL
+		);
+	`
+	getVersionQuery = `
+		SELECT version, dirty FROM schema_migrations LIMIT 1;
+	`
+	deleteVersionQuery = `
+		DELETE FROM schema_migrations;
+	`
+	setVersionQuery = `
+		INSERT INTO schema_migrations (version, dirty) VALUES ($1, false);
+	`
+)
+
+// ErrDirty is returned when previous migration failed half way
+var ErrDirty = errors.New("database is dirty, fix it and force version with migrate CLI")
+
+// Migration is pair of up and down scripts of one version
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// List returns embedded migrations sorted by version
+func List() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrations.List: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		// file names are like 000001_songs.up.sql
+		prefix, rest, ok := strings.Cut(entry.Name(), "_")
+		if !ok {
+			continue
+		}
+
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrations.List: bad version of %s: %w", entry.Name(), err)
+		}
+
+		content, err := files.ReadFile(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrations.List: %w", err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version}
+			byVersion[version] = m
+		}
+
+		switch {
+		case strings.HasSuffix(rest, ".up.sql"):
+			m.Name, m.Up = strings.TrimSuffix(rest, ".up.sql"), string(content)
+		case strings.HasSuffix(rest, ".down.sql"):
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return int(a.Version - b.Version)
+	})
+
+	return migrations, nil
+}
+
+// Version returns current version of database, 0 when nothing is applied
+func Version(ctx context.Context, db connection.DBops) (int64, bool, error) {
+	if _, err := db.Exec(ctx, createVersionTableQuery); err != nil {
+		return 0, false, fmt.Errorf("migrations.Version: %w", err)
+	}
+
+	var (
+		version int64
+		dirty   bool
+	)
+
+	err := db.QueryRow(ctx, getVersionQuery).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("migrations.Version: %w", err)
+	}
+
+	return version, dirty, nil
+}
+
+// Up applies every migration newer than current version, each in own transaction.
+// It returns versions which were applied.
+func Up(ctx context.Context, db connection.DBops) ([]int64, error) {
+	current, dirty, err := Version(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, ErrDirty
+	}
+
+	migrations, err := List()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []int64
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+
+		if err := apply(ctx, db, m.Up, m.Version); err != nil {
+			return applied, fmt.Errorf("migrations.Up: %d_%s: %w", m.Version, m.Name, err)
+		}
+		applied = append(applied, m.Version)
+	}
+
+	return applied, nil
+}
+
+// Down reverts last steps migrations, it returns versions which were reverted.
+func Down(ctx context.Context, db connection.DBops, steps int) ([]int64, error) {
+	current, dirty, err := Version(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, ErrDirty
+	}
+
+	migrations, err := List()
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []int64
+	for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+		m := migrations[i]
+		if m.Version > current {
+			continue
+		}
+
+		// version after revert is previous migration, 0 when it was the first one
+		var previous int64
+		if i > 0 {
+			previous = migrations[i-1].Version
+		}
+
+		if err := apply(ctx, db, m.Down, previous); err != nil {
+			return reverted, fmt.Errorf("migrations.Down: %d_%s: %w", m.Version, m.Name, err)
+		}
+		reverted = append(reverted, m.Version)
+	}
+
+	return reverted, nil
+}
+
+// apply runs script and stores version in one transaction, version 0 clears table
+func apply(ctx context.Context, db connection.DBops, script string, version int64) error {
+	tx, err := db.Begin(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+
+	// script is run without args, so multiple statements are allowed
+	if _, err := tx.Exec(ctx, script); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, deleteVersionQuery); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+
+	if version > 0 {
+		if _, err := tx.Exec(ctx, setVersionQuery, version); err != nil {
+			_ = tx.Rollback(ctx)
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
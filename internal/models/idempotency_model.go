@@ -0,0 +1,20 @@
+package models
+
+// IdempotencyKeyDAO is request stored under Idempotency-Key, response fields
+// stay empty while request is in progress
+type IdempotencyKeyDAO struct {
+	// Scope separates keys of different clients, it is actor of request
+	Scope        string  `db:"scope"`
+	Key          string  `db:"key"`
+	RequestHash  string  `db:"request_hash"`
+	StatusCode   *int    `db:"status_code"`
+	ContentType  *string `db:"content_type"`
+	ResponseBody []byte  `db:"response_body"`
+	// Claimed is true when request of caller owns the key
+	Claimed bool `db:"claimed"`
+}
+
+// Completed reports whether response of key is stored
+func (k *IdempotencyKeyDAO) Completed() bool {
+	return k.StatusCode != nil
+}
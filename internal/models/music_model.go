@@ -1,59 +1,134 @@
 package models
 
+import (
+	"time"
+
+	"github.com/jumayevgadam/music-app/pkg/releasedate"
+	"github.com/jumayevgadam/music-app/pkg/songlink"
+)
+
 // We use in this project 'DAO' and 'DTO' models
 // Easily separate them with tags
 
 // DTO is
 type DTO struct {
-	ID          int    `json:"id" validate:"required"`
-	Group       string `json:"group" validate:"required"`
-	Title       string `json:"title" validate:"required"`
-	ReleaseDate string `json:"release_date" validate:"required"`
-	Text        string `json:"text" validate:"required"`
-	Link        string `json:"link" validate:"required"`
-	CreatedAt   string `json:"createdAt"`
-	UpdatedAt   string `json:"updatedAt"`
+	ID          int              `json:"id"`
+	Group       string           `json:"group" validate:"required"`
+	Title       string           `json:"title" validate:"required"`
+	ReleaseDate releasedate.Date `json:"release_date" validate:"required"`
+	Text        string           `json:"text" validate:"required"`
+	Link        string           `json:"link" validate:"required,songlink"`
+	// link fields are derived from Link, duration and thumbnail come from provider later
+	LinkProvider     string  `json:"linkProvider"`
+	LinkID           string  `json:"linkId"`
+	LinkDuration     *int    `json:"linkDuration"`
+	LinkThumbnailURL *string `json:"linkThumbnailUrl"`
+	// link health is recorded by link checker
+	LinkStatus     string     `json:"linkStatus"`
+	LinkStatusCode *int       `json:"linkStatusCode"`
+	LinkFailures   int        `json:"linkFailures"`
+	LinkCheckedAt  *time.Time `json:"linkCheckedAt"`
+	Version        int        `json:"version"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
 }
 
 // DAO is
 type DAO struct {
-	ID          int    `db:"id"`
-	Group       string `db:"group"`
-	Title       string `db:"title"`
-	ReleaseDate string `db:"release_date"`
-	Text        string `db:"text"`
-	Link        string `db:"link"`
-	CreatedAt   string `db:"created_at"`
-	UpdatedAt   string `db:"updated_at"`
+	ID                   int        `db:"id"`
+	Group                string     `db:"group"`
+	Title                string     `db:"title"`
+	ReleaseDate          time.Time  `db:"release_date"`
+	ReleaseDatePrecision string     `db:"release_date_precision"`
+	Text                 string     `db:"text"`
+	Link                 string     `db:"link"`
+	LinkProvider         string     `db:"link_provider"`
+	LinkID               string     `db:"link_id"`
+	LinkDuration         *int       `db:"link_duration"`
+	LinkThumbnailURL     *string    `db:"link_thumbnail_url"`
+	LinkStatus           string     `db:"link_status"`
+	LinkStatusCode       *int       `db:"link_status_code"`
+	LinkFailures         int        `db:"link_failures"`
+	LinkCheckedAt        *time.Time `db:"link_checked_at"`
+	Version              int        `db:"version"`
+	CreatedAt            time.Time  `db:"created_at"`
+	UpdatedAt            time.Time  `db:"updated_at"`
 }
 
 // Now we will use in service and handler layer DTO models
 // In repository layer we only use DAO model
 
-// ToStorage is
+// ToStorage is, link provider and id are always derived from Link,
+// whatever client sent in them
 func (d *DTO) ToStorage() *DAO {
-	return &DAO{
-		ID:          d.ID,
-		Group:       d.Group,
-		Title:       d.Title,
-		ReleaseDate: d.ReleaseDate,
-		Text:        d.Text,
-		Link:        d.Link,
-		CreatedAt:   d.CreatedAt,
-		UpdatedAt:   d.UpdatedAt,
+	dao := &DAO{
+		ID:                   d.ID,
+		Group:                d.Group,
+		Title:                d.Title,
+		ReleaseDate:          d.ReleaseDate.Time,
+		ReleaseDatePrecision: string(d.ReleaseDate.Precision),
+		Text:                 d.Text,
+		Link:                 d.Link,
+		LinkProvider:         string(songlink.ProviderGeneric),
+		Version:              d.Version,
+		CreatedAt:            d.CreatedAt,
+		UpdatedAt:            d.UpdatedAt,
+	}
+
+	if link, err := songlink.Parse(d.Link); err == nil {
+		dao.LinkProvider, dao.LinkID = string(link.Provider), link.ID
 	}
+
+	return dao
 }
 
 // ToServer is
 func (d *DAO) ToServer() *DTO {
 	return &DTO{
-		ID:          d.ID,
-		Group:       d.Group,
-		Title:       d.Title,
-		ReleaseDate: d.ReleaseDate,
-		Text:        d.Text,
-		Link:        d.Link,
-		CreatedAt:   d.CreatedAt,
-		UpdatedAt:   d.UpdatedAt,
+		ID:               d.ID,
+		Group:            d.Group,
+		Title:            d.Title,
+		ReleaseDate:      releasedate.New(d.ReleaseDate, releasedate.Precision(d.ReleaseDatePrecision)),
+		Text:             d.Text,
+		Link:             d.Link,
+		LinkProvider:     d.LinkProvider,
+		LinkID:           d.LinkID,
+		LinkDuration:     d.LinkDuration,
+		LinkThumbnailURL: d.LinkThumbnailURL,
+		LinkStatus:       d.LinkStatus,
+		LinkStatusCode:   d.LinkStatusCode,
+		LinkFailures:     d.LinkFailures,
+		LinkCheckedAt:    d.LinkCheckedAt,
+		Version:          d.Version,
+		CreatedAt:        d.CreatedAt,
+		UpdatedAt:        d.UpdatedAt,
+	}
+}
+
+// SongPatchDTO is partial update of song, nil fields keep their values
+type SongPatchDTO struct {
+	Group       *string           `json:"group"`
+	Title       *string           `json:"title"`
+	ReleaseDate *releasedate.Date `json:"release_date"`
+	Text        *string           `json:"text"`
+	Link        *string           `json:"link"`
+}
+
+// Apply copies given fields of patch to song
+func (p *SongPatchDTO) Apply(song *DTO) {
+	if p.Group != nil {
+		song.Group = *p.Group
+	}
+	if p.Title != nil {
+		song.Title = *p.Title
+	}
+	if p.ReleaseDate != nil {
+		song.ReleaseDate = *p.ReleaseDate
+	}
+	if p.Text != nil {
+		song.Text = *p.Text
+	}
+	if p.Link != nil {
+		song.Link = *p.Link
 	}
 }
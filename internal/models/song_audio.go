@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jumayevgadam/music-app/pkg/audiotag"
+)
+
+// Song audio is uploaded audio file of song, file itself is kept in blob store
+// and row keeps where it is with tags read from it on upload.
+
+// AudioTagsDTO is
+type AudioTagsDTO struct {
+	Format string `json:"format"`
+	Title  string `json:"title,omitempty"`
+	Artist string `json:"artist,omitempty"`
+	Album  string `json:"album,omitempty"`
+	Date   string `json:"date,omitempty"`
+}
+
+// SongAudioDTO is
+type SongAudioDTO struct {
+	SongID      int           `json:"songId"`
+	ContentType string        `json:"contentType"`
+	Size        int64         `json:"size"`
+	SHA256      string        `json:"sha256"`
+	Filename    string        `json:"filename"`
+	Tags        *AudioTagsDTO `json:"tags"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	UpdatedAt   time.Time     `json:"updatedAt"`
+}
+
+// SongAudioDAO is
+type SongAudioDAO struct {
+	SongID      int       `db:"song_id"`
+	BlobKey     string    `db:"blob_key"`
+	ContentType string    `db:"content_type"`
+	Size        int64     `db:"size"`
+	SHA256      string    `db:"sha256"`
+	Filename    string    `db:"filename"`
+	TagFormat   string    `db:"tag_format"`
+	TagTitle    string    `db:"tag_title"`
+	TagArtist   string    `db:"tag_artist"`
+	TagAlbum    string    `db:"tag_album"`
+	TagDate     string    `db:"tag_date"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// SongAudioUploadDTO is response of upload, Prefilled lists song fields taken from tags
+type SongAudioUploadDTO struct {
+	Audio     *SongAudioDTO `json:"audio"`
+	Song      *DTO          `json:"song"`
+	Prefilled []string      `json:"prefilled"`
+}
+
+// SignedURLDTO is URL players can stream without token till it expires
+type SignedURLDTO struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// SetTags is, nil tags leave tag columns empty
+func (d *SongAudioDAO) SetTags(tags *audiotag.Tags) {
+	if tags == nil {
+		return
+	}
+
+	d.TagFormat = string(tags.Format)
+	d.TagTitle, d.TagArtist, d.TagAlbum, d.TagDate = tags.Title, tags.Artist, tags.Album, tags.Date
+}
+
+// ToServer is
+func (d *SongAudioDAO) ToServer() *SongAudioDTO {
+	dto := &SongAudioDTO{
+		SongID:      d.SongID,
+		ContentType: d.ContentType,
+		Size:        d.Size,
+		SHA256:      d.SHA256,
+		Filename:    d.Filename,
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+	}
+
+	if d.TagFormat != "" {
+		dto.Tags = &AudioTagsDTO{
+			Format: d.TagFormat,
+			Title:  d.TagTitle,
+			Artist: d.TagArtist,
+			Album:  d.TagAlbum,
+			Date:   d.TagDate,
+		}
+	}
+
+	return dto
+}
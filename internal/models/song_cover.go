@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Song cover is uploaded artwork of song, original image and its thumbnails
+// are kept in blob store.
+
+// SongCoverDTO is, thumbnails are served by GET /song/:id/cover?size=<size>
+type SongCoverDTO struct {
+	SongID         int       `json:"songId"`
+	ContentType    string    `json:"contentType"`
+	Size           int64     `json:"size"`
+	SHA256         string    `json:"sha256"`
+	Width          int       `json:"width"`
+	Height         int       `json:"height"`
+	ThumbnailSizes []int     `json:"thumbnailSizes"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// SongCoverDAO is
+type SongCoverDAO struct {
+	SongID      int       `db:"song_id"`
+	BlobKey     string    `db:"blob_key"`
+	ContentType string    `db:"content_type"`
+	Size        int64     `db:"size"`
+	SHA256      string    `db:"sha256"`
+	Width       int       `db:"width"`
+	Height      int       `db:"height"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// ToServer is
+func (d *SongCoverDAO) ToServer(thumbnailSizes []int) *SongCoverDTO {
+	return &SongCoverDTO{
+		SongID:         d.SongID,
+		ContentType:    d.ContentType,
+		Size:           d.Size,
+		SHA256:         d.SHA256,
+		Width:          d.Width,
+		Height:         d.Height,
+		ThumbnailSizes: thumbnailSizes,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
@@ -1,35 +1,43 @@
 package models
 
+import (
+	"time"
+
+	"github.com/jumayevgadam/music-app/pkg/releasedate"
+)
+
 // Same logic, we use methods ToStorage() and ToServer()
 // for clearly using models in layers
 
-// SongDetailDTO struct is
+// SongDetailDTO struct is, info API sends release date as DD.MM.YYYY
 type SongDetailDTO struct {
-	ReleaseDate string `json:"release_date" validate:"required"`
-	Text        string `json:"text" validate:"required"`
-	Link        string `json:"link" validate:"required"`
+	ReleaseDate releasedate.Date `json:"release_date" validate:"required"`
+	Text        string           `json:"text" validate:"required"`
+	Link        string           `json:"link" validate:"required"`
 }
 
 // SongDetailDAO struct is
 type SongDetailDAO struct {
-	ReleaseDate string `db:"release_date"`
-	Text        string `db:"text"`
-	Link        string `db:"link"`
+	ReleaseDate          time.Time `db:"release_date"`
+	ReleaseDatePrecision string    `db:"release_date_precision"`
+	Text                 string    `db:"text"`
+	Link                 string    `db:"link"`
 }
 
 // ToStorage is
 func (s *SongDetailDTO) ToStorage() *SongDetailDAO {
 	return &SongDetailDAO{
-		ReleaseDate: s.ReleaseDate,
-		Text:        s.Text,
-		Link:        s.Link,
+		ReleaseDate:          s.ReleaseDate.Time,
+		ReleaseDatePrecision: string(s.ReleaseDate.Precision),
+		Text:                 s.Text,
+		Link:                 s.Link,
 	}
 }
 
 // ToServer is
 func (s *SongDetailDAO) ToServer() *SongDetailDTO {
 	return &SongDetailDTO{
-		ReleaseDate: s.ReleaseDate,
+		ReleaseDate: releasedate.New(s.ReleaseDate, releasedate.Precision(s.ReleaseDatePrecision)),
 		Text:        s.Text,
 		Link:        s.Link,
 	}
@@ -0,0 +1,53 @@
+package models
+
+// Duplicate reasons, every candidate is reported with one of them
+
+const (
+	// DuplicateSameKey is songs with same normalized group and title
+	DuplicateSameKey = "same_key"
+	// DuplicateSameLink is songs with same link
+	DuplicateSameLink = "same_link"
+	// DuplicateSimilarLyrics is pair of songs with lyrics similar by trigrams
+	DuplicateSimilarLyrics = "similar_lyrics"
+)
+
+// DuplicateReasons are
+var DuplicateReasons = []string{DuplicateSameKey, DuplicateSameLink, DuplicateSimilarLyrics}
+
+// SongDuplicateDTO is group of songs which are likely the same song,
+// similarity is 1 for exact matches
+type SongDuplicateDTO struct {
+	Reason     string  `json:"reason"`
+	Similarity float64 `json:"similarity"`
+	Songs      []*DTO  `json:"songs"`
+}
+
+// SongDuplicateListDTO is
+type SongDuplicateListDTO struct {
+	Duplicates []*SongDuplicateDTO `json:"duplicates"`
+	Page       int                 `json:"page"`
+	Size       int                 `json:"size"`
+	TotalCount int                 `json:"totalCount"`
+	TotalPages int                 `json:"totalPages"`
+	HasMore    bool                `json:"hasMore"`
+}
+
+// SongDuplicateDAO is
+type SongDuplicateDAO struct {
+	Reason     string  `db:"reason"`
+	Similarity float64 `db:"similarity"`
+	SongIDs    []int   `db:"song_ids"`
+	TotalCount int     `db:"total_count"`
+}
+
+// DuplicateFilterDAO selects reported duplicates, empty Reasons means all
+type DuplicateFilterDAO struct {
+	Reasons []string
+	// MinSimilarity is lowest lyrics similarity reported, from 0.3 to 1
+	MinSimilarity float64
+}
+
+// MergeSongsDTO lists songs merged into canonical one
+type MergeSongsDTO struct {
+	DuplicateIDs []int `json:"duplicateIds" validate:"required,min=1,max=100,dive,gt=0"`
+}
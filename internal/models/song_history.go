@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Every change of song is recorded in song_history in the transaction of the change.
+// Snapshot is song after change (before it for deletes), it is what restore brings back.
+
+const (
+	// HistoryCreated is
+	HistoryCreated = "created"
+	// HistoryUpdated is
+	HistoryUpdated = "updated"
+	// HistoryDeleted is
+	HistoryDeleted = "deleted"
+	// HistoryRestored is
+	HistoryRestored = "restored"
+	// HistoryMerged is recorded for both sides of merge, duplicate gets MergedInto
+	// and canonical song gets MergedFrom
+	HistoryMerged = "merged"
+)
+
+// AnonymousActor is recorded when request doesn't tell who made it
+const AnonymousActor = "anonymous"
+
+// FieldChange is value of field before and after change
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// SongHistoryDTO is
+type SongHistoryDTO struct {
+	ID         int64                  `json:"id"`
+	SongID     int                    `json:"songId"`
+	Action     string                 `json:"action"`
+	Actor      string                 `json:"actor"`
+	Diff       map[string]FieldChange `json:"diff"`
+	Snapshot   *DTO                   `json:"snapshot"`
+	MergedInto *int                   `json:"mergedInto,omitempty"`
+	MergedFrom []int                  `json:"mergedFrom,omitempty"`
+	ChangedAt  time.Time              `json:"changedAt"`
+}
+
+// SongHistoryDAO is
+type SongHistoryDAO struct {
+	ID         int64           `db:"id"`
+	SongID     int             `db:"song_id"`
+	Action     string          `db:"action"`
+	Actor      string          `db:"actor"`
+	Diff       json.RawMessage `db:"diff"`
+	Snapshot   json.RawMessage `db:"snapshot"`
+	MergedInto *int            `db:"merged_into"`
+	MergedFrom []int           `db:"merged_from"`
+	ChangedAt  time.Time       `db:"changed_at"`
+}
+
+// ToServer is
+func (d *SongHistoryDAO) ToServer() (*SongHistoryDTO, error) {
+	entry := &SongHistoryDTO{
+		ID:         d.ID,
+		SongID:     d.SongID,
+		Action:     d.Action,
+		Actor:      d.Actor,
+		MergedInto: d.MergedInto,
+		MergedFrom: d.MergedFrom,
+		ChangedAt:  d.ChangedAt,
+	}
+
+	if err := json.Unmarshal(d.Diff, &entry.Diff); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(d.Snapshot, &entry.Snapshot); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
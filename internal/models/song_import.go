@@ -0,0 +1,35 @@
+package models
+
+// ImportReportDTO is result of bulk import. Rows are numbered from 1, header of
+// CSV is not counted. Listed rows are capped, counters always keep real numbers.
+type ImportReportDTO struct {
+	DryRun         bool                 `json:"dryRun"`
+	Accepted       int                  `json:"accepted"`
+	RejectedCount  int                  `json:"rejectedCount"`
+	DuplicateCount int                  `json:"duplicateCount"`
+	Rejected       []ImportRejectedRow  `json:"rejected"`
+	Duplicates     []ImportDuplicateRow `json:"duplicates"`
+}
+
+// ImportRejectedRow is row which couldn't be parsed or validated
+type ImportRejectedRow struct {
+	Row    int    `json:"row"`
+	Reason string `json:"reason"`
+}
+
+// ImportDuplicateRow is row whose group and title already exist, in catalogue
+// (ExistingID) or earlier in same import (DuplicateOfRow)
+type ImportDuplicateRow struct {
+	Row            int    `json:"row"`
+	Group          string `json:"group"`
+	Title          string `json:"title"`
+	ExistingID     int    `json:"existingId,omitempty"`
+	DuplicateOfRow int    `json:"duplicateOfRow,omitempty"`
+}
+
+// SongKeyDAO identifies song by normalized group and title
+type SongKeyDAO struct {
+	ID    int    `db:"id"`
+	Group string `db:"group"`
+	Title string `db:"title"`
+}
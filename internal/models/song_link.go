@@ -0,0 +1,43 @@
+package models
+
+// Link of song is classified on write, enriched with provider metadata and
+// checked for health in background, see music/enricher and music/linkcheck
+
+const (
+	// LinkUnchecked is link which wasn't checked since it was set
+	LinkUnchecked = "unchecked"
+	// LinkOK is link which answered last check
+	LinkOK = "ok"
+	// LinkFailing is link which failed last checks, but not enough of them to be broken
+	LinkFailing = "failing"
+	// LinkBroken is link which failed configured count of checks in a row
+	LinkBroken = "broken"
+)
+
+// LinkStatuses are
+var LinkStatuses = []string{LinkUnchecked, LinkOK, LinkFailing, LinkBroken}
+
+// LinkMetadataDAO is result of link enrichment, it is saved only if song still has Link
+type LinkMetadataDAO struct {
+	SongID       int
+	Link         string
+	Provider     string
+	LinkID       string
+	Duration     *int
+	ThumbnailURL *string
+}
+
+// LinkCheckJobDAO is song link claimed for health check
+type LinkCheckJobDAO struct {
+	SongID int    `db:"id"`
+	Link   string `db:"link"`
+}
+
+// LinkCheckResultDAO is result of health check, it is saved only if song still has Link
+type LinkCheckResultDAO struct {
+	SongID int
+	Link   string
+	Failed bool
+	// StatusCode is nil when request failed before response
+	StatusCode *int
+}
@@ -0,0 +1,14 @@
+package models
+
+// SongListDTO is one page of songs. In page mode TotalCount and TotalPages
+// are filled, in cursor mode client follows NextCursor and PrevCursor.
+type SongListDTO struct {
+	Songs      []*DTO `json:"songs"`
+	Page       int    `json:"page,omitempty"`
+	Size       int    `json:"size"`
+	TotalCount int    `json:"totalCount,omitempty"`
+	TotalPages int    `json:"totalPages,omitempty"`
+	HasMore    bool   `json:"hasMore"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Lyric variants are lyrics of song in other languages, keyed by BCP 47 tag.
+// Original variant is language song is sung in, its text is kept in songs.text,
+// so songs without variants have only original text of unknown language.
+
+// LyricVariantDTO is, language comes from path of request
+type LyricVariantDTO struct {
+	Language  string    `json:"language"`
+	Text      string    `json:"text" validate:"required"`
+	Original  bool      `json:"original"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// LyricVariantDAO is
+type LyricVariantDAO struct {
+	SongID    int       `db:"song_id"`
+	Language  string    `db:"language"`
+	Text      string    `db:"text"`
+	Original  bool      `db:"original"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// ToStorage is
+func (d *LyricVariantDTO) ToStorage(songID int) *LyricVariantDAO {
+	return &LyricVariantDAO{
+		SongID:   songID,
+		Language: d.Language,
+		Text:     d.Text,
+		Original: d.Original,
+	}
+}
+
+// ToServer is
+func (d *LyricVariantDAO) ToServer() *LyricVariantDTO {
+	return &LyricVariantDTO{
+		Language:  d.Language,
+		Text:      d.Text,
+		Original:  d.Original,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jumayevgadam/music-app/pkg/lrc"
+)
+
+// Synced lyrics are stored line by line in song_lyric_lines, Text of song is
+// plain text derived from them. Changing Text otherwise drops synced lines.
+
+const (
+	// LyricsFormatJSON is
+	LyricsFormatJSON = "json"
+	// LyricsFormatLRC is
+	LyricsFormatLRC = "lrc"
+	// LyricsFormatPlain is
+	LyricsFormatPlain = "plain"
+)
+
+// LyricsFormats are
+var LyricsFormats = []string{LyricsFormatJSON, LyricsFormatLRC, LyricsFormatPlain}
+
+// LyricLineDTO is
+type LyricLineDTO struct {
+	Position int `json:"position"`
+	// TimeMs is start of line from beginning of song
+	TimeMs    int64  `json:"timeMs"`
+	Timestamp string `json:"timestamp"`
+	Text      string `json:"text"`
+}
+
+// SongLyricsDTO is lyrics in negotiated language, Language is empty when
+// original language isn't known. Lines are timings of original lyrics, they
+// are empty for translations and songs with plain text only.
+type SongLyricsDTO struct {
+	SongID    int             `json:"songId"`
+	Group     string          `json:"group"`
+	Title     string          `json:"title"`
+	Version   int             `json:"version"`
+	Language  string          `json:"language,omitempty"`
+	Original  bool            `json:"original"`
+	Languages []string        `json:"languages"`
+	Text      string          `json:"text"`
+	Lines     []*LyricLineDTO `json:"lines"`
+}
+
+// LyricLineAtDTO is line sung at TimeMs, Line is nil before first line
+type LyricLineAtDTO struct {
+	SongID     int           `json:"songId"`
+	TimeMs     int64         `json:"timeMs"`
+	Line       *LyricLineDTO `json:"line"`
+	NextTimeMs *int64        `json:"nextTimeMs"`
+}
+
+// LyricLineDAO is
+type LyricLineDAO struct {
+	SongID   int    `db:"song_id"`
+	Position int    `db:"position"`
+	TimeMs   int64  `db:"time_ms"`
+	Text     string `db:"text"`
+}
+
+// ToServer is
+func (d *LyricLineDAO) ToServer() *LyricLineDTO {
+	return &LyricLineDTO{
+		Position:  d.Position,
+		TimeMs:    d.TimeMs,
+		Timestamp: lrc.FormatTimestamp(time.Duration(d.TimeMs) * time.Millisecond),
+		Text:      d.Text,
+	}
+}
+
+// LyricLinesToStorage numbers parsed lines of song in time order
+func LyricLinesToStorage(songID int, lines []lrc.Line) []*LyricLineDAO {
+	daoModels := make([]*LyricLineDAO, 0, len(lines))
+	for i, line := range lines {
+		daoModels = append(daoModels, &LyricLineDAO{
+			SongID:   songID,
+			Position: i + 1,
+			TimeMs:   line.Time.Milliseconds(),
+			Text:     line.Text,
+		})
+	}
+
+	return daoModels
+}
+
+// LyricLinesToLRC is
+func LyricLinesToLRC(lines []*LyricLineDTO) []lrc.Line {
+	lrcLines := make([]lrc.Line, 0, len(lines))
+	for _, line := range lines {
+		lrcLines = append(lrcLines, lrc.Line{Time: time.Duration(line.TimeMs) * time.Millisecond, Text: line.Text})
+	}
+
+	return lrcLines
+}
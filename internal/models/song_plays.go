@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// Plays are recorded one row per event and rolled up into hourly and daily
+// counts in background, see music/playrollup. Charts read rollups only, so
+// plays of last minutes appear in them after next rollup.
+
+// SongPlayDTO is body of play event, all fields are optional
+type SongPlayDTO struct {
+	// DurationMs is how long song was listened to
+	DurationMs *int `json:"durationMs" validate:"omitempty,min=0"`
+	// Source names client, e.g. web or android
+	Source string `json:"source" validate:"max=50"`
+}
+
+// SongPlayDAO is
+type SongPlayDAO struct {
+	SongID     int
+	DurationMs *int
+	Source     string
+	Actor      string
+}
+
+// ToStorage is
+func (d *SongPlayDTO) ToStorage(songID int, actor string) *SongPlayDAO {
+	return &SongPlayDAO{
+		SongID:     songID,
+		DurationMs: d.DurationMs,
+		Source:     d.Source,
+		Actor:      actor,
+	}
+}
+
+// ChartQueryDAO selects songs of chart, zero Since is all time and empty Group is all groups
+type ChartQueryDAO struct {
+	Since time.Time
+	// Hourly reads hourly rollup, it is exact to hour but has more rows than daily one
+	Hourly bool
+	// Group is normalized group
+	Group string
+	Limit int
+}
+
+// TrendingQueryDAO is ChartQueryDAO of trending chart, plays lose half of weight every HalfLife
+type TrendingQueryDAO struct {
+	Now      time.Time
+	Since    time.Time
+	HalfLife time.Duration
+	Group    string
+	Limit    int
+}
+
+// ChartEntryDAO is
+type ChartEntryDAO struct {
+	SongID int      `db:"song_id"`
+	Group  string   `db:"group"`
+	Title  string   `db:"title"`
+	Plays  int64    `db:"plays"`
+	Score  *float64 `db:"score"`
+}
+
+// ChartEntryDTO is, score is set for trending chart only
+type ChartEntryDTO struct {
+	Rank   int      `json:"rank"`
+	SongID int      `json:"songId"`
+	Group  string   `json:"group"`
+	Title  string   `json:"title"`
+	Plays  int64    `json:"plays"`
+	Score  *float64 `json:"score,omitempty"`
+}
+
+// ChartDTO is
+type ChartDTO struct {
+	Chart       string           `json:"chart"`
+	Period      string           `json:"period,omitempty"`
+	Group       string           `json:"group,omitempty"`
+	Since       *time.Time       `json:"since,omitempty"`
+	GeneratedAt time.Time        `json:"generatedAt"`
+	Entries     []*ChartEntryDTO `json:"entries"`
+}
+
+// ToServer is
+func (d *ChartEntryDAO) ToServer(rank int) *ChartEntryDTO {
+	return &ChartEntryDTO{
+		Rank:   rank,
+		SongID: d.SongID,
+		Group:  d.Group,
+		Title:  d.Title,
+		Plays:  d.Plays,
+		Score:  d.Score,
+	}
+}
@@ -0,0 +1,168 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Song events are written to outbox in same transaction as change of song,
+// dispatcher delivers them to webhook subscriptions later
+
+const (
+	// EventSongCreated is
+	EventSongCreated = "song.created"
+	// EventSongUpdated is
+	EventSongUpdated = "song.updated"
+	// EventSongDeleted is
+	EventSongDeleted = "song.deleted"
+	// EventSongMerged is sent for canonical song of merge, merged duplicates get song.deleted
+	EventSongMerged = "song.merged"
+)
+
+const (
+	// DeliveryPending is delivery waiting for (next) attempt
+	DeliveryPending = "pending"
+	// DeliverySucceeded is delivery answered with 2xx
+	DeliverySucceeded = "succeeded"
+	// DeliveryDead is delivery which failed all attempts, it is retried only by replay
+	DeliveryDead = "dead"
+)
+
+// OutboxEventDAO is
+type OutboxEventDAO struct {
+	ID          int64           `db:"id"`
+	EventType   string          `db:"event_type"`
+	AggregateID int             `db:"aggregate_id"`
+	Payload     json.RawMessage `db:"payload"`
+	CreatedAt   time.Time       `db:"created_at"`
+}
+
+// WebhookSubscriptionDTO is, secret is only shown when subscription is created
+type WebhookSubscriptionDTO struct {
+	ID         int       `json:"id"`
+	URL        string    `json:"url" validate:"required,http_url"`
+	Secret     string    `json:"secret,omitempty"`
+	EventTypes []string  `json:"eventTypes" validate:"dive,oneof=song.created song.updated song.deleted song.merged"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// WebhookSubscriptionDAO is
+type WebhookSubscriptionDAO struct {
+	ID         int       `db:"id"`
+	URL        string    `db:"url"`
+	Secret     string    `db:"secret"`
+	EventTypes []string  `db:"event_types"`
+	Active     bool      `db:"active"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// ToStorage is
+func (d *WebhookSubscriptionDTO) ToStorage() *WebhookSubscriptionDAO {
+	return &WebhookSubscriptionDAO{
+		ID:         d.ID,
+		URL:        d.URL,
+		Secret:     d.Secret,
+		EventTypes: d.EventTypes,
+		Active:     d.Active,
+		CreatedAt:  d.CreatedAt,
+		UpdatedAt:  d.UpdatedAt,
+	}
+}
+
+// ToServer is, secret is dropped
+func (d *WebhookSubscriptionDAO) ToServer() *WebhookSubscriptionDTO {
+	return &WebhookSubscriptionDTO{
+		ID:         d.ID,
+		URL:        d.URL,
+		EventTypes: d.EventTypes,
+		Active:     d.Active,
+		CreatedAt:  d.CreatedAt,
+		UpdatedAt:  d.UpdatedAt,
+	}
+}
+
+// WebhookDeliveryDTO is
+type WebhookDeliveryDTO struct {
+	ID             int64     `json:"id"`
+	SubscriptionID int       `json:"subscriptionId"`
+	EventID        int64     `json:"eventId"`
+	EventType      string    `json:"eventType"`
+	Status         string    `json:"status"`
+	Attempts       int       `json:"attempts"`
+	NextAttemptAt  time.Time `json:"nextAttemptAt"`
+	LastStatusCode *int      `json:"lastStatusCode,omitempty"`
+	LastError      *string   `json:"lastError,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// WebhookDeliveryDAO is
+type WebhookDeliveryDAO struct {
+	ID             int64     `db:"id"`
+	SubscriptionID int       `db:"subscription_id"`
+	EventID        int64     `db:"event_id"`
+	EventType      string    `db:"event_type"`
+	Status         string    `db:"status"`
+	Attempts       int       `db:"attempts"`
+	NextAttemptAt  time.Time `db:"next_attempt_at"`
+	LastStatusCode *int      `db:"last_status_code"`
+	LastError      *string   `db:"last_error"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+// ToServer is
+func (d *WebhookDeliveryDAO) ToServer() *WebhookDeliveryDTO {
+	return &WebhookDeliveryDTO{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+// WebhookDeliveryListDTO is one page of deliveries
+type WebhookDeliveryListDTO struct {
+	Deliveries []*WebhookDeliveryDTO `json:"deliveries"`
+	Page       int                   `json:"page"`
+	Size       int                   `json:"size"`
+	TotalCount int                   `json:"totalCount"`
+	TotalPages int                   `json:"totalPages"`
+}
+
+// DeliveryFilterDAO narrows delivery list, zero values match everything
+type DeliveryFilterDAO struct {
+	SubscriptionID int
+	Status         string
+}
+
+// DeliveryJobDAO is claimed delivery with everything needed to send it
+type DeliveryJobDAO struct {
+	ID             int64           `db:"id"`
+	Attempts       int             `db:"attempts"`
+	URL            string          `db:"url"`
+	Secret         string          `db:"secret"`
+	EventID        int64           `db:"event_id"`
+	EventType      string          `db:"event_type"`
+	Payload        json.RawMessage `db:"payload"`
+	EventCreatedAt time.Time       `db:"event_created_at"`
+}
+
+// DeliveryResultDAO is outcome of one attempt
+type DeliveryResultDAO struct {
+	ID            int64
+	Status        string
+	NextAttemptAt time.Time
+	StatusCode    *int
+	Error         *string
+}
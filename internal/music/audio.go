@@ -0,0 +1,34 @@
+package music
+
+import (
+	"errors"
+	"io"
+)
+
+// AudioTypes are MIME types of audio files songs can have, aliases are matched too
+var AudioTypes = []string{
+	"audio/mpeg",
+	"audio/flac",
+	"audio/ogg",
+	"audio/wav",
+	"audio/aac",
+	"audio/mp4",
+	"audio/x-m4a",
+}
+
+var (
+	// ErrUnsupportedAudio is returned when sniffed type of upload isn't one of AudioTypes
+	ErrUnsupportedAudio = errors.New("unsupported audio type")
+	// ErrNoAudio is returned when song has no uploaded audio
+	ErrNoAudio = errors.New("song has no audio")
+)
+
+// AudioUpload is uploaded audio file of song
+type AudioUpload struct {
+	Filename string
+	File     io.ReadSeeker
+	// Prefill replaces group, title and release date of song by tags of file,
+	// Version is then checked as in UpdateSong
+	Prefill bool
+	Version int
+}
@@ -0,0 +1,39 @@
+package music
+
+import "time"
+
+const (
+	// ChartTop is chart of most played songs in period
+	ChartTop = "top"
+	// ChartTrending is chart of songs ranked by plays which weigh less as they get older
+	ChartTrending = "trending"
+
+	// TrendingHalfLife is after how long play weighs half in trending score
+	TrendingHalfLife = 24 * time.Hour
+	// TrendingWindow is how far back plays count for trending, older ones weigh under 1%
+	TrendingWindow = 7 * 24 * time.Hour
+
+	// DefaultChartLimit and MaxChartLimit bound length of charts
+	DefaultChartLimit = 50
+	MaxChartLimit     = 200
+)
+
+// ChartPeriods are how far back top chart looks, zero is all time
+var ChartPeriods = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+	"year":  365 * 24 * time.Hour,
+	"all":   0,
+}
+
+// ChartPeriodNames are names of ChartPeriods in increasing order
+var ChartPeriodNames = []string{"day", "week", "month", "year", "all"}
+
+// hourlyChartPeriod is longest period read from hourly rollup, longer ones read daily rollup
+const hourlyChartPeriod = 7 * 24 * time.Hour
+
+// UsesHourlyRollup reports whether chart of period should read hourly rollup
+func UsesHourlyRollup(period time.Duration) bool {
+	return period > 0 && period <= hourlyChartPeriod
+}
@@ -0,0 +1,15 @@
+package music
+
+import "errors"
+
+// CoverTypes are MIME types of cover images
+var CoverTypes = []string{"image/jpeg", "image/png", "image/webp"}
+
+// CoverSizes are sides of square thumbnails made of every cover, in pixels
+var CoverSizes = []int{96, 300, 600}
+
+// MaxCoverPixels limits decoded cover, 40 megapixels take 160MiB as RGBA
+const MaxCoverPixels = 40_000_000
+
+// ErrNoCover is returned when song has no cover
+var ErrNoCover = errors.New("song has no cover")
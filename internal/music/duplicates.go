@@ -0,0 +1,8 @@
+package music
+
+const (
+	// MinLyricsSimilarity is threshold of pg_trgm % operator, lower similarity can't be searched by index
+	MinLyricsSimilarity = 0.3
+	// DefaultLyricsSimilarity is lowest lyrics similarity reported when threshold is not given
+	DefaultLyricsSimilarity = 0.8
+)
@@ -0,0 +1,117 @@
+package enricher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jumayevgadam/music-app/internal/config"
+	"github.com/jumayevgadam/music-app/internal/database"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/pkg/songlink"
+	"github.com/sirupsen/logrus"
+)
+
+// Enricher polls songs which link wasn't enriched yet, classifies link again
+// (songs stored before classification get provider this way) and asks fetcher
+// for duration and thumbnail. Failed fetch is logged and song is saved without
+// metadata, so broken links don't block the queue.
+
+// Enricher struct is
+type Enricher struct {
+	dataStore database.DataStore
+	fetcher   songlink.Fetcher
+	cfg       config.Links
+}
+
+// NewEnricher method is
+func NewEnricher(dataStore database.DataStore, fetcher songlink.Fetcher, cfg config.Links) *Enricher {
+	return &Enricher{dataStore: dataStore, fetcher: fetcher, cfg: cfg}
+}
+
+// NewOEmbedFetcher builds default fetcher from config
+func NewOEmbedFetcher(cfg config.Links) *songlink.OEmbedFetcher {
+	return songlink.NewOEmbedFetcher(&http.Client{Timeout: cfg.Timeout}, map[songlink.Provider]string{
+		songlink.ProviderYouTube:    cfg.YouTubeOEmbedURL,
+		songlink.ProviderSoundCloud: cfg.SoundCloudOEmbedURL,
+		songlink.ProviderSpotify:    cfg.SpotifyOEmbedURL,
+		songlink.ProviderBandcamp:   cfg.BandcampOEmbedURL,
+	})
+}
+
+// Run enriches songs till ctx is done
+func (e *Enricher) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		e.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick enriches batches till fewer songs than batch size are left
+func (e *Enricher) tick(ctx context.Context) {
+	for ctx.Err() == nil {
+		songs, err := e.dataStore.SongRepo().ListUnenrichedSongs(ctx, e.cfg.BatchSize)
+		if err != nil {
+			logrus.Errorf("[enricher][ListUnenrichedSongs]: %v", err)
+			return
+		}
+
+		for _, song := range songs {
+			if err := e.dataStore.SongRepo().SaveLinkMetadata(ctx, e.enrich(ctx, song)); err != nil {
+				logrus.Errorf("[enricher][SaveLinkMetadata]: song %d: %v", song.ID, err)
+				return
+			}
+		}
+
+		if len(songs) < e.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// enrich returns link metadata of song, fields fetcher couldn't get stay nil
+func (e *Enricher) enrich(ctx context.Context, song *songModel.DAO) *songModel.LinkMetadataDAO {
+	result := &songModel.LinkMetadataDAO{
+		SongID:   song.ID,
+		Link:     song.Link,
+		Provider: string(songlink.ProviderGeneric),
+	}
+
+	// links stored before validation may be invalid, they stay generic
+	link, err := songlink.Parse(song.Link)
+	if err != nil {
+		return result
+	}
+	result.Provider, result.LinkID = string(link.Provider), link.ID
+
+	if link.Provider == songlink.ProviderGeneric {
+		return result
+	}
+
+	metadata, err := e.fetcher.Fetch(ctx, link)
+	if errors.Is(err, songlink.ErrNoEndpoint) {
+		return result
+	}
+	if err != nil {
+		logrus.Warnf("[enricher][Fetch]: song %d: %v", song.ID, err)
+		return result
+	}
+
+	if metadata.Duration > 0 {
+		result.Duration = &metadata.Duration
+	}
+	if metadata.ThumbnailURL != "" {
+		result.ThumbnailURL = &metadata.ThumbnailURL
+	}
+
+	return result
+}
@@ -0,0 +1,19 @@
+package music
+
+import "github.com/jumayevgadam/music-app/pkg/filter"
+
+// lyricsTexts are text of song and its lyric variants, so text filter searches translations too
+const lyricsTexts = `SELECT 1 FROM (SELECT songs.text UNION ALL SELECT text FROM song_lyric_variants WHERE song_id = songs.id) AS lyrics(text)`
+
+// SongFilterFields are fields songs can be filtered by with filter query param
+var SongFilterFields = filter.Schema{
+	"id":           {Column: "songs.id", Type: filter.TypeInt},
+	"group":        {Column: `songs."group"`, Type: filter.TypeString},
+	"title":        {Column: "songs.title", Type: filter.TypeString},
+	"release_date": {Column: "songs.release_date", Type: filter.TypeDate},
+	"text":         {Column: "lyrics.text", Type: filter.TypeString, Exists: lyricsTexts},
+	"link":         {Column: "songs.link", Type: filter.TypeString},
+	"link_status":  {Column: "songs.link_status", Type: filter.TypeString},
+	"created_at":   {Column: "songs.created_at", Type: filter.TypeTime},
+	"updated_at":   {Column: "songs.updated_at", Type: filter.TypeTime},
+}
@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	httpError "github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/reqvalidator"
+	"github.com/jumayevgadam/music-app/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+)
+
+// GraphQLHandler struct is
+type GraphQLHandler struct {
+	schema  *graphql.Schema
+	service musicOps.Service
+}
+
+// NewGraphQLHandler method is
+func NewGraphQLHandler(service musicOps.Service) *GraphQLHandler {
+	return &GraphQLHandler{schema: NewSchema(service), service: service}
+}
+
+// queryRequest is body of GraphQL request
+type queryRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query handler executes query given as JSON body (POST) or query params (GET)
+func (gh *GraphQLHandler) Query() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[GraphQLHandler][Query]")
+		ctx, span := tracer.Start(c.Request().Context(), "[GraphQLHandler][Query]")
+		defer span.End()
+
+		var request queryRequest
+		if c.Request().Method == http.MethodGet {
+			request.Query = c.QueryParam("query")
+			request.OperationName = c.QueryParam("operationName")
+
+			if variables := c.QueryParam("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+					tracing.EventErrorTracer(span, err, "[GraphQLHandler][Query]")
+					return httpError.Write(c, httpError.Validation("variables must be JSON object", err))
+				}
+			}
+		} else if err := reqvalidator.ReadRequest(c, &request); err != nil {
+			tracing.EventErrorTracer(span, err, "[GraphQLHandler][Query]")
+			return httpError.Write(c, err)
+		}
+
+		if request.Query == "" {
+			return httpError.Write(c, httpError.Validation("query is required", httpError.ErrBadRequest))
+		}
+
+		response := gh.schema.Exec(Context(ctx, gh.service), request.Query, request.OperationName, request.Variables)
+
+		// GraphQL errors are part of response body, status stays 200
+		return c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"context"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/pkg/dataloader"
+)
+
+// Loaders are made for every request, so songs requested by sibling fields
+// (e.g. songs of artist of every song in connection) are fetched with one query
+
+type loadersKey struct{}
+
+// artistSongsKey is group songs are loaded for, first is count of songs
+type artistSongsKey struct {
+	group string
+	first int
+}
+
+// loaders struct is
+type loaders struct {
+	songByID    *dataloader.Loader[int, *songModel.DTO]
+	artistSongs *dataloader.Loader[artistSongsKey, []*songModel.DTO]
+}
+
+// newLoaders method is, ctx is context of request loaders fetch with
+func newLoaders(ctx context.Context, service musicOps.Service) *loaders {
+	return &loaders{
+		songByID: dataloader.New(ctx, func(ctx context.Context, songIDs []int) (map[int]*songModel.DTO, error) {
+			return service.GetSongsByIDs(ctx, songIDs)
+		}),
+		artistSongs: dataloader.New(ctx, func(ctx context.Context, keys []artistSongsKey) (map[artistSongsKey][]*songModel.DTO, error) {
+			// one query with biggest first, then every key gets its own count
+			groups := make([]string, 0, len(keys))
+			first := 0
+			for _, key := range keys {
+				groups = append(groups, key.group)
+				first = max(first, key.first)
+			}
+
+			songs, err := service.ListSongsByGroups(ctx, groups, first)
+			if err != nil {
+				return nil, err
+			}
+
+			result := make(map[artistSongsKey][]*songModel.DTO, len(keys))
+			for _, key := range keys {
+				groupSongs := songs[key.group]
+				result[key] = groupSongs[:min(len(groupSongs), key.first)]
+			}
+
+			return result, nil
+		}),
+	}
+}
+
+// withLoaders puts loaders to context of request
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// loadersFromContext is
+func loadersFromContext(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,300 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/filter"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+)
+
+// maxArtistSongs is max count of songs of artist in one query
+const maxArtistSongs = 100
+
+// resolver is root of Query type
+type resolver struct {
+	service musicOps.Service
+}
+
+// Song resolves song by id through loader, so aliased song fields make one query
+func (r *resolver) Song(ctx context.Context, args struct{ ID graphql.ID }) (*songResolver, error) {
+	songID, err := strconv.Atoi(string(args.ID))
+	if err != nil {
+		return nil, errlst.GraphQLError(errlst.Validation("id must be a number", err))
+	}
+
+	song, err := loadersFromContext(ctx).songByID.Load(ctx, songID)
+	if err != nil || song == nil {
+		return nil, errlst.GraphQLError(err)
+	}
+
+	return &songResolver{song: song}, nil
+}
+
+// songsArgs are arguments of songs connection
+type songsArgs struct {
+	First   int32
+	After   *string
+	Before  *string
+	Page    *int32
+	Filter  *string
+	OrderBy *string
+}
+
+// Songs resolves songs connection, after takes endCursor and before takes startCursor
+func (r *resolver) Songs(ctx context.Context, args songsArgs) (*songConnectionResolver, error) {
+	if args.After != nil && args.Before != nil {
+		return nil, errlst.GraphQLError(errlst.Validation("after and before can't be used together", errlst.ErrBadQueryParams))
+	}
+
+	cursor, prev := "", false
+	switch {
+	case args.After != nil:
+		cursor = *args.After
+	case args.Before != nil:
+		cursor, prev = *args.Before, true
+	}
+
+	page, size := "", strconv.Itoa(int(args.First))
+	if args.Page != nil {
+		page = strconv.Itoa(int(*args.Page))
+	}
+
+	paginationQuery, err := pagination.GetPagination(page, size, deref(args.OrderBy), cursor)
+	if err != nil {
+		return nil, errlst.GraphQLError(err)
+	}
+
+	// endCursor only goes forward and startCursor only goes back
+	if c := paginationQuery.GetCursor(); c != nil && c.Prev != prev {
+		return nil, errlst.GraphQLError(errlst.Validation("cursor doesn't match direction of after/before", pagination.ErrInvalidCursor))
+	}
+
+	if err := paginationQuery.SetSort(musicOps.SongSortFields, musicOps.DefaultSongSort); err != nil {
+		return nil, errlst.GraphQLError(err)
+	}
+
+	songFilter, err := filter.Parse(deref(args.Filter), musicOps.SongFilterFields)
+	if err != nil {
+		return nil, errlst.GraphQLError(err)
+	}
+
+	list, err := r.service.ListSongs(ctx, songFilter, paginationQuery)
+	if err != nil {
+		return nil, errlst.GraphQLError(err)
+	}
+
+	return &songConnectionResolver{list: list, cursorMode: paginationQuery.IsCursorMode()}, nil
+}
+
+// Artist is
+func (r *resolver) Artist(args struct{ Name string }) *artistResolver {
+	return &artistResolver{name: args.Name}
+}
+
+// songResolver is
+type songResolver struct {
+	song *songModel.DTO
+}
+
+// ID is
+func (s *songResolver) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(s.song.ID))
+}
+
+// Group is
+func (s *songResolver) Group() string {
+	return s.song.Group
+}
+
+// Title is
+func (s *songResolver) Title() string {
+	return s.song.Title
+}
+
+// ReleaseDate is
+func (s *songResolver) ReleaseDate() string {
+	return s.song.ReleaseDate.String()
+}
+
+// Text is
+func (s *songResolver) Text() string {
+	return s.song.Text
+}
+
+// Link is
+func (s *songResolver) Link() string {
+	return s.song.Link
+}
+
+// CreatedAt is
+func (s *songResolver) CreatedAt() string {
+	return s.song.CreatedAt.Format(time.RFC3339Nano)
+}
+
+// UpdatedAt is
+func (s *songResolver) UpdatedAt() string {
+	return s.song.UpdatedAt.Format(time.RFC3339Nano)
+}
+
+// Version is
+func (s *songResolver) Version() int32 {
+	return int32(s.song.Version)
+}
+
+// LinkProvider is
+func (s *songResolver) LinkProvider() string {
+	return s.song.LinkProvider
+}
+
+// LinkID is
+func (s *songResolver) LinkID() string {
+	return s.song.LinkID
+}
+
+// LinkDuration is
+func (s *songResolver) LinkDuration() *int32 {
+	if s.song.LinkDuration == nil {
+		return nil
+	}
+	duration := int32(*s.song.LinkDuration)
+
+	return &duration
+}
+
+// LinkThumbnailURL is
+func (s *songResolver) LinkThumbnailURL() *string {
+	return s.song.LinkThumbnailURL
+}
+
+// LinkStatus is
+func (s *songResolver) LinkStatus() string {
+	return s.song.LinkStatus
+}
+
+// LinkFailures is
+func (s *songResolver) LinkFailures() int32 {
+	return int32(s.song.LinkFailures)
+}
+
+// LinkCheckedAt is
+func (s *songResolver) LinkCheckedAt() *string {
+	if s.song.LinkCheckedAt == nil {
+		return nil
+	}
+
+	checkedAt := s.song.LinkCheckedAt.Format(time.RFC3339Nano)
+	return &checkedAt
+}
+
+// Artist is
+func (s *songResolver) Artist() *artistResolver {
+	return &artistResolver{name: s.song.Group}
+}
+
+// artistResolver is, artist is group of songs
+type artistResolver struct {
+	name string
+}
+
+// Name is
+func (a *artistResolver) Name() string {
+	return a.name
+}
+
+// Songs resolves latest songs of artist through loader
+func (a *artistResolver) Songs(ctx context.Context, args struct{ First int32 }) ([]*songResolver, error) {
+	first := int(args.First)
+	if first < 1 || first > maxArtistSongs {
+		return nil, errlst.GraphQLError(errlst.Validation("first must be between 1 and "+strconv.Itoa(maxArtistSongs), errlst.ErrRange))
+	}
+
+	songs, err := loadersFromContext(ctx).artistSongs.Load(ctx, artistSongsKey{group: musicOps.NormalizeGroup(a.name), first: first})
+	if err != nil {
+		return nil, errlst.GraphQLError(err)
+	}
+
+	return toSongResolvers(songs), nil
+}
+
+// songConnectionResolver is
+type songConnectionResolver struct {
+	list       *songModel.SongListDTO
+	cursorMode bool
+}
+
+// Nodes is
+func (c *songConnectionResolver) Nodes() []*songResolver {
+	return toSongResolvers(c.list.Songs)
+}
+
+// PageInfo is
+func (c *songConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{list: c.list}
+}
+
+// TotalCount is
+func (c *songConnectionResolver) TotalCount() *int32 {
+	if c.cursorMode {
+		return nil
+	}
+
+	totalCount := int32(c.list.TotalCount)
+	return &totalCount
+}
+
+// pageInfoResolver is
+type pageInfoResolver struct {
+	list *songModel.SongListDTO
+}
+
+// HasNextPage is
+func (p *pageInfoResolver) HasNextPage() bool {
+	return p.list.HasMore
+}
+
+// HasPreviousPage is
+func (p *pageInfoResolver) HasPreviousPage() bool {
+	return p.list.PrevCursor != ""
+}
+
+// StartCursor is
+func (p *pageInfoResolver) StartCursor() *string {
+	return optional(p.list.PrevCursor)
+}
+
+// EndCursor is
+func (p *pageInfoResolver) EndCursor() *string {
+	return optional(p.list.NextCursor)
+}
+
+// toSongResolvers is
+func toSongResolvers(songs []*songModel.DTO) []*songResolver {
+	resolvers := make([]*songResolver, 0, len(songs))
+	for _, song := range songs {
+		resolvers = append(resolvers, &songResolver{song: song})
+	}
+
+	return resolvers
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
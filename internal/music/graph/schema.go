@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/graph-gophers/graphql-go"
+	graphqlOtel "github.com/graph-gophers/graphql-go/trace/otel"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+)
+
+// We use in graph package GraphQL transport of catalogue, resolvers call
+// same music.Service as HTTP handlers and gRPC server
+
+//go:embed schema.graphql
+var schemaSDL string
+
+const (
+	// maxDepth limits nesting of queries
+	maxDepth = 8
+	// maxParallelism is count of fields resolved concurrently, loaders batch them
+	maxParallelism = 50
+)
+
+// NewSchema parses schema with resolvers of service
+func NewSchema(service musicOps.Service) *graphql.Schema {
+	return graphql.MustParseSchema(
+		schemaSDL,
+		&resolver{service: service},
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(maxParallelism),
+		graphql.Tracer(graphqlOtel.DefaultTracer()),
+	)
+}
+
+// Context returns context resolvers of one request need
+func Context(ctx context.Context, service musicOps.Service) context.Context {
+	return withLoaders(ctx, newLoaders(ctx, service))
+}
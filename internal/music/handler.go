@@ -7,4 +7,33 @@ import "github.com/labstack/echo/v4"
 // Handler interface is
 type Handler interface {
 	AddSong() echo.HandlerFunc
+	GetSong() echo.HandlerFunc
+	UpdateSong() echo.HandlerFunc
+	PatchSong() echo.HandlerFunc
+	DeleteSong() echo.HandlerFunc
+	ListSongs() echo.HandlerFunc
+	ImportSongs() echo.HandlerFunc
+	ExportSongs() echo.HandlerFunc
+	ListSongHistory() echo.HandlerFunc
+	RestoreSong() echo.HandlerFunc
+	ListSongDuplicates() echo.HandlerFunc
+	MergeSongs() echo.HandlerFunc
+	GetSongLyrics() echo.HandlerFunc
+	PutSongLyrics() echo.HandlerFunc
+	GetLyricLineAt() echo.HandlerFunc
+	ListLyricVariants() echo.HandlerFunc
+	GetLyricVariant() echo.HandlerFunc
+	PutLyricVariant() echo.HandlerFunc
+	DeleteLyricVariant() echo.HandlerFunc
+	UploadSongAudio() echo.HandlerFunc
+	GetSongAudio() echo.HandlerFunc
+	DeleteSongAudio() echo.HandlerFunc
+	StreamSongAudio() echo.HandlerFunc
+	SignSongStreamURL() echo.HandlerFunc
+	UploadSongCover() echo.HandlerFunc
+	GetSongCover() echo.HandlerFunc
+	DeleteSongCover() echo.HandlerFunc
+	RecordPlay() echo.HandlerFunc
+	TopSongs() echo.HandlerFunc
+	TrendingSongs() echo.HandlerFunc
 }
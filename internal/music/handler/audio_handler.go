@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	httpError "github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+)
+
+// UploadSongAudio handler is, body is multipart form with audio in "file" field.
+// With prefill=true tags of file replace group, title and release date of song,
+// If-Match with song ETag is then required as for other song writes.
+func (sh *SongHandler) UploadSongAudio() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][UploadSongAudio]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][UploadSongAudio]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][UploadSongAudio]")
+			return httpError.Write(c, err)
+		}
+
+		upload := &musicOps.AudioUpload{}
+		if raw := c.QueryParam("prefill"); raw != "" {
+			if upload.Prefill, err = strconv.ParseBool(raw); err != nil {
+				return httpError.Write(c, httpError.NewBadQueryParamsError("prefill must be true or false"))
+			}
+		}
+
+		if upload.Prefill {
+			if upload.Version, err = ifMatchVersion(c); err != nil {
+				tracing.EventErrorTracer(span, err, "[SongHandler][UploadSongAudio]")
+				return httpError.Write(c, err)
+			}
+		}
+
+		file, fileHeader, err := formFile(c, "audio", sh.storage.MaxAudioSize)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][UploadSongAudio]")
+			return httpError.Write(c, err)
+		}
+		defer file.Close()
+
+		upload.Filename, upload.File = fileHeader.Filename, file
+
+		uploaded, err := sh.service.UploadSongAudio(ctx, songID, upload)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][UploadSongAudio]")
+			if errors.Is(err, musicOps.ErrUnsupportedAudio) {
+				return httpError.Write(c, httpError.NewRestError(
+					http.StatusUnsupportedMediaType, "unsupported media type", err.Error(),
+				))
+			}
+			return httpError.Write(c, err)
+		}
+
+		setSongETag(c, uploaded.Song.Version)
+		return c.JSON(http.StatusOK, uploaded)
+	}
+}
+
+// GetSongAudio handler is, returns metadata of uploaded audio
+func (sh *SongHandler) GetSongAudio() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][GetSongAudio]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][GetSongAudio]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][GetSongAudio]")
+			return httpError.Write(c, err)
+		}
+
+		audio, err := sh.service.GetSongAudio(ctx, songID)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][GetSongAudio]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, audio)
+	}
+}
+
+// DeleteSongAudio handler is
+func (sh *SongHandler) DeleteSongAudio() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][DeleteSongAudio]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][DeleteSongAudio]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][DeleteSongAudio]")
+			return httpError.Write(c, err)
+		}
+
+		if err := sh.service.DeleteSongAudio(ctx, songID); err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][DeleteSongAudio]")
+			return httpError.Write(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// multipartOverhead is room for multipart headers and boundaries around uploaded file
+const multipartOverhead = 1 << 20
+
+// formFile opens uploaded file of multipart form, kind names it in errors. Request
+// body is limited, so too big uploads are refused before they are spooled to disk.
+func formFile(c echo.Context, kind string, maxSize int64) (multipart.File, *multipart.FileHeader, error) {
+	tooLarge := httpError.NewRestError(
+		http.StatusRequestEntityTooLarge, "request entity too large",
+		fmt.Sprintf("%s file must be at most %d bytes", kind, maxSize),
+	)
+
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, nil, tooLarge
+		}
+		return nil, nil, httpError.NewBadRequestError(fmt.Sprintf("multipart form with %s in file field is required", kind))
+	}
+	if fileHeader.Size > maxSize {
+		return nil, nil, tooLarge
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, fileHeader, nil
+}
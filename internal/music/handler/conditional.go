@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	httpError "github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/labstack/echo/v4"
+)
+
+// songETag is strong ETag of song version
+func songETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// setSongETag is
+func setSongETag(c echo.Context, version int) {
+	c.Response().Header().Set("ETag", songETag(version))
+}
+
+// ifMatchVersion parses If-Match header of write request, it is required so
+// client can't overwrite song without seeing its current version. "*" matches
+// any version and is returned as 0.
+func ifMatchVersion(c echo.Context) (int, error) {
+	raw := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if raw == "" {
+		return 0, httpError.NewPreconditionRequiredError("If-Match header with song ETag is required")
+	}
+
+	if raw == "*" {
+		return 0, nil
+	}
+
+	// only one ETag is meaningful, song has single current version
+	version, ok := parseSongETag(raw)
+	if !ok {
+		return 0, httpError.NewBadRequestError("If-Match must be ETag of song, e.g. \"3\"")
+	}
+
+	return version, nil
+}
+
+// ifNoneMatch reports whether If-None-Match header of read request matches version
+func ifNoneMatch(c echo.Context, version int) bool {
+	raw := strings.TrimSpace(c.Request().Header.Get("If-None-Match"))
+	if raw == "" {
+		return false
+	}
+
+	if raw == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(raw, ",") {
+		// weak comparison is used for reads
+		if v, ok := parseSongETag(strings.TrimPrefix(strings.TrimSpace(tag), "W/")); ok && v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseSongETag parses quoted positive version
+func parseSongETag(tag string) (int, bool) {
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil || !strings.HasPrefix(tag, `"`) {
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+
+	return version, true
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jumayevgadam/music-app/internal/config"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/labstack/echo/v4"
+)
+
+// fakeService serves one song, methods tests don't need panic through
+// nil embedded interface
+type fakeService struct {
+	musicOps.Service
+	song *songModel.DTO
+}
+
+func (s *fakeService) GetSong(ctx context.Context, songID int) (*songModel.DTO, error) {
+	if s.song == nil || s.song.ID != songID {
+		return nil, errlst.NotFound(fmt.Sprintf("song %d not found", songID), errlst.ErrNotFound)
+	}
+
+	song := *s.song
+	return &song, nil
+}
+
+func (s *fakeService) DeleteSong(ctx context.Context, songID int, version int) error {
+	if _, err := s.GetSong(ctx, songID); err != nil {
+		return err
+	}
+
+	if version != 0 && version != s.song.Version {
+		return errlst.PreconditionFailed("stale version", errlst.ErrPreconditionFailed)
+	}
+	s.song = nil
+
+	return nil
+}
+
+// serveSong calls handler for song 1 with given headers
+func serveSong(t *testing.T, handler echo.HandlerFunc, method string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, "/song/1", nil)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	if err := handler(c); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+
+	return rec
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		header  string
+		version int
+		status  int
+	}{
+		{header: `"3"`, version: 3},
+		{header: ` "12" `, version: 12},
+		{header: "*", version: 0},
+		{header: "", status: http.StatusPreconditionRequired},
+		{header: "3", status: http.StatusBadRequest},
+		{header: `W/"3"`, status: http.StatusBadRequest},
+		{header: `"0"`, status: http.StatusBadRequest},
+		{header: `"3", "4"`, status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/song/1", nil)
+			req.Header.Set("If-Match", tt.header)
+
+			version, err := ifMatchVersion(echo.New().NewContext(req, httptest.NewRecorder()))
+			if tt.status != 0 {
+				if err == nil || errlst.ParseErrors(err).Status() != tt.status {
+					t.Fatalf("ifMatchVersion(%q) error = %v, want %d", tt.header, err, tt.status)
+				}
+				return
+			}
+
+			if err != nil || version != tt.version {
+				t.Fatalf("ifMatchVersion(%q) = %d, %v, want %d", tt.header, version, err, tt.version)
+			}
+		})
+	}
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	tests := map[string]bool{
+		`"3"`:         true,
+		`W/"3"`:       true,
+		`"1", "3"`:    true,
+		"*":           true,
+		`"2"`:         false,
+		"":            false,
+		"3":           false,
+		`"1",W/"2"  `: false,
+	}
+
+	for header, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/song/1", nil)
+		req.Header.Set("If-None-Match", header)
+
+		if got := ifNoneMatch(echo.New().NewContext(req, httptest.NewRecorder()), 3); got != want {
+			t.Fatalf("ifNoneMatch(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
+
+func TestGetSongETag(t *testing.T) {
+	handler := NewSongHandler(&fakeService{song: &songModel.DTO{ID: 1, Title: "Hysteria", Version: 3}}, config.Storage{}).GetSong()
+
+	rec := serveSong(t, handler, http.MethodGet, nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"3"` || !strings.Contains(rec.Body.String(), "Hysteria") {
+		t.Fatalf("GET = %d, ETag %q, body %s", rec.Code, rec.Header().Get("ETag"), rec.Body)
+	}
+
+	// client has current version
+	rec = serveSong(t, handler, http.MethodGet, map[string]string{"If-None-Match": `"3"`})
+	if rec.Code != http.StatusNotModified || rec.Header().Get("ETag") != `"3"` || rec.Body.Len() != 0 {
+		t.Fatalf("conditional GET = %d, ETag %q, body %s", rec.Code, rec.Header().Get("ETag"), rec.Body)
+	}
+
+	// client has old version
+	rec = serveSong(t, handler, http.MethodGet, map[string]string{"If-None-Match": `"2"`})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET with old ETag = %d, want 200", rec.Code)
+	}
+}
+
+func TestDeleteSongPreconditions(t *testing.T) {
+	service := &fakeService{song: &songModel.DTO{ID: 1, Version: 3}}
+	handler := NewSongHandler(service, config.Storage{}).DeleteSong()
+
+	if rec := serveSong(t, handler, http.MethodDelete, nil); rec.Code != http.StatusPreconditionRequired {
+		t.Fatalf("DELETE without If-Match = %d, want 428", rec.Code)
+	}
+	if rec := serveSong(t, handler, http.MethodDelete, map[string]string{"If-Match": `"2"`}); rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("DELETE with stale If-Match = %d, want 412", rec.Code)
+	}
+	if service.song == nil {
+		t.Fatal("song was deleted with stale If-Match")
+	}
+
+	if rec := serveSong(t, handler, http.MethodDelete, map[string]string{"If-Match": `"3"`}); rec.Code != http.StatusNoContent {
+		t.Fatalf("DELETE with current If-Match = %d, want 204", rec.Code)
+	}
+}
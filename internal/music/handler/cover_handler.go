@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	httpError "github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+)
+
+// UploadSongCover handler is, body is multipart form with JPEG, PNG or WebP image
+// in "file" field. Thumbnails of all sizes are made at once.
+func (sh *SongHandler) UploadSongCover() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][UploadSongCover]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][UploadSongCover]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][UploadSongCover]")
+			return httpError.Write(c, err)
+		}
+
+		file, _, err := formFile(c, "cover", sh.storage.MaxCoverSize)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][UploadSongCover]")
+			return httpError.Write(c, err)
+		}
+		defer file.Close()
+
+		cover, err := sh.service.UploadSongCover(ctx, songID, file)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][UploadSongCover]")
+			if errors.Is(err, httpError.ErrNotAllowedImageHeader) {
+				return httpError.Write(c, httpError.NewRestError(
+					http.StatusUnsupportedMediaType, "unsupported media type", err.Error(),
+				))
+			}
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, cover)
+	}
+}
+
+// GetSongCover handler is, size=300 gives JPEG thumbnail instead of original image.
+// URL with v=<sha256 of cover> never changes content, so it is cached for a year,
+// other URLs are revalidated by ETag.
+func (sh *SongHandler) GetSongCover() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][GetSongCover]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][GetSongCover]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][GetSongCover]")
+			return httpError.Write(c, err)
+		}
+
+		var size int
+		if raw := c.QueryParam("size"); raw != "" {
+			if size, err = strconv.Atoi(raw); err != nil {
+				return httpError.Write(c, httpError.NewBadQueryParamsError("size must be integer"))
+			}
+		}
+
+		cover, object, err := sh.service.OpenSongCover(ctx, songID, size)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][GetSongCover]")
+			return httpError.Write(c, err)
+		}
+		defer object.Close()
+
+		header := c.Response().Header()
+		header.Set(echo.HeaderContentType, cover.ContentType)
+		header.Set("ETag", `"`+cover.SHA256+`"`)
+		if size != 0 {
+			header.Set(echo.HeaderContentType, "image/jpeg")
+			header.Set("ETag", `"`+cover.SHA256+"-"+strconv.Itoa(size)+`"`)
+		}
+
+		if c.QueryParam("v") == cover.SHA256 {
+			header.Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			header.Set("Cache-Control", "public, no-cache")
+		}
+
+		http.ServeContent(c.Response(), c.Request(), "", cover.UpdatedAt, object)
+
+		return nil
+	}
+}
+
+// DeleteSongCover handler is
+func (sh *SongHandler) DeleteSongCover() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][DeleteSongCover]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][DeleteSongCover]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][DeleteSongCover]")
+			return httpError.Write(c, err)
+		}
+
+		if err := sh.service.DeleteSongCover(ctx, songID); err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][DeleteSongCover]")
+			return httpError.Write(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
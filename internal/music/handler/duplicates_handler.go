@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	httpError "github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+	"github.com/jumayevgadam/music-app/pkg/reqvalidator"
+	"github.com/jumayevgadam/music-app/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+)
+
+// ListSongDuplicates handler is, supports page/size, reason=same_key,same_link,similar_lyrics
+// and threshold=0.9 as lowest lyrics similarity
+func (sh *SongHandler) ListSongDuplicates() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][ListSongDuplicates]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][ListSongDuplicates]")
+		defer span.End()
+
+		paginationQuery, err := pagination.GetPaginationFromCtx(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ListSongDuplicates]")
+			return httpError.Write(c, err)
+		}
+		if paginationQuery.IsCursorMode() {
+			return httpError.Write(c, httpError.NewBadQueryParamsError("duplicates are paginated by page only"))
+		}
+
+		duplicateFilter, err := parseDuplicateFilter(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ListSongDuplicates]")
+			return httpError.Write(c, err)
+		}
+
+		duplicates, err := sh.service.ListSongDuplicates(ctx, duplicateFilter, paginationQuery)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ListSongDuplicates]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, duplicates)
+	}
+}
+
+// MergeSongs handler is, body lists duplicate_ids merged into song of path
+func (sh *SongHandler) MergeSongs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][MergeSongs]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][MergeSongs]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][MergeSongs]")
+			return httpError.Write(c, err)
+		}
+
+		var mergeRequest songModel.MergeSongsDTO
+		if err := reqvalidator.ReadRequest(c, &mergeRequest); err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][MergeSongs]")
+			return httpError.Write(c, err)
+		}
+
+		song, err := sh.service.MergeSongs(ctx, songID, mergeRequest.DuplicateIDs)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][MergeSongs]")
+			return httpError.Write(c, err)
+		}
+
+		setSongETag(c, song.Version)
+		return c.JSON(http.StatusOK, song)
+	}
+}
+
+// parseDuplicateFilter is
+func parseDuplicateFilter(c echo.Context) (songModel.DuplicateFilterDAO, error) {
+	duplicateFilter := songModel.DuplicateFilterDAO{MinSimilarity: musicOps.DefaultLyricsSimilarity}
+
+	if raw := c.QueryParam("reason"); raw != "" {
+		for _, reason := range strings.Split(raw, ",") {
+			reason = strings.TrimSpace(reason)
+			if !slices.Contains(songModel.DuplicateReasons, reason) {
+				return duplicateFilter, httpError.NewBadQueryParamsError(
+					fmt.Sprintf("reason must be one of %s", strings.Join(songModel.DuplicateReasons, ", ")),
+				)
+			}
+			duplicateFilter.Reasons = append(duplicateFilter.Reasons, reason)
+		}
+	}
+
+	if raw := c.QueryParam("threshold"); raw != "" {
+		threshold, err := strconv.ParseFloat(raw, 64)
+		if err != nil || threshold < musicOps.MinLyricsSimilarity || threshold > 1 {
+			return duplicateFilter, httpError.NewBadQueryParamsError(
+				fmt.Sprintf("threshold must be a number between %g and 1", musicOps.MinLyricsSimilarity),
+			)
+		}
+		duplicateFilter.MinSimilarity = threshold
+	}
+
+	return duplicateFilter, nil
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	httpError "github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/reqvalidator"
+	"github.com/jumayevgadam/music-app/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+)
+
+// ListLyricVariants handler is
+func (sh *SongHandler) ListLyricVariants() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][ListLyricVariants]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][ListLyricVariants]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ListLyricVariants]")
+			return httpError.Write(c, err)
+		}
+
+		variants, err := sh.service.ListLyricVariants(ctx, songID)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ListLyricVariants]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, variants)
+	}
+}
+
+// GetLyricVariant handler is
+func (sh *SongHandler) GetLyricVariant() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][GetLyricVariant]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][GetLyricVariant]")
+		defer span.End()
+
+		songID, language, err := lyricVariantParams(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][GetLyricVariant]")
+			return httpError.Write(c, err)
+		}
+
+		variant, err := sh.service.GetLyricVariant(ctx, songID, language)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][GetLyricVariant]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, variant)
+	}
+}
+
+// PutLyricVariant handler is, body is {"text": "...", "original": false}, language
+// is BCP 47 tag of path. If-Match with song ETag is required, variants are part of song.
+func (sh *SongHandler) PutLyricVariant() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][PutLyricVariant]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][PutLyricVariant]")
+		defer span.End()
+
+		songID, language, err := lyricVariantParams(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][PutLyricVariant]")
+			return httpError.Write(c, err)
+		}
+
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][PutLyricVariant]")
+			return httpError.Write(c, err)
+		}
+
+		var variantRequest songModel.LyricVariantDTO
+		if err := reqvalidator.ReadRequest(c, &variantRequest); err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][PutLyricVariant]")
+			return httpError.Write(c, err)
+		}
+		variantRequest.Language = language
+
+		variant, songVersion, err := sh.service.PutLyricVariant(ctx, songID, version, &variantRequest)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][PutLyricVariant]")
+			return httpError.Write(c, err)
+		}
+
+		setSongETag(c, songVersion)
+		return c.JSON(http.StatusOK, variant)
+	}
+}
+
+// DeleteLyricVariant handler is, If-Match with song ETag is required
+func (sh *SongHandler) DeleteLyricVariant() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][DeleteLyricVariant]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][DeleteLyricVariant]")
+		defer span.End()
+
+		songID, language, err := lyricVariantParams(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][DeleteLyricVariant]")
+			return httpError.Write(c, err)
+		}
+
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][DeleteLyricVariant]")
+			return httpError.Write(c, err)
+		}
+
+		songVersion, err := sh.service.DeleteLyricVariant(ctx, songID, version, language)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][DeleteLyricVariant]")
+			return httpError.Write(c, err)
+		}
+
+		setSongETag(c, songVersion)
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// lyricVariantParams parses song id and canonical language of path
+func lyricVariantParams(c echo.Context) (int, string, error) {
+	songID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, "", err
+	}
+
+	language, err := musicOps.CanonicalLanguage(c.Param("language"))
+	if err != nil {
+		return 0, "", httpError.NewBadRequestError("language must be BCP 47 tag, e.g. tk, ru or en-US")
+	}
+
+	return songID, language, nil
+}
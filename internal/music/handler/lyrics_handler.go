@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	httpError "github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/lrc"
+	"github.com/jumayevgadam/music-app/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+)
+
+// GetSongLyrics handler is, format=json (default) returns text with synced lines,
+// format=lrc returns LRC file and format=plain returns text only. Language is
+// negotiated by Accept-Language, lang=ru query param overrides it.
+func (sh *SongHandler) GetSongLyrics() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][GetSongLyrics]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][GetSongLyrics]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][GetSongLyrics]")
+			return httpError.Write(c, err)
+		}
+
+		format := strings.ToLower(c.QueryParam("format"))
+		if format == "" {
+			format = songModel.LyricsFormatJSON
+		}
+		if !slices.Contains(songModel.LyricsFormats, format) {
+			return httpError.Write(c, httpError.NewBadQueryParamsError(
+				"format must be one of "+strings.Join(songModel.LyricsFormats, ", "),
+			))
+		}
+
+		acceptLanguage := c.Request().Header.Get("Accept-Language")
+		if lang := c.QueryParam("lang"); lang != "" {
+			acceptLanguage = lang
+		}
+
+		songLyrics, err := sh.service.GetSongLyrics(ctx, songID, acceptLanguage)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][GetSongLyrics]")
+			return httpError.Write(c, err)
+		}
+
+		c.Response().Header().Add(echo.HeaderVary, "Accept-Language")
+		if songLyrics.Language != "" {
+			c.Response().Header().Set("Content-Language", songLyrics.Language)
+		}
+		setSongETag(c, songLyrics.Version)
+		if ifNoneMatch(c, songLyrics.Version) {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		switch format {
+		case songModel.LyricsFormatLRC:
+			if len(songLyrics.Lines) == 0 {
+				return httpError.Write(c, httpError.NotFound(musicOps.ErrNoSyncedLyrics.Error(), musicOps.ErrNoSyncedLyrics))
+			}
+
+			lyrics := &lrc.Lyrics{
+				Tags:  []lrc.Tag{{Key: "ar", Value: songLyrics.Group}, {Key: "ti", Value: songLyrics.Title}},
+				Lines: songModel.LyricLinesToLRC(songLyrics.Lines),
+			}
+			return c.String(http.StatusOK, lyrics.String())
+		case songModel.LyricsFormatPlain:
+			return c.String(http.StatusOK, songLyrics.Text)
+		default:
+			return c.JSON(http.StatusOK, songLyrics)
+		}
+	}
+}
+
+// PutSongLyrics handler is, body is LRC file, text of song becomes its plain
+// text. If-Match with song ETag is required as for other song writes.
+func (sh *SongHandler) PutSongLyrics() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][PutSongLyrics]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][PutSongLyrics]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][PutSongLyrics]")
+			return httpError.Write(c, err)
+		}
+
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][PutSongLyrics]")
+			return httpError.Write(c, err)
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request().Body, musicOps.MaxLyricsSize+1))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][PutSongLyrics]")
+			return httpError.Write(c, httpError.NewBadRequestError(err.Error()))
+		}
+		if len(body) > musicOps.MaxLyricsSize {
+			return httpError.Write(c, httpError.NewRestError(
+				http.StatusRequestEntityTooLarge, "request entity too large", "lyrics must be at most 1MiB",
+			))
+		}
+
+		lyrics, err := lrc.Parse(bytes.NewReader(body))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][PutSongLyrics]")
+			return httpError.Write(c, httpError.NewBadRequestError(err.Error()))
+		}
+
+		songLyrics, err := sh.service.PutSongLyrics(ctx, songID, version, lyrics)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][PutSongLyrics]")
+			return httpError.Write(c, err)
+		}
+
+		setSongETag(c, songLyrics.Version)
+		return c.JSON(http.StatusOK, songLyrics)
+	}
+}
+
+// GetLyricLineAt handler is, t is time from start of song in seconds (83.5)
+// or as LRC timestamp (01:23.50)
+func (sh *SongHandler) GetLyricLineAt() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][GetLyricLineAt]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][GetLyricLineAt]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][GetLyricLineAt]")
+			return httpError.Write(c, err)
+		}
+
+		at, err := parseLyricsTime(c.QueryParam("t"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][GetLyricLineAt]")
+			return httpError.Write(c, httpError.NewBadQueryParamsError(err.Error()))
+		}
+
+		lineAt, err := sh.service.GetLyricLineAt(ctx, songID, at)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][GetLyricLineAt]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, lineAt)
+	}
+}
+
+// parseLyricsTime is
+func parseLyricsTime(raw string) (time.Duration, error) {
+	if raw == "" {
+		return 0, errors.New("t is required, e.g. t=83.5 or t=01:23.50")
+	}
+
+	if seconds, err := strconv.ParseFloat(raw, 64); err == nil {
+		if seconds < 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+			return 0, fmt.Errorf("t must not be negative, got %q", raw)
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+
+	at, err := lrc.ParseTimestamp(raw)
+	if err != nil {
+		return 0, fmt.Errorf("t must be seconds or mm:ss.xx, got %q", raw)
+	}
+
+	return at, nil
+}
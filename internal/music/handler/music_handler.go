@@ -1,27 +1,41 @@
 package handler
 
 import (
+	"bufio"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/jumayevgadam/music-app/internal/config"
 	songModel "github.com/jumayevgadam/music-app/internal/models"
 	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/internal/music/songio"
 	httpError "github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/filter"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
 	"github.com/jumayevgadam/music-app/pkg/reqvalidator"
 	"github.com/jumayevgadam/music-app/pkg/tracing"
+	"github.com/jumayevgadam/music-app/pkg/urlsign"
 	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
 )
 
-// SongHandler struct is
+// SongHandler struct is, storage limits uploaded files and signs stream URLs
 type SongHandler struct {
 	service musicOps.Service
+	storage config.Storage
+	signer  *urlsign.Signer
 }
 
 // NewSongHandler method is
-func NewSongHandler(service musicOps.Service) *SongHandler {
-	return &SongHandler{service: service}
+func NewSongHandler(service musicOps.Service, storage config.Storage) *SongHandler {
+	return &SongHandler{service: service, storage: storage, signer: urlsign.New([]byte(storage.URLSecret))}
 }
 
+// AddSong handler is
 func (sh *SongHandler) AddSong() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		tracer := otel.Tracer("[SongHandler][AddSong]")
@@ -31,15 +45,398 @@ func (sh *SongHandler) AddSong() echo.HandlerFunc {
 		var songRequest songModel.DTO
 		if err := reqvalidator.ReadRequest(c, &songRequest); err != nil {
 			tracing.EventErrorTracer(span, err, "[SongHandler][AddSong]")
-			return c.JSON(httpError.Response(err))
+			return httpError.Write(c, err)
 		}
 
 		songID, err := sh.service.AddSong(ctx, &songRequest)
 		if err != nil {
 			tracing.EventErrorTracer(span, err, "[SongHandler][AddSong]")
-			return c.JSON(httpError.Response(err))
+			return httpError.Write(c, err)
 		}
 
 		return c.JSON(http.StatusOK, songID)
 	}
 }
+
+// GetSong handler is, responds with ETag of song version and 304 when
+// If-None-Match still matches it
+func (sh *SongHandler) GetSong() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][GetSong]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][GetSong]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][GetSong]")
+			return httpError.Write(c, err)
+		}
+
+		song, err := sh.service.GetSong(ctx, songID)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][GetSong]")
+			return httpError.Write(c, err)
+		}
+
+		setSongETag(c, song.Version)
+		if ifNoneMatch(c, song.Version) {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		return c.JSON(http.StatusOK, song)
+	}
+}
+
+// UpdateSong handler is, replaces song with body, If-Match with ETag of song is required
+// and stale version is rejected with 412
+func (sh *SongHandler) UpdateSong() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][UpdateSong]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][UpdateSong]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][UpdateSong]")
+			return httpError.Write(c, err)
+		}
+
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][UpdateSong]")
+			return httpError.Write(c, err)
+		}
+
+		var songRequest songModel.DTO
+		if err := reqvalidator.ReadRequest(c, &songRequest); err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][UpdateSong]")
+			return httpError.Write(c, err)
+		}
+		songRequest.ID, songRequest.Version = songID, version
+
+		song, err := sh.service.UpdateSong(ctx, &songRequest)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][UpdateSong]")
+			return httpError.Write(c, err)
+		}
+
+		setSongETag(c, song.Version)
+		return c.JSON(http.StatusOK, song)
+	}
+}
+
+// PatchSong handler is, changes only fields given in body, If-Match works as in UpdateSong
+func (sh *SongHandler) PatchSong() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][PatchSong]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][PatchSong]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][PatchSong]")
+			return httpError.Write(c, err)
+		}
+
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][PatchSong]")
+			return httpError.Write(c, err)
+		}
+
+		var patch songModel.SongPatchDTO
+		if err := reqvalidator.ReadRequest(c, &patch); err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][PatchSong]")
+			return httpError.Write(c, err)
+		}
+
+		song, err := sh.service.PatchSong(ctx, songID, version, &patch)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][PatchSong]")
+			return httpError.Write(c, err)
+		}
+
+		setSongETag(c, song.Version)
+		return c.JSON(http.StatusOK, song)
+	}
+}
+
+// DeleteSong handler is, If-Match works as in UpdateSong
+func (sh *SongHandler) DeleteSong() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][DeleteSong]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][DeleteSong]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][DeleteSong]")
+			return httpError.Write(c, err)
+		}
+
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][DeleteSong]")
+			return httpError.Write(c, err)
+		}
+
+		if err := sh.service.DeleteSong(ctx, songID, version); err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][DeleteSong]")
+			return httpError.Write(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// ListSongs handler is, supports page/size and cursor pagination, orderBy=-release_date,title,
+// filter=release_date>=2000-01-01;group~muse,title=Hysteria and link_status=broken
+func (sh *SongHandler) ListSongs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][ListSongs]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][ListSongs]")
+		defer span.End()
+
+		paginationQuery, err := pagination.GetPaginationFromCtx(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ListSongs]")
+			return httpError.Write(c, err)
+		}
+
+		if err := paginationQuery.SetSort(musicOps.SongSortFields, musicOps.DefaultSongSort); err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ListSongs]")
+			return httpError.Write(c, err)
+		}
+
+		songFilter, err := filter.Parse(c.QueryParam("filter"), musicOps.SongFilterFields)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ListSongs]")
+			return httpError.Write(c, err)
+		}
+
+		// link_status=broken is shortcut of filter=link_status=broken
+		if linkStatus := c.QueryParam("link_status"); linkStatus != "" {
+			if !slices.Contains(songModel.LinkStatuses, linkStatus) {
+				return httpError.Write(c, httpError.NewBadQueryParamsError(
+					"link_status must be one of "+strings.Join(songModel.LinkStatuses, ", "),
+				))
+			}
+
+			statusFilter, err := filter.Parse("link_status="+linkStatus, musicOps.SongFilterFields)
+			if err != nil {
+				tracing.EventErrorTracer(span, err, "[SongHandler][ListSongs]")
+				return httpError.Write(c, err)
+			}
+			songFilter = filter.And(songFilter, statusFilter)
+		}
+
+		songs, err := sh.service.ListSongs(ctx, songFilter, paginationQuery)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ListSongs]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, songs)
+	}
+}
+
+// ImportSongs handler is, body is streamed CSV (with header) or NDJSON, format
+// comes from format query param or Content-Type. dry_run=true only validates rows.
+func (sh *SongHandler) ImportSongs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][ImportSongs]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][ImportSongs]")
+		defer span.End()
+
+		format, err := songio.ParseFormat(c.QueryParam("format"), c.Request().Header.Get(echo.HeaderContentType))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ImportSongs]")
+			return httpError.Write(c, httpError.NewBadQueryParamsError(err.Error()))
+		}
+
+		var dryRun bool
+		if raw := c.QueryParam("dry_run"); raw != "" {
+			if dryRun, err = strconv.ParseBool(raw); err != nil {
+				tracing.EventErrorTracer(span, err, "[SongHandler][ImportSongs]")
+				return httpError.Write(c, httpError.NewBadQueryParamsError("dry_run must be true or false"))
+			}
+		}
+
+		rows, err := songio.NewReader(format, c.Request().Body)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ImportSongs]")
+			return httpError.Write(c, httpError.NewBadRequestError(err.Error()))
+		}
+
+		report, err := sh.service.ImportSongs(ctx, rows, dryRun)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ImportSongs]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, report)
+	}
+}
+
+// ListSongHistory handler is, returns who changed song, when and what, newest first
+func (sh *SongHandler) ListSongHistory() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][ListSongHistory]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][ListSongHistory]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ListSongHistory]")
+			return httpError.Write(c, err)
+		}
+
+		history, err := sh.service.ListSongHistory(ctx, songID)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ListSongHistory]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, history)
+	}
+}
+
+// RestoreSong handler is, brings song back to snapshot of history entry
+func (sh *SongHandler) RestoreSong() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][RestoreSong]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][RestoreSong]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][RestoreSong]")
+			return httpError.Write(c, err)
+		}
+
+		historyID, err := strconv.ParseInt(c.Param("history_id"), 10, 64)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][RestoreSong]")
+			return httpError.Write(c, err)
+		}
+
+		song, err := sh.service.RestoreSong(ctx, songID, historyID)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][RestoreSong]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, song)
+	}
+}
+
+// exportFlushRows is number of rows after which export output is flushed to client
+const exportFlushRows = 500
+
+// ExportSongs handler is, streams songs matching filter as csv, ndjson or json.
+// Columns are selected with columns=id,title, default order is by id.
+func (sh *SongHandler) ExportSongs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][ExportSongs]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][ExportSongs]")
+		defer span.End()
+
+		formatName := c.QueryParam("format")
+		if formatName == "" {
+			formatName = string(songio.FormatCSV)
+		}
+
+		format, err := songio.ParseFormat(formatName, "")
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ExportSongs]")
+			return httpError.Write(c, httpError.NewBadQueryParamsError(err.Error()))
+		}
+
+		columns, err := songio.ParseColumns(c.QueryParam("columns"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ExportSongs]")
+			return httpError.Write(c, httpError.NewBadQueryParamsError(err.Error()))
+		}
+
+		songFilter, err := filter.Parse(c.QueryParam("filter"), musicOps.SongFilterFields)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ExportSongs]")
+			return httpError.Write(c, err)
+		}
+
+		sort, err := pagination.ParseSort(c.QueryParam("orderBy"), musicOps.SongSortFields, "id")
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ExportSongs]")
+			return httpError.Write(c, err)
+		}
+
+		// nothing reaches client till first flush, so early errors still get proper status
+		response := c.Response()
+		response.Header().Set(echo.HeaderContentType, format.ContentType())
+		response.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="songs.%s"`, format))
+
+		output := &flushWriter{buf: bufio.NewWriterSize(response, 64*1024), response: response}
+		writer, err := songio.NewWriter(format, output, columns)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ExportSongs]")
+			return httpError.Write(c, err)
+		}
+
+		err = sh.service.ExportSongs(ctx, songFilter, sort, columns, &flushingRowWriter{RowWriter: writer, output: output})
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][ExportSongs]")
+			if !response.Committed {
+				response.Header().Del(echo.HeaderContentType)
+				response.Header().Del(echo.HeaderContentDisposition)
+				return httpError.Write(c, err)
+			}
+
+			// status is already sent, abort connection so client doesn't take truncated dump as complete
+			logrus.Errorf("[SongHandler][ExportSongs]: export failed after %d bytes: %v", response.Size, err)
+			panic(http.ErrAbortHandler)
+		}
+
+		return output.Flush()
+	}
+}
+
+// flushWriter buffers export output and flushes it to client on demand
+type flushWriter struct {
+	buf      *bufio.Writer
+	response *echo.Response
+}
+
+// Write is
+func (w *flushWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+// Flush is
+func (w *flushWriter) Flush() error {
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+	w.response.Flush()
+
+	return nil
+}
+
+// flushingRowWriter flushes output every exportFlushRows rows
+type flushingRowWriter struct {
+	songio.RowWriter
+	output *flushWriter
+	rows   int
+}
+
+// Write is
+func (w *flushingRowWriter) Write(values []interface{}) error {
+	if err := w.RowWriter.Write(values); err != nil {
+		return err
+	}
+
+	if w.rows++; w.rows%exportFlushRows == 0 {
+		return w.output.Flush()
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	httpError "github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/reqvalidator"
+	"github.com/jumayevgadam/music-app/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+)
+
+// RecordPlay handler is, body with duration_ms and source is optional
+func (sh *SongHandler) RecordPlay() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][RecordPlay]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][RecordPlay]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][RecordPlay]")
+			return httpError.Write(c, err)
+		}
+
+		var playRequest songModel.SongPlayDTO
+		if err := reqvalidator.ReadRequest(c, &playRequest); err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][RecordPlay]")
+			return httpError.Write(c, err)
+		}
+
+		if err := sh.service.RecordPlay(ctx, songID, &playRequest); err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][RecordPlay]")
+			return httpError.Write(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// TopSongs handler is, supports period=day|week|month|year|all (week by default),
+// group and limit
+func (sh *SongHandler) TopSongs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][TopSongs]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][TopSongs]")
+		defer span.End()
+
+		limit, err := chartLimitParam(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][TopSongs]")
+			return httpError.Write(c, err)
+		}
+
+		period := c.QueryParam("period")
+		if period == "" {
+			period = "week"
+		}
+
+		chart, err := sh.service.TopSongs(ctx, period, c.QueryParam("group"), limit)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][TopSongs]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, chart)
+	}
+}
+
+// TrendingSongs handler is, supports group and limit
+func (sh *SongHandler) TrendingSongs() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][TrendingSongs]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][TrendingSongs]")
+		defer span.End()
+
+		limit, err := chartLimitParam(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][TrendingSongs]")
+			return httpError.Write(c, err)
+		}
+
+		chart, err := sh.service.TrendingSongs(ctx, c.QueryParam("group"), limit)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][TrendingSongs]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, chart)
+	}
+}
+
+// chartLimitParam returns zero when limit isn't given, service picks default then
+func chartLimitParam(c echo.Context) (int, error) {
+	limitParam := c.QueryParam("limit")
+	if limitParam == "" {
+		return 0, nil
+	}
+
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil {
+		return 0, httpError.NewBadQueryParamsError("limit must be integer")
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	httpError "github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+)
+
+// StreamSongAudio handler is, serves audio file with Range, If-Range and conditional
+// requests. ETag is checksum of file, so it changes only when other file is uploaded.
+func (sh *SongHandler) StreamSongAudio() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][StreamSongAudio]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][StreamSongAudio]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][StreamSongAudio]")
+			return httpError.Write(c, err)
+		}
+
+		audio, object, err := sh.service.OpenSongAudio(ctx, songID)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][StreamSongAudio]")
+			return httpError.Write(c, err)
+		}
+		defer object.Close()
+
+		header := c.Response().Header()
+		header.Set(echo.HeaderContentType, audio.ContentType)
+		header.Set("ETag", `"`+audio.SHA256+`"`)
+		// same URL serves new file after upload, so caches revalidate by ETag
+		header.Set("Cache-Control", "no-cache")
+		if audio.Filename != "" {
+			header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": audio.Filename}))
+		}
+
+		// ServeContent answers ranges, HEAD, 304 and 412 itself
+		http.ServeContent(c.Response(), c.Request(), audio.Filename, audio.UpdatedAt, object)
+
+		return nil
+	}
+}
+
+// SignSongStreamURL handler is, returns stream URL which works without token till
+// it expires. ttl=30m sets its lifetime, default and maximum come from config.
+// Route is guarded like stream, so only clients with token can sign URLs.
+func (sh *SongHandler) SignSongStreamURL() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[SongHandler][SignSongStreamURL]")
+		ctx, span := tracer.Start(c.Request().Context(), "[SongHandler][SignSongStreamURL]")
+		defer span.End()
+
+		songID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][SignSongStreamURL]")
+			return httpError.Write(c, err)
+		}
+
+		ttl := sh.storage.SignedURLTTL
+		if raw := c.QueryParam("ttl"); raw != "" {
+			ttl, err = time.ParseDuration(raw)
+			if err != nil || ttl <= 0 || ttl > sh.storage.MaxSignedURLTTL {
+				return httpError.Write(c, httpError.NewBadQueryParamsError(
+					fmt.Sprintf("ttl must be duration between 0 and %s, e.g. 30m", sh.storage.MaxSignedURLTTL),
+				))
+			}
+		}
+
+		// URL of song without audio would only give 404
+		if _, err := sh.service.GetSongAudio(ctx, songID); err != nil {
+			tracing.EventErrorTracer(span, err, "[SongHandler][SignSongStreamURL]")
+			return httpError.Write(c, err)
+		}
+
+		path := strings.TrimSuffix(c.Request().URL.Path, "/url")
+		expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+		query := sh.signer.Sign(path, expiresAt)
+
+		return c.JSON(http.StatusOK, &songModel.SignedURLDTO{
+			URL:       c.Scheme() + "://" + c.Request().Host + path + "?" + query.Encode(),
+			ExpiresAt: expiresAt,
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package linkcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jumayevgadam/music-app/internal/config"
+	"github.com/jumayevgadam/music-app/internal/database"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Checker claims links due for check and requests them with HEAD, falling back
+// to GET for servers which don't support HEAD. Links are grouped by host, every
+// host gets one request at a time with HostDelay between them, so catalogue with
+// thousands of YouTube links doesn't hammer YouTube. Answers 2xx and 3xx after
+// redirects are healthy, throttled answers (429) aren't counted either way.
+
+const (
+	// userAgent tells site owners who checks their links
+	userAgent = "music-app-linkcheck/1.0"
+
+	// maxDrainBody is how much of GET body is read before connection is reused
+	maxDrainBody = 4 << 10
+)
+
+// errBadRequest is returned when request of link can't be built
+var errBadRequest = errors.New("can't build request of link")
+
+// Checker struct is
+type Checker struct {
+	dataStore database.DataStore
+	client    *http.Client
+	cfg       config.LinkCheck
+}
+
+// NewChecker method is
+func NewChecker(dataStore database.DataStore, cfg config.LinkCheck) *Checker {
+	return &Checker{
+		dataStore: dataStore,
+		client:    &http.Client{Timeout: cfg.Timeout},
+		cfg:       cfg,
+	}
+}
+
+// Run checks links till ctx is done
+func (ch *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(ch.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		ch.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick checks batches till fewer links than batch size are due
+func (ch *Checker) tick(ctx context.Context) {
+	for ctx.Err() == nil {
+		var jobs []*songModel.LinkCheckJobDAO
+
+		if err := ch.dataStore.WithTransaction(ctx, func(db database.DataStore) error {
+			var err error
+			jobs, err = db.SongRepo().ClaimLinkChecks(ctx, ch.cfg.BatchSize, ch.cfg.Interval)
+			return err
+		}); err != nil {
+			logrus.Errorf("[linkcheck][ClaimLinkChecks]: %v", err)
+			return
+		}
+
+		ch.checkAll(ctx, jobs)
+		if len(jobs) < ch.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// checkAll checks hosts in parallel and links of one host one by one
+func (ch *Checker) checkAll(ctx context.Context, jobs []*songModel.LinkCheckJobDAO) {
+	byHost := make(map[string][]*songModel.LinkCheckJobDAO)
+	for _, job := range jobs {
+		host := ""
+		if u, err := url.Parse(job.Link); err == nil {
+			host = strings.ToLower(u.Hostname())
+		}
+		byHost[host] = append(byHost[host], job)
+	}
+
+	hosts := make(chan []*songModel.LinkCheckJobDAO)
+	var wg sync.WaitGroup
+
+	for range min(ch.cfg.Concurrency, len(byHost)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for hostJobs := range hosts {
+				ch.checkHost(ctx, hostJobs)
+			}
+		}()
+	}
+
+	for _, hostJobs := range byHost {
+		hosts <- hostJobs
+	}
+	close(hosts)
+	wg.Wait()
+}
+
+// checkHost checks links of one host keeping politeness delay between them
+func (ch *Checker) checkHost(ctx context.Context, jobs []*songModel.LinkCheckJobDAO) {
+	for i, job := range jobs {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(ch.cfg.HostDelay):
+			}
+		}
+
+		result, counted := ch.check(ctx, job)
+		if !counted {
+			continue
+		}
+
+		if err := ch.dataStore.SongRepo().SaveLinkCheck(ctx, result, ch.cfg.BrokenAfter); err != nil {
+			logrus.Errorf("[linkcheck][SaveLinkCheck]: song %d: %v", job.SongID, err)
+		}
+	}
+}
+
+// check requests link, counted is false when result shouldn't change link health
+func (ch *Checker) check(ctx context.Context, job *songModel.LinkCheckJobDAO) (*songModel.LinkCheckResultDAO, bool) {
+	result := &songModel.LinkCheckResultDAO{SongID: job.SongID, Link: job.Link}
+
+	statusCode, err := ch.request(ctx, http.MethodHead, job.Link)
+	// nothing was fetched, so link is neither healthy nor broken
+	if errors.Is(err, errBadRequest) {
+		logrus.Warnf("[linkcheck][check]: song %d: %v", job.SongID, err)
+		return nil, false
+	}
+	// many servers answer HEAD with 403, 404 or 405 while GET works
+	if err == nil && statusCode >= http.StatusBadRequest && statusCode != http.StatusTooManyRequests {
+		statusCode, err = ch.request(ctx, http.MethodGet, job.Link)
+	}
+
+	if err != nil {
+		// stopping server isn't failure of link
+		if ctx.Err() != nil {
+			return nil, false
+		}
+		logrus.Debugf("[linkcheck][check]: song %d: %v", job.SongID, err)
+		result.Failed = true
+		return result, true
+	}
+
+	if statusCode == http.StatusTooManyRequests {
+		return nil, false
+	}
+
+	result.StatusCode = &statusCode
+	result.Failed = statusCode >= http.StatusBadRequest
+
+	return result, true
+}
+
+// request returns status code of final response, client follows redirects
+func (ch *Checker) request(ctx context.Context, method, link string) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", errBadRequest, err)
+	}
+	request.Header.Set("User-Agent", userAgent)
+
+	response, err := ch.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxDrainBody))
+
+	return response.StatusCode, nil
+}
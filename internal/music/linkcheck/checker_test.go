@@ -0,0 +1,160 @@
+package linkcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jumayevgadam/music-app/internal/config"
+	"github.com/jumayevgadam/music-app/internal/database"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/internal/music"
+)
+
+var testConfig = config.LinkCheck{
+	Timeout:     time.Second,
+	Concurrency: 2,
+	BrokenAfter: 3,
+}
+
+// fakeStore is DataStore saving results of checks, methods tests don't
+// need panic through nil embedded interface
+type fakeStore struct {
+	database.DataStore
+	songs *fakeSongRepo
+}
+
+func (f *fakeStore) SongRepo() music.Repository {
+	return f.songs
+}
+
+type fakeSongRepo struct {
+	music.Repository
+	results     []*songModel.LinkCheckResultDAO
+	brokenAfter int
+}
+
+func (r *fakeSongRepo) SaveLinkCheck(ctx context.Context, result *songModel.LinkCheckResultDAO, brokenAfter int) error {
+	r.results = append(r.results, result)
+	r.brokenAfter = brokenAfter
+
+	return nil
+}
+
+// newServer answers HEAD and GET with given status codes
+func newServer(t *testing.T, head, get int) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != userAgent {
+			t.Errorf("User-Agent = %q, want %q", r.Header.Get("User-Agent"), userAgent)
+		}
+
+		if r.Method == http.MethodHead {
+			w.WriteHeader(head)
+			return
+		}
+		w.WriteHeader(get)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		head, get  int
+		counted    bool
+		failed     bool
+		statusCode int
+	}{
+		{name: "ok", head: http.StatusOK, get: http.StatusInternalServerError, counted: true, statusCode: http.StatusOK},
+		{name: "head not allowed", head: http.StatusMethodNotAllowed, get: http.StatusOK, counted: true, statusCode: http.StatusOK},
+		{name: "not found", head: http.StatusNotFound, get: http.StatusNotFound, counted: true, failed: true, statusCode: http.StatusNotFound},
+		{name: "server error", head: http.StatusBadGateway, get: http.StatusBadGateway, counted: true, failed: true, statusCode: http.StatusBadGateway},
+		{name: "throttled head", head: http.StatusTooManyRequests, get: http.StatusOK},
+		{name: "throttled get", head: http.StatusForbidden, get: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := newServer(t, tt.head, tt.get)
+
+			result, counted := NewChecker(nil, testConfig).check(context.Background(), &songModel.LinkCheckJobDAO{SongID: 1, Link: link})
+			if counted != tt.counted {
+				t.Fatalf("counted = %v, want %v", counted, tt.counted)
+			}
+			if !counted {
+				return
+			}
+
+			if result.SongID != 1 || result.Link != link || result.Failed != tt.failed ||
+				result.StatusCode == nil || *result.StatusCode != tt.statusCode {
+				t.Fatalf("result = %+v, want failed %v with status %d", result, tt.failed, tt.statusCode)
+			}
+		})
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	target := newServer(t, http.StatusNotFound, http.StatusNotFound)
+	server := httptest.NewServer(http.RedirectHandler(target, http.StatusMovedPermanently))
+	defer server.Close()
+
+	// health is status of final response
+	result, counted := NewChecker(nil, testConfig).check(context.Background(), &songModel.LinkCheckJobDAO{SongID: 1, Link: server.URL})
+	if !counted || !result.Failed || *result.StatusCode != http.StatusNotFound {
+		t.Fatalf("result = %+v, want failed with 404", result)
+	}
+}
+
+func TestCheckNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	result, counted := NewChecker(nil, testConfig).check(context.Background(), &songModel.LinkCheckJobDAO{SongID: 1, Link: server.URL})
+	if !counted || !result.Failed || result.StatusCode != nil {
+		t.Fatalf("result = %+v, want failed without status code", result)
+	}
+
+	// stopping checker doesn't count against link
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, counted := NewChecker(nil, testConfig).check(ctx, &songModel.LinkCheckJobDAO{SongID: 1, Link: server.URL}); counted {
+		t.Fatal("check of stopped checker was counted")
+	}
+}
+
+func TestCheckBadLink(t *testing.T) {
+	// nothing was requested, so link health doesn't change
+	if _, counted := NewChecker(nil, testConfig).check(context.Background(), &songModel.LinkCheckJobDAO{SongID: 1, Link: "http://bad host/"}); counted {
+		t.Fatal("check of link which can't be requested was counted")
+	}
+}
+
+func TestCheckAllSavesCountedResults(t *testing.T) {
+	store := &fakeStore{songs: &fakeSongRepo{}}
+	ok := newServer(t, http.StatusOK, http.StatusOK)
+	throttled := newServer(t, http.StatusTooManyRequests, http.StatusTooManyRequests)
+	gone := newServer(t, http.StatusGone, http.StatusGone)
+
+	NewChecker(store, testConfig).checkAll(context.Background(), []*songModel.LinkCheckJobDAO{
+		{SongID: 1, Link: ok},
+		{SongID: 2, Link: throttled},
+		{SongID: 3, Link: gone},
+	})
+
+	failed := make(map[int]bool)
+	for _, result := range store.songs.results {
+		failed[result.SongID] = result.Failed
+	}
+	if len(failed) != 2 || failed[1] || !failed[3] {
+		t.Fatalf("saved results = %v, want song 1 ok and song 3 failed", failed)
+	}
+	if store.songs.brokenAfter != testConfig.BrokenAfter {
+		t.Fatalf("brokenAfter = %d, want %d", store.songs.brokenAfter, testConfig.BrokenAfter)
+	}
+}
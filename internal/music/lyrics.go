@@ -0,0 +1,44 @@
+package music
+
+import (
+	"errors"
+
+	"golang.org/x/text/language"
+)
+
+// MaxLyricsSize limits uploaded LRC file
+const MaxLyricsSize = 1 << 20
+
+var (
+	// ErrNoSyncedLyrics is returned when timings of song are asked but it has only plain text
+	ErrNoSyncedLyrics = errors.New("song has no synced lyrics")
+	// ErrOriginalLyricVariant is returned when original variant would be deleted or unmarked,
+	// another variant must be marked as original instead
+	ErrOriginalLyricVariant = errors.New("original lyric variant can't be deleted or unmarked, mark another variant as original")
+)
+
+// CanonicalLanguage parses BCP 47 tag and returns its canonical form, e.g. en-us gives en-US
+func CanonicalLanguage(raw string) (string, error) {
+	tag, err := language.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+
+	return tag.String(), nil
+}
+
+// MatchLanguage returns index of supported language best matching Accept-Language
+// header, first supported language is fallback when nothing matches
+func MatchLanguage(acceptLanguage string, supported []language.Tag) int {
+	desired, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(desired) == 0 || len(supported) == 0 {
+		return 0
+	}
+
+	_, index, confidence := language.NewMatcher(supported).Match(desired...)
+	if confidence == language.No {
+		return 0
+	}
+
+	return index
+}
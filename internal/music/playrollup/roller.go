@@ -0,0 +1,93 @@
+package playrollup
+
+import (
+	"context"
+	"time"
+
+	"github.com/jumayevgadam/music-app/internal/config"
+	"github.com/jumayevgadam/music-app/internal/database"
+	"github.com/sirupsen/logrus"
+)
+
+// Roller adds plays to hourly and daily counts. Rolled up period is one row
+// locked for update, so many app instances can run Roller and every play is
+// counted once. Plays are rolled up only when they are older than RollupLag,
+// because play row becomes visible when its transaction commits, which may be
+// after played_at. Long backlog after downtime is rolled up in RollupSpan steps,
+// one transaction each.
+
+// Roller struct is
+type Roller struct {
+	dataStore database.DataStore
+	cfg       config.Plays
+}
+
+// NewRoller method is
+func NewRoller(dataStore database.DataStore, cfg config.Plays) *Roller {
+	return &Roller{
+		dataStore: dataStore,
+		cfg:       cfg,
+	}
+}
+
+// Run rolls up plays till ctx is done
+func (r *Roller) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.RollupInterval)
+	defer ticker.Stop()
+
+	for {
+		r.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick rolls up spans till it catches up with now minus lag
+func (r *Roller) tick(ctx context.Context) {
+	for ctx.Err() == nil {
+		caughtUp, err := r.rollup(ctx)
+		if err != nil {
+			logrus.Errorf("[playrollup][RollupPlays]: %v", err)
+			return
+		}
+
+		if caughtUp {
+			return
+		}
+	}
+}
+
+// rollup rolls up one span, caughtUp is true when no later plays are due
+func (r *Roller) rollup(ctx context.Context) (bool, error) {
+	caughtUp := true
+
+	err := r.dataStore.WithTransaction(ctx, func(db database.DataStore) error {
+		from, err := db.SongRepo().LockPlayRollup(ctx)
+		if err != nil {
+			return err
+		}
+
+		to := time.Now().Add(-r.cfg.RollupLag)
+		if !to.After(from) {
+			return nil
+		}
+		if spanEnd := from.Add(r.cfg.RollupSpan); spanEnd.Before(to) {
+			to = spanEnd
+			caughtUp = false
+		}
+
+		plays, err := db.SongRepo().RollupPlays(ctx, from, to)
+		if err != nil {
+			return err
+		}
+		logrus.Debugf("[playrollup][RollupPlays]: %d plays of [%s, %s)", plays, from.Format(time.RFC3339), to.Format(time.RFC3339))
+
+		return db.SongRepo().SetPlayRollup(ctx, to)
+	})
+
+	return caughtUp, err
+}
@@ -0,0 +1,117 @@
+package playrollup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jumayevgadam/music-app/internal/config"
+	"github.com/jumayevgadam/music-app/internal/database"
+	"github.com/jumayevgadam/music-app/internal/music"
+)
+
+var testConfig = config.Plays{
+	RollupInterval: time.Minute,
+	RollupLag:      time.Minute,
+	RollupSpan:     time.Hour,
+}
+
+// fakeStore is DataStore keeping rolled up time, methods tests don't
+// need panic through nil embedded interface
+type fakeStore struct {
+	database.DataStore
+	songs        *fakeSongRepo
+	transactions int
+}
+
+func (f *fakeStore) SongRepo() music.Repository {
+	return f.songs
+}
+
+func (f *fakeStore) WithTransaction(ctx context.Context, tx database.Transaction) error {
+	f.transactions++
+	return tx(f)
+}
+
+// fakeSongRepo records rolled up spans
+type fakeSongRepo struct {
+	music.Repository
+	rolledUntil time.Time
+	spans       [][2]time.Time
+	err         error
+}
+
+func (r *fakeSongRepo) LockPlayRollup(ctx context.Context) (time.Time, error) {
+	return r.rolledUntil, nil
+}
+
+func (r *fakeSongRepo) RollupPlays(ctx context.Context, from, to time.Time) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	r.spans = append(r.spans, [2]time.Time{from, to})
+
+	return 1, nil
+}
+
+func (r *fakeSongRepo) SetPlayRollup(ctx context.Context, rolledUntil time.Time) error {
+	r.rolledUntil = rolledUntil
+	return nil
+}
+
+func TestTickCatchesUpBySpans(t *testing.T) {
+	from := time.Now().Add(-150 * time.Minute)
+	store := &fakeStore{songs: &fakeSongRepo{rolledUntil: from}}
+
+	before := time.Now()
+	NewRoller(store, testConfig).tick(context.Background())
+
+	spans := store.songs.spans
+	if len(spans) != 3 || store.transactions != 3 {
+		t.Fatalf("rolled up %d spans in %d transactions, want 3 of each", len(spans), store.transactions)
+	}
+
+	// spans follow each other, full ones are RollupSpan long and last one ends lag before now
+	for i, span := range spans {
+		if i > 0 && !span[0].Equal(spans[i-1][1]) {
+			t.Fatalf("span %d starts at %v, want end of previous span %v", i, span[0], spans[i-1][1])
+		}
+		if i < len(spans)-1 && span[1].Sub(span[0]) != testConfig.RollupSpan {
+			t.Fatalf("span %d is %v long, want %v", i, span[1].Sub(span[0]), testConfig.RollupSpan)
+		}
+	}
+	if !spans[0][0].Equal(from) {
+		t.Fatalf("first span starts at %v, want %v", spans[0][0], from)
+	}
+
+	last := spans[len(spans)-1][1]
+	if last.Before(before.Add(-testConfig.RollupLag)) || last.After(time.Now().Add(-testConfig.RollupLag)) {
+		t.Fatalf("last span ends at %v, want %v before now", last, testConfig.RollupLag)
+	}
+	if !store.songs.rolledUntil.Equal(last) {
+		t.Fatalf("rolled until %v, want %v", store.songs.rolledUntil, last)
+	}
+}
+
+func TestTickWithinLag(t *testing.T) {
+	// plays newer than lag may still be inserted
+	store := &fakeStore{songs: &fakeSongRepo{rolledUntil: time.Now().Add(-testConfig.RollupLag / 2)}}
+
+	NewRoller(store, testConfig).tick(context.Background())
+
+	if len(store.songs.spans) != 0 || store.transactions != 1 {
+		t.Fatalf("rolled up %v, want nothing", store.songs.spans)
+	}
+}
+
+func TestTickStopsOnError(t *testing.T) {
+	from := time.Now().Add(-10 * time.Hour)
+	store := &fakeStore{songs: &fakeSongRepo{rolledUntil: from, err: errors.New("database is down")}}
+
+	NewRoller(store, testConfig).tick(context.Background())
+
+	if store.transactions != 1 || !store.songs.rolledUntil.Equal(from) {
+		t.Fatalf("%d transactions, rolled until %v, want one failed attempt", store.transactions, store.songs.rolledUntil)
+	}
+}
@@ -2,7 +2,11 @@ package music
 
 import (
 	"context"
+	"time"
+
 	songModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/pkg/filter"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
 )
 
 // write needed methods for repository layer
@@ -10,9 +14,49 @@ import (
 // Repository is
 type Repository interface {
 	AddSong(ctx context.Context, daoModel *songModel.DAO) (int, error)
-	//GetSongByID(ctx context.Context, id int) (*songModel.DAO, error)
-	//GetSongByName(ctx context.Context, name string) (*songModel.DAO, error)
-	//GetAllSongs(ctx context.Context) ([]*songModel.DAO, error)
-	//UpdateSong(ctx context.Context, songID int) (string, error)
-	//DeleteSong(ctx context.Context, songID int) (string, error)
+	CountSongs(ctx context.Context, songFilter *filter.Filter) (int, error)
+	ListUnenrichedSongs(ctx context.Context, limit int) ([]*songModel.DAO, error)
+	SaveLinkMetadata(ctx context.Context, metadata *songModel.LinkMetadataDAO) error
+	ClaimLinkChecks(ctx context.Context, limit int, interval time.Duration) ([]*songModel.LinkCheckJobDAO, error)
+	SaveLinkCheck(ctx context.Context, result *songModel.LinkCheckResultDAO, brokenAfter int) error
+	ListSongDuplicates(ctx context.Context, duplicateFilter songModel.DuplicateFilterDAO, paginationQuery *pagination.PaginationQuery) ([]*songModel.SongDuplicateDAO, error)
+	ExportSongs(ctx context.Context, songFilter *filter.Filter, sort pagination.SortSpec, columns []string, fn func(values []interface{}) error) error
+	CopySongs(ctx context.Context, daoModels []*songModel.DAO) (int64, error)
+	FindSongsByKeys(ctx context.Context, keys []songModel.SongKeyDAO) ([]songModel.SongKeyDAO, error)
+	ListSongs(ctx context.Context, songFilter *filter.Filter, paginationQuery *pagination.PaginationQuery) ([]*songModel.DAO, error)
+	GetSong(ctx context.Context, songID int) (*songModel.DAO, error)
+	GetSongsByIDs(ctx context.Context, songIDs []int) ([]*songModel.DAO, error)
+	ListSongsByGroups(ctx context.Context, groups []string, limit int) ([]*songModel.DAO, error)
+	UpdateSong(ctx context.Context, daoModel *songModel.DAO) error
+	DeleteSong(ctx context.Context, songID int, version int) error
+	RestoreSong(ctx context.Context, daoModel *songModel.DAO) error
+	AddSongHistory(ctx context.Context, entries []*songModel.SongHistoryDAO) error
+	ListSongHistory(ctx context.Context, songID int) ([]*songModel.SongHistoryDAO, error)
+	GetSongHistory(ctx context.Context, songID int, historyID int64) (*songModel.SongHistoryDAO, error)
+	ListLyricLines(ctx context.Context, songID int) ([]*songModel.LyricLineDAO, error)
+	ReplaceLyricLines(ctx context.Context, songID int, lines []*songModel.LyricLineDAO) error
+	DeleteLyricLines(ctx context.Context, songID int) error
+	ListLyricVariants(ctx context.Context, songID int) ([]*songModel.LyricVariantDAO, error)
+	GetLyricVariant(ctx context.Context, songID int, language string) (*songModel.LyricVariantDAO, error)
+	SaveLyricVariant(ctx context.Context, daoModel *songModel.LyricVariantDAO) (*songModel.LyricVariantDAO, error)
+	DeleteLyricVariant(ctx context.Context, songID int, language string) error
+	MoveLyricVariants(ctx context.Context, fromSongIDs []int, toSongID int) ([]*songModel.LyricVariantDAO, error)
+	SetOriginalLyricsText(ctx context.Context, songID int, text string) error
+	GetSongAudio(ctx context.Context, songID int) (*songModel.SongAudioDAO, error)
+	SaveSongAudio(ctx context.Context, daoModel *songModel.SongAudioDAO) (*songModel.SongAudioDAO, error)
+	DeleteSongAudio(ctx context.Context, songID int) (*songModel.SongAudioDAO, error)
+	CountAudioBlobReferences(ctx context.Context, blobKey string) (int, error)
+	MoveSongAudio(ctx context.Context, fromSongIDs []int, toSongID int) error
+	GetSongCover(ctx context.Context, songID int) (*songModel.SongCoverDAO, error)
+	SaveSongCover(ctx context.Context, daoModel *songModel.SongCoverDAO) (*songModel.SongCoverDAO, error)
+	DeleteSongCover(ctx context.Context, songID int) (*songModel.SongCoverDAO, error)
+	CountCoverBlobReferences(ctx context.Context, blobKey string) (int, error)
+	MoveSongCover(ctx context.Context, fromSongIDs []int, toSongID int) error
+	RecordPlay(ctx context.Context, daoModel *songModel.SongPlayDAO) error
+	LockPlayRollup(ctx context.Context) (time.Time, error)
+	RollupPlays(ctx context.Context, from, to time.Time) (int64, error)
+	SetPlayRollup(ctx context.Context, rolledUntil time.Time) error
+	ListTopSongs(ctx context.Context, chartQuery songModel.ChartQueryDAO) ([]*songModel.ChartEntryDAO, error)
+	ListTrendingSongs(ctx context.Context, trendingQuery songModel.TrendingQueryDAO) ([]*songModel.ChartEntryDAO, error)
+	MoveSongPlays(ctx context.Context, fromSongIDs []int, toSongID int) error
 }
@@ -2,11 +2,22 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 	"github.com/jumayevgadam/music-app/internal/connection"
 	songModel "github.com/jumayevgadam/music-app/internal/models"
 	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/filter"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
 )
 
+// songIDColumn is tie breaker of sorted song lists
+const songIDColumn = "songs.id"
+
 // SongRepository struct is
 type SongRepository struct {
 	psqlDB connection.DB
@@ -27,11 +38,783 @@ func (sr *SongRepository) AddSong(ctx context.Context, daoModel *songModel.DAO)
 		daoModel.Group,
 		daoModel.Title,
 		daoModel.ReleaseDate,
+		daoModel.ReleaseDatePrecision,
 		daoModel.Text,
 		daoModel.Link,
+		daoModel.LinkProvider,
+		daoModel.LinkID,
 	).Scan(&songID); err != nil {
-		return -1, errlst.ParseSqlErrors(err)
+		return -1, errlst.FromPostgres(err)
 	}
 
 	return songID, nil
 }
+
+// GetSong repo is
+func (sr *SongRepository) GetSong(ctx context.Context, songID int) (*songModel.DAO, error) {
+	var song songModel.DAO
+
+	if err := sr.psqlDB.Get(ctx, sr.psqlDB, &song, getSongQuery, songID); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return &song, nil
+}
+
+// UpdateSong repo is, when daoModel.Version isn't 0 it must match version of stored song
+func (sr *SongRepository) UpdateSong(ctx context.Context, daoModel *songModel.DAO) error {
+	var songID int
+
+	if err := sr.psqlDB.QueryRow(
+		ctx,
+		updateSongQuery,
+		daoModel.ID,
+		daoModel.Group,
+		daoModel.Title,
+		daoModel.ReleaseDate,
+		daoModel.Text,
+		daoModel.Link,
+		daoModel.Version,
+		daoModel.LinkProvider,
+		daoModel.LinkID,
+		daoModel.ReleaseDatePrecision,
+	).Scan(&songID); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// DeleteSong repo is, version 0 deletes song whatever its version is
+func (sr *SongRepository) DeleteSong(ctx context.Context, songID int, version int) error {
+	if err := sr.psqlDB.QueryRow(ctx, deleteSongQuery, songID, version).Scan(&songID); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// GetSongsByIDs repo returns existing songs of given ids in any order
+func (sr *SongRepository) GetSongsByIDs(ctx context.Context, songIDs []int) ([]*songModel.DAO, error) {
+	var songs []*songModel.DAO
+
+	if err := sr.psqlDB.Select(ctx, sr.psqlDB, &songs, getSongsByIDsQuery, songIDs); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return songs, nil
+}
+
+// ListSongsByGroups repo returns up to limit latest songs of every group,
+// groups must be normalized (lower, trimmed)
+func (sr *SongRepository) ListSongsByGroups(ctx context.Context, groups []string, limit int) ([]*songModel.DAO, error) {
+	var songs []*songModel.DAO
+
+	if err := sr.psqlDB.Select(ctx, sr.psqlDB, &songs, listSongsByGroupsQuery, groups, limit); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return songs, nil
+}
+
+// RestoreSong repo inserts deleted song back with its id and creation time
+func (sr *SongRepository) RestoreSong(ctx context.Context, daoModel *songModel.DAO) error {
+	var songID int
+
+	if err := sr.psqlDB.QueryRow(
+		ctx,
+		restoreSongQuery,
+		daoModel.ID,
+		daoModel.Group,
+		daoModel.Title,
+		daoModel.ReleaseDate,
+		daoModel.ReleaseDatePrecision,
+		daoModel.Text,
+		daoModel.Link,
+		daoModel.LinkProvider,
+		daoModel.LinkID,
+		daoModel.CreatedAt,
+	).Scan(&songID); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// AddSongHistory repo writes entries with one statement, it should run in
+// transaction of the change entries describe
+func (sr *SongRepository) AddSongHistory(ctx context.Context, entries []*songModel.SongHistoryDAO) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var (
+		songIDs    = make([]int, 0, len(entries))
+		actions    = make([]string, 0, len(entries))
+		actors     = make([]string, 0, len(entries))
+		diffs      = make([]string, 0, len(entries))
+		snapshots  = make([]string, 0, len(entries))
+		mergedInto = make([]*int, 0, len(entries))
+		mergedFrom = make([]string, 0, len(entries))
+	)
+	for _, entry := range entries {
+		songIDs = append(songIDs, entry.SongID)
+		actions = append(actions, entry.Action)
+		actors = append(actors, entry.Actor)
+		diffs = append(diffs, string(entry.Diff))
+		snapshots = append(snapshots, string(entry.Snapshot))
+		mergedInto = append(mergedInto, entry.MergedInto)
+		mergedFrom = append(mergedFrom, intArrayLiteral(entry.MergedFrom))
+	}
+
+	if _, err := sr.psqlDB.Exec(
+		ctx,
+		addSongHistoryQuery,
+		songIDs,
+		actions,
+		actors,
+		diffs,
+		snapshots,
+		mergedInto,
+		mergedFrom,
+	); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// intArrayLiteral formats ids as Postgres array literal, e.g. {1,2}
+func intArrayLiteral(ids []int) string {
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.Itoa(id))
+	}
+
+	return "{" + strings.Join(values, ",") + "}"
+}
+
+// ListSongHistory repo is
+func (sr *SongRepository) ListSongHistory(ctx context.Context, songID int) ([]*songModel.SongHistoryDAO, error) {
+	var entries []*songModel.SongHistoryDAO
+
+	if err := sr.psqlDB.Select(ctx, sr.psqlDB, &entries, listSongHistoryQuery, songID); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return entries, nil
+}
+
+// GetSongHistory repo is
+func (sr *SongRepository) GetSongHistory(ctx context.Context, songID int, historyID int64) (*songModel.SongHistoryDAO, error) {
+	var entry songModel.SongHistoryDAO
+
+	if err := sr.psqlDB.Get(ctx, sr.psqlDB, &entry, getSongHistoryQuery, songID, historyID); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return &entry, nil
+}
+
+// ListLyricLines repo returns synced lyrics of song in time order
+func (sr *SongRepository) ListLyricLines(ctx context.Context, songID int) ([]*songModel.LyricLineDAO, error) {
+	var lines []*songModel.LyricLineDAO
+
+	if err := sr.psqlDB.Select(ctx, sr.psqlDB, &lines, listLyricLinesQuery, songID); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return lines, nil
+}
+
+// ReplaceLyricLines repo replaces synced lyrics of song, it should run inside transaction
+func (sr *SongRepository) ReplaceLyricLines(ctx context.Context, songID int, lines []*songModel.LyricLineDAO) error {
+	if err := sr.DeleteLyricLines(ctx, songID); err != nil {
+		return err
+	}
+
+	var (
+		positions = make([]int, 0, len(lines))
+		times     = make([]int64, 0, len(lines))
+		texts     = make([]string, 0, len(lines))
+	)
+	for _, line := range lines {
+		positions = append(positions, line.Position)
+		times = append(times, line.TimeMs)
+		texts = append(texts, line.Text)
+	}
+
+	if _, err := sr.psqlDB.Exec(ctx, addLyricLinesQuery, songID, positions, times, texts); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// DeleteLyricLines repo is
+func (sr *SongRepository) DeleteLyricLines(ctx context.Context, songID int) error {
+	if _, err := sr.psqlDB.Exec(ctx, deleteLyricLinesQuery, songID); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// ListLyricVariants repo returns lyric variants of song, original first
+func (sr *SongRepository) ListLyricVariants(ctx context.Context, songID int) ([]*songModel.LyricVariantDAO, error) {
+	var variants []*songModel.LyricVariantDAO
+
+	if err := sr.psqlDB.Select(ctx, sr.psqlDB, &variants, listLyricVariantsQuery, songID); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return variants, nil
+}
+
+// GetLyricVariant repo is
+func (sr *SongRepository) GetLyricVariant(ctx context.Context, songID int, language string) (*songModel.LyricVariantDAO, error) {
+	var variant songModel.LyricVariantDAO
+
+	if err := sr.psqlDB.Get(ctx, sr.psqlDB, &variant, getLyricVariantQuery, songID, language); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return &variant, nil
+}
+
+// SaveLyricVariant repo inserts or replaces variant, variant saved as original
+// unmarks previous original, it should run inside transaction
+func (sr *SongRepository) SaveLyricVariant(ctx context.Context, daoModel *songModel.LyricVariantDAO) (*songModel.LyricVariantDAO, error) {
+	if daoModel.Original {
+		if _, err := sr.psqlDB.Exec(ctx, unmarkOriginalLyricVariantQuery, daoModel.SongID, daoModel.Language); err != nil {
+			return nil, errlst.FromPostgres(err)
+		}
+	}
+
+	var saved songModel.LyricVariantDAO
+
+	if err := sr.psqlDB.QueryRow(
+		ctx,
+		saveLyricVariantQuery,
+		daoModel.SongID,
+		daoModel.Language,
+		daoModel.Text,
+		daoModel.Original,
+	).Scan(&saved.SongID, &saved.Language, &saved.Text, &saved.Original, &saved.CreatedAt, &saved.UpdatedAt); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return &saved, nil
+}
+
+// DeleteLyricVariant repo is
+func (sr *SongRepository) DeleteLyricVariant(ctx context.Context, songID int, language string) error {
+	if err := sr.psqlDB.QueryRow(ctx, deleteLyricVariantQuery, songID, language).Scan(&songID); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// MoveLyricVariants repo copies variants of merged songs to canonical one and returns
+// copied ones, statement writes so it runs on primary
+func (sr *SongRepository) MoveLyricVariants(ctx context.Context, fromSongIDs []int, toSongID int) ([]*songModel.LyricVariantDAO, error) {
+	var variants []*songModel.LyricVariantDAO
+
+	if err := sr.psqlDB.Select(ctx, sr.psqlDB.Primary(), &variants, moveLyricVariantsQuery, fromSongIDs, toSongID); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return variants, nil
+}
+
+// SetOriginalLyricsText repo copies changed text of song to its original variant
+func (sr *SongRepository) SetOriginalLyricsText(ctx context.Context, songID int, text string) error {
+	if _, err := sr.psqlDB.Exec(ctx, setOriginalLyricsTextQuery, songID, text); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// GetSongAudio repo is
+func (sr *SongRepository) GetSongAudio(ctx context.Context, songID int) (*songModel.SongAudioDAO, error) {
+	var audio songModel.SongAudioDAO
+
+	if err := sr.psqlDB.Get(ctx, sr.psqlDB, &audio, getSongAudioQuery, songID); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return &audio, nil
+}
+
+// SaveSongAudio repo inserts or replaces audio of song
+func (sr *SongRepository) SaveSongAudio(ctx context.Context, daoModel *songModel.SongAudioDAO) (*songModel.SongAudioDAO, error) {
+	return scanSongAudio(sr.psqlDB.QueryRow(
+		ctx,
+		saveSongAudioQuery,
+		daoModel.SongID,
+		daoModel.BlobKey,
+		daoModel.ContentType,
+		daoModel.Size,
+		daoModel.SHA256,
+		daoModel.Filename,
+		daoModel.TagFormat,
+		daoModel.TagTitle,
+		daoModel.TagArtist,
+		daoModel.TagAlbum,
+		daoModel.TagDate,
+	))
+}
+
+// DeleteSongAudio repo returns deleted audio, so its blob can be released
+func (sr *SongRepository) DeleteSongAudio(ctx context.Context, songID int) (*songModel.SongAudioDAO, error) {
+	return scanSongAudio(sr.psqlDB.QueryRow(ctx, deleteSongAudioQuery, songID))
+}
+
+// scanSongAudio scans row of songAudioColumns returned by write, it goes to primary
+func scanSongAudio(row pgx.Row) (*songModel.SongAudioDAO, error) {
+	var audio songModel.SongAudioDAO
+
+	if err := row.Scan(
+		&audio.SongID,
+		&audio.BlobKey,
+		&audio.ContentType,
+		&audio.Size,
+		&audio.SHA256,
+		&audio.Filename,
+		&audio.TagFormat,
+		&audio.TagTitle,
+		&audio.TagArtist,
+		&audio.TagAlbum,
+		&audio.TagDate,
+		&audio.CreatedAt,
+		&audio.UpdatedAt,
+	); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return &audio, nil
+}
+
+// CountAudioBlobReferences repo is, it reads primary as blob is deleted when count is zero
+func (sr *SongRepository) CountAudioBlobReferences(ctx context.Context, blobKey string) (int, error) {
+	var count int
+
+	if err := sr.psqlDB.QueryRow(ctx, countAudioBlobReferencesQuery, blobKey).Scan(&count); err != nil {
+		return 0, errlst.FromPostgres(err)
+	}
+
+	return count, nil
+}
+
+// MoveSongAudio repo gives audio of merged songs to canonical one when it has none
+func (sr *SongRepository) MoveSongAudio(ctx context.Context, fromSongIDs []int, toSongID int) error {
+	if _, err := sr.psqlDB.Exec(ctx, moveSongAudioQuery, fromSongIDs, toSongID); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// GetSongCover repo is
+func (sr *SongRepository) GetSongCover(ctx context.Context, songID int) (*songModel.SongCoverDAO, error) {
+	var cover songModel.SongCoverDAO
+
+	if err := sr.psqlDB.Get(ctx, sr.psqlDB, &cover, getSongCoverQuery, songID); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return &cover, nil
+}
+
+// SaveSongCover repo inserts or replaces cover of song
+func (sr *SongRepository) SaveSongCover(ctx context.Context, daoModel *songModel.SongCoverDAO) (*songModel.SongCoverDAO, error) {
+	return scanSongCover(sr.psqlDB.QueryRow(
+		ctx,
+		saveSongCoverQuery,
+		daoModel.SongID,
+		daoModel.BlobKey,
+		daoModel.ContentType,
+		daoModel.Size,
+		daoModel.SHA256,
+		daoModel.Width,
+		daoModel.Height,
+	))
+}
+
+// DeleteSongCover repo returns deleted cover, so its blobs can be released
+func (sr *SongRepository) DeleteSongCover(ctx context.Context, songID int) (*songModel.SongCoverDAO, error) {
+	return scanSongCover(sr.psqlDB.QueryRow(ctx, deleteSongCoverQuery, songID))
+}
+
+// CountCoverBlobReferences repo is, it reads primary as blobs are deleted when count is zero
+func (sr *SongRepository) CountCoverBlobReferences(ctx context.Context, blobKey string) (int, error) {
+	var count int
+
+	if err := sr.psqlDB.QueryRow(ctx, countCoverBlobReferencesQuery, blobKey).Scan(&count); err != nil {
+		return 0, errlst.FromPostgres(err)
+	}
+
+	return count, nil
+}
+
+// MoveSongCover repo gives cover of merged songs to canonical one when it has none
+func (sr *SongRepository) MoveSongCover(ctx context.Context, fromSongIDs []int, toSongID int) error {
+	if _, err := sr.psqlDB.Exec(ctx, moveSongCoverQuery, fromSongIDs, toSongID); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// scanSongCover scans row of songCoverColumns returned by write
+func scanSongCover(row pgx.Row) (*songModel.SongCoverDAO, error) {
+	var cover songModel.SongCoverDAO
+
+	if err := row.Scan(
+		&cover.SongID,
+		&cover.BlobKey,
+		&cover.ContentType,
+		&cover.Size,
+		&cover.SHA256,
+		&cover.Width,
+		&cover.Height,
+		&cover.CreatedAt,
+		&cover.UpdatedAt,
+	); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return &cover, nil
+}
+
+// RecordPlay repo appends play event, missing song is not found
+func (sr *SongRepository) RecordPlay(ctx context.Context, daoModel *songModel.SongPlayDAO) error {
+	var playID int64
+
+	if err := sr.psqlDB.QueryRow(
+		ctx,
+		recordPlayQuery,
+		daoModel.SongID,
+		daoModel.DurationMs,
+		daoModel.Source,
+		daoModel.Actor,
+	).Scan(&playID); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// LockPlayRollup repo returns end of rolled up period and locks it till transaction ends
+func (sr *SongRepository) LockPlayRollup(ctx context.Context) (time.Time, error) {
+	var rolledUntil time.Time
+
+	if err := sr.psqlDB.QueryRow(ctx, lockPlayRollupQuery).Scan(&rolledUntil); err != nil {
+		return time.Time{}, errlst.FromPostgres(err)
+	}
+
+	return rolledUntil, nil
+}
+
+// RollupPlays repo adds plays of [from, to) to hourly and daily counts, it must
+// run inside transaction holding LockPlayRollup
+func (sr *SongRepository) RollupPlays(ctx context.Context, from, to time.Time) (int64, error) {
+	var plays int64
+
+	if err := sr.psqlDB.QueryRow(ctx, rollupPlaysQuery, from, to).Scan(&plays); err != nil {
+		return 0, errlst.FromPostgres(err)
+	}
+
+	return plays, nil
+}
+
+// SetPlayRollup repo is
+func (sr *SongRepository) SetPlayRollup(ctx context.Context, rolledUntil time.Time) error {
+	if _, err := sr.psqlDB.Exec(ctx, setPlayRollupQuery, rolledUntil); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// ListTopSongs repo is
+func (sr *SongRepository) ListTopSongs(ctx context.Context, chartQuery songModel.ChartQueryDAO) ([]*songModel.ChartEntryDAO, error) {
+	var entries []*songModel.ChartEntryDAO
+
+	query := listTopSongsDailyQuery
+	if chartQuery.Hourly {
+		query = listTopSongsHourlyQuery
+	}
+
+	if err := sr.psqlDB.Select(
+		ctx, sr.psqlDB, &entries, query, chartQuery.Since, chartQuery.Group, chartQuery.Limit,
+	); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return entries, nil
+}
+
+// ListTrendingSongs repo is
+func (sr *SongRepository) ListTrendingSongs(ctx context.Context, trendingQuery songModel.TrendingQueryDAO) ([]*songModel.ChartEntryDAO, error) {
+	var entries []*songModel.ChartEntryDAO
+
+	if err := sr.psqlDB.Select(
+		ctx,
+		sr.psqlDB,
+		&entries,
+		listTrendingSongsQuery,
+		trendingQuery.Now,
+		trendingQuery.HalfLife.Seconds(),
+		trendingQuery.Since,
+		trendingQuery.Group,
+		trendingQuery.Limit,
+	); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return entries, nil
+}
+
+// MoveSongPlays repo moves plays of merged songs to canonical one
+func (sr *SongRepository) MoveSongPlays(ctx context.Context, fromSongIDs []int, toSongID int) error {
+	if _, err := sr.psqlDB.Exec(ctx, moveSongPlaysQuery, fromSongIDs, toSongID); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// ListSongDuplicates repo returns page of duplicate candidates of given reasons
+func (sr *SongRepository) ListSongDuplicates(
+	ctx context.Context,
+	duplicateFilter songModel.DuplicateFilterDAO,
+	paginationQuery *pagination.PaginationQuery,
+) ([]*songModel.SongDuplicateDAO, error) {
+	var duplicates []*songModel.SongDuplicateDAO
+
+	reasons := duplicateFilter.Reasons
+	if len(reasons) == 0 {
+		reasons = songModel.DuplicateReasons
+	}
+
+	parts := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		part, ok := duplicateQueries[reason]
+		if !ok {
+			return nil, errlst.Validation(fmt.Sprintf("unknown duplicate reason %q", reason), errlst.ErrBadQueryParams)
+		}
+		parts = append(parts, part)
+	}
+
+	query := fmt.Sprintf(listSongDuplicatesQuery, strings.Join(parts, " UNION ALL "))
+	if err := sr.psqlDB.Select(
+		ctx,
+		sr.psqlDB,
+		&duplicates,
+		query,
+		duplicateFilter.MinSimilarity,
+		paginationQuery.GetLimit(),
+		paginationQuery.GetOffset(),
+	); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return duplicates, nil
+}
+
+// ListUnenrichedSongs repo returns up to limit songs which link wasn't enriched yet
+func (sr *SongRepository) ListUnenrichedSongs(ctx context.Context, limit int) ([]*songModel.DAO, error) {
+	var songs []*songModel.DAO
+
+	if err := sr.psqlDB.Select(ctx, sr.psqlDB, &songs, listUnenrichedSongsQuery, limit); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return songs, nil
+}
+
+// SaveLinkMetadata repo is
+func (sr *SongRepository) SaveLinkMetadata(ctx context.Context, metadata *songModel.LinkMetadataDAO) error {
+	if _, err := sr.psqlDB.Exec(
+		ctx,
+		saveLinkMetadataQuery,
+		metadata.SongID,
+		metadata.Link,
+		metadata.Provider,
+		metadata.LinkID,
+		metadata.Duration,
+		metadata.ThumbnailURL,
+	); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// ClaimLinkChecks repo takes http(s) links due for health check, statement writes so it runs on primary
+func (sr *SongRepository) ClaimLinkChecks(ctx context.Context, limit int, interval time.Duration) ([]*songModel.LinkCheckJobDAO, error) {
+	var jobs []*songModel.LinkCheckJobDAO
+
+	if err := sr.psqlDB.Select(ctx, sr.psqlDB.Primary(), &jobs, claimLinkChecksQuery, limit, interval.Seconds()); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return jobs, nil
+}
+
+// SaveLinkCheck repo records result of check, link is broken after brokenAfter failures in a row
+func (sr *SongRepository) SaveLinkCheck(ctx context.Context, result *songModel.LinkCheckResultDAO, brokenAfter int) error {
+	if _, err := sr.psqlDB.Exec(
+		ctx,
+		saveLinkCheckQuery,
+		result.SongID,
+		result.Link,
+		result.Failed,
+		result.StatusCode,
+		brokenAfter,
+	); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// CountSongs repo is
+func (sr *SongRepository) CountSongs(ctx context.Context, songFilter *filter.Filter) (int, error) {
+	var totalCount int
+
+	condition, args := songFilter.SQL(1)
+	if err := sr.psqlDB.Get(ctx, sr.psqlDB, &totalCount, countSongsQuery+" WHERE "+condition, args...); err != nil {
+		return -1, errlst.FromPostgres(err)
+	}
+
+	return totalCount, nil
+}
+
+// ListSongs repo returns one row more than page size, so caller knows if there are more rows.
+// In cursor mode rows before cursor are returned in reversed order.
+func (sr *SongRepository) ListSongs(ctx context.Context, songFilter *filter.Filter, paginationQuery *pagination.PaginationQuery) ([]*songModel.DAO, error) {
+	var (
+		songs  []*songModel.DAO
+		query  strings.Builder
+		sort   = paginationQuery.GetSort()
+		cursor = paginationQuery.GetCursor()
+	)
+
+	query.WriteString(listSongsQuery)
+
+	condition, args := songFilter.SQL(1)
+	query.WriteString(" WHERE " + condition)
+
+	if cursor != nil {
+		condition, cursorArgs := sort.KeysetCondition(cursor, songIDColumn, len(args)+1)
+		query.WriteString(" AND " + condition)
+		args = append(args, cursorArgs...)
+	}
+
+	query.WriteString(" ORDER BY " + sort.OrderBy(songIDColumn, cursor != nil && cursor.Prev))
+
+	args = append(args, paginationQuery.GetLimit()+1)
+	query.WriteString(" LIMIT $" + strconv.Itoa(len(args)))
+
+	if cursor == nil {
+		args = append(args, paginationQuery.GetOffset())
+		query.WriteString(" OFFSET $" + strconv.Itoa(len(args)))
+	}
+
+	if err := sr.psqlDB.Select(ctx, sr.psqlDB, &songs, query.String(), args...); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return songs, nil
+}
+
+// songCopyColumns are columns filled by CopySongs
+var songCopyColumns = []string{
+	"group", "title", "release_date", "release_date_precision", "text", "link", "link_provider", "link_id",
+}
+
+// CopySongs repo bulk inserts songs with COPY, it should run inside transaction
+func (sr *SongRepository) CopySongs(ctx context.Context, daoModels []*songModel.DAO) (int64, error) {
+	copied, err := sr.psqlDB.CopyFrom(
+		ctx,
+		pgx.Identifier{"songs"},
+		songCopyColumns,
+		pgx.CopyFromSlice(len(daoModels), func(i int) ([]interface{}, error) {
+			song := daoModels[i]
+			return []interface{}{
+				song.Group, song.Title, song.ReleaseDate, song.ReleaseDatePrecision, song.Text, song.Link,
+				song.LinkProvider, song.LinkID,
+			}, nil
+		}),
+	)
+	if err != nil {
+		return -1, errlst.FromPostgres(err)
+	}
+
+	return copied, nil
+}
+
+// FindSongsByKeys repo returns existing songs matching normalized (lower, trimmed)
+// group and title keys, Group and Title of result are normalized too
+func (sr *SongRepository) FindSongsByKeys(ctx context.Context, keys []songModel.SongKeyDAO) ([]songModel.SongKeyDAO, error) {
+	groups := make([]string, 0, len(keys))
+	titles := make([]string, 0, len(keys))
+	for _, key := range keys {
+		groups = append(groups, key.Group)
+		titles = append(titles, key.Title)
+	}
+
+	var existing []songModel.SongKeyDAO
+	if err := sr.psqlDB.Select(ctx, sr.psqlDB, &existing, findSongsByKeysQuery, groups, titles); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return existing, nil
+}
+
+// ExportSongs repo streams every song matching filter in given order,
+// rows are read from cursor one by one and passed to fn with values of columns
+func (sr *SongRepository) ExportSongs(
+	ctx context.Context,
+	songFilter *filter.Filter,
+	sort pagination.SortSpec,
+	columns []string,
+	fn func(values []interface{}) error,
+) error {
+	expressions := make([]string, 0, len(columns))
+	for _, column := range columns {
+		expression, ok := songExportColumns[column]
+		if !ok {
+			return errlst.Validation("unknown export column "+column, errlst.ErrBadQueryParams)
+		}
+		expressions = append(expressions, expression)
+	}
+
+	condition, args := songFilter.SQL(1)
+	query := fmt.Sprintf(exportSongsQuery, strings.Join(expressions, ", ")) +
+		" WHERE " + condition +
+		" ORDER BY " + sort.OrderBy(songIDColumn, false)
+
+	rows, err := sr.psqlDB.Query(ctx, query, args...)
+	if err != nil {
+		return errlst.FromPostgres(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return errlst.FromPostgres(err)
+		}
+
+		if err := fn(values); err != nil {
+			return err
+		}
+	}
+
+	return errlst.FromPostgres(rows.Err())
+}
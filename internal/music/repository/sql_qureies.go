@@ -1,11 +1,518 @@
 package repository
 
+import songModel "github.com/jumayevgadam/music-app/internal/models"
+
 // SQL Queries for songs
 const (
 	// addSongQuery is
 	addSongQuery = `
-		INSERT INTO songs (group, title, release_date, text, link)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO songs ("group", title, release_date, release_date_precision, text, link, link_provider, link_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id;
+	`
+
+	// getSongQuery is
+	getSongQuery = `
+		SELECT` + songColumns + `
+		FROM songs
+		WHERE songs.id = $1;
+	`
+
+	// updateSongQuery bumps version, when $7 isn't 0 song is updated only if it still has that version.
+	// Metadata and health of changed link are dropped, so workers check it again.
+	updateSongQuery = `
+		UPDATE songs
+		SET "group" = $2, title = $3, release_date = $4, text = $5, link = $6,
+			link_provider = $8, link_id = $9, release_date_precision = $10,
+			link_duration = CASE WHEN link = $6 THEN link_duration END,
+			link_thumbnail_url = CASE WHEN link = $6 THEN link_thumbnail_url END,
+			link_enriched_at = CASE WHEN link = $6 THEN link_enriched_at END,
+			link_status = CASE WHEN link = $6 THEN link_status ELSE 'unchecked' END,
+			link_status_code = CASE WHEN link = $6 THEN link_status_code END,
+			link_failures = CASE WHEN link = $6 THEN link_failures ELSE 0 END,
+			link_checked_at = CASE WHEN link = $6 THEN link_checked_at END,
+			version = version + 1, updated_at = now()
+		WHERE id = $1 AND ($7 = 0 OR version = $7)
+		RETURNING id;
+	`
+
+	// deleteSongQuery is, when $2 isn't 0 song is deleted only if it still has that version
+	deleteSongQuery = `
+		DELETE FROM songs
+		WHERE id = $1 AND ($2 = 0 OR version = $2)
+		RETURNING id;
+	`
+
+	// findSongsByKeysQuery finds songs with same normalized group and title,
+	// $1 and $2 are arrays of normalized groups and titles
+	findSongsByKeysQuery = `
+		SELECT DISTINCT ON (lower(trim(songs."group")), lower(trim(songs.title)))
+			songs.id, lower(trim(songs."group")) AS "group", lower(trim(songs.title)) AS title
+		FROM songs
+		JOIN unnest($1::text[], $2::text[]) AS k("group", title)
+			ON lower(trim(songs."group")) = k."group" AND lower(trim(songs.title)) = k.title
+		ORDER BY lower(trim(songs."group")), lower(trim(songs.title)), songs.id;
+	`
+
+	// getSongsByIDsQuery is, $1 is array of ids
+	getSongsByIDsQuery = `
+		SELECT` + songColumns + `
+		FROM songs
+		WHERE songs.id = ANY($1);
+	`
+
+	// listSongsByGroupsQuery returns latest $2 songs of every normalized group in $1
+	listSongsByGroupsQuery = `
+		SELECT s.id, s."group", s.title, s.release_date, s.release_date_precision, s.text, s.link, s.link_provider, s.link_id,
+			s.link_duration, s.link_thumbnail_url, s.link_status, s.link_status_code, s.link_failures,
+			s.link_checked_at, s.version, s.created_at, s.updated_at
+		FROM (
+			SELECT` + songColumns + `,
+				row_number() OVER (
+					PARTITION BY lower(trim(songs."group"))
+					ORDER BY songs.release_date DESC, songs.id DESC
+				) AS rn
+			FROM songs
+			WHERE lower(trim(songs."group")) = ANY($1)
+		) AS s
+		WHERE s.rn <= $2
+		ORDER BY s."group", s.rn;
+	`
+
+	// restoreSongQuery inserts deleted song back with its old id, version continues after
+	// every version song had, so ETags issued before delete don't match restored song
+	restoreSongQuery = `
+		INSERT INTO songs (id, "group", title, release_date, release_date_precision, text, link, link_provider, link_id,
+			created_at, version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, (
+			SELECT COALESCE(max((snapshot->>'version')::int), 0) + 1
+			FROM song_history
+			WHERE song_id = $1
+		))
+		RETURNING id;
+	`
+
+	// addSongHistoryQuery inserts history entries given as arrays, merged_from
+	// is given as array literals because arrays of arrays can't be unnested by row
+	addSongHistoryQuery = `
+		INSERT INTO song_history (song_id, action, actor, diff, snapshot, merged_into, merged_from)
+		SELECT h.song_id, h.action, h.actor, h.diff::jsonb, h.snapshot::jsonb, h.merged_into, h.merged_from::int[]
+		FROM unnest($1::int[], $2::text[], $3::text[], $4::text[], $5::text[], $6::int[], $7::text[])
+			AS h(song_id, action, actor, diff, snapshot, merged_into, merged_from);
+	`
+
+	// listSongHistoryQuery returns newest entries first
+	listSongHistoryQuery = `
+		SELECT id, song_id, action, actor, diff, snapshot, merged_into, merged_from, changed_at
+		FROM song_history
+		WHERE song_id = $1
+		ORDER BY id DESC;
+	`
+
+	// getSongHistoryQuery is
+	getSongHistoryQuery = `
+		SELECT id, song_id, action, actor, diff, snapshot, merged_into, merged_from, changed_at
+		FROM song_history
+		WHERE song_id = $1 AND id = $2;
+	`
+
+	// listLyricLinesQuery is
+	listLyricLinesQuery = `
+		SELECT song_id, position, time_ms, text
+		FROM song_lyric_lines
+		WHERE song_id = $1
+		ORDER BY position;
+	`
+
+	// deleteLyricLinesQuery is
+	deleteLyricLinesQuery = `
+		DELETE FROM song_lyric_lines
+		WHERE song_id = $1;
+	`
+
+	// addLyricLinesQuery inserts lines given as arrays of positions, times and texts
+	addLyricLinesQuery = `
+		INSERT INTO song_lyric_lines (song_id, position, time_ms, text)
+		SELECT $1, l.position, l.time_ms, l.text
+		FROM unnest($2::int[], $3::bigint[], $4::text[]) AS l(position, time_ms, text);
+	`
+
+	// listLyricVariantsQuery returns original variant first
+	listLyricVariantsQuery = `
+		SELECT song_id, language, text, original, created_at, updated_at
+		FROM song_lyric_variants
+		WHERE song_id = $1
+		ORDER BY original DESC, language;
+	`
+
+	// getLyricVariantQuery is
+	getLyricVariantQuery = `
+		SELECT song_id, language, text, original, created_at, updated_at
+		FROM song_lyric_variants
+		WHERE song_id = $1 AND language = $2;
+	`
+
+	// unmarkOriginalLyricVariantQuery is run before other variant is saved as original
+	unmarkOriginalLyricVariantQuery = `
+		UPDATE song_lyric_variants
+		SET original = FALSE, updated_at = now()
+		WHERE song_id = $1 AND original AND language <> $2;
+	`
+
+	// saveLyricVariantQuery is
+	saveLyricVariantQuery = `
+		INSERT INTO song_lyric_variants (song_id, language, text, original)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (song_id, language) DO UPDATE
+		SET text = EXCLUDED.text, original = EXCLUDED.original, updated_at = now()
+		RETURNING song_id, language, text, original, created_at, updated_at;
+	`
+
+	// deleteLyricVariantQuery is
+	deleteLyricVariantQuery = `
+		DELETE FROM song_lyric_variants
+		WHERE song_id = $1 AND language = $2
+		RETURNING song_id;
+	`
+
+	// moveLyricVariantsQuery copies variants of songs $1 to song $2 as translations,
+	// languages song $2 has are kept and for others variant of lowest song id wins
+	moveLyricVariantsQuery = `
+		INSERT INTO song_lyric_variants (song_id, language, text, original, created_at, updated_at)
+		SELECT DISTINCT ON (language) $2::INTEGER, language, text, FALSE, created_at, now()
+		FROM song_lyric_variants
+		WHERE song_id = ANY($1)
+		ORDER BY language, song_id
+		ON CONFLICT (song_id, language) DO NOTHING
+		RETURNING song_id, language, text, original, created_at, updated_at;
+	`
+
+	// setOriginalLyricsTextQuery keeps original variant in sync with songs.text
+	setOriginalLyricsTextQuery = `
+		UPDATE song_lyric_variants
+		SET text = $2, updated_at = now()
+		WHERE song_id = $1 AND original AND text <> $2;
+	`
+
+	// songAudioColumns are selected columns of song_audio
+	songAudioColumns = `
+		song_id, blob_key, content_type, size, sha256, filename,
+		tag_format, tag_title, tag_artist, tag_album, tag_date, created_at, updated_at
+	`
+
+	// getSongAudioQuery is
+	getSongAudioQuery = `
+		SELECT ` + songAudioColumns + `
+		FROM song_audio
+		WHERE song_id = $1;
+	`
+
+	// saveSongAudioQuery replaces audio of song
+	saveSongAudioQuery = `
+		INSERT INTO song_audio (
+			song_id, blob_key, content_type, size, sha256, filename,
+			tag_format, tag_title, tag_artist, tag_album, tag_date
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (song_id) DO UPDATE
+		SET blob_key = EXCLUDED.blob_key, content_type = EXCLUDED.content_type, size = EXCLUDED.size,
+			sha256 = EXCLUDED.sha256, filename = EXCLUDED.filename, tag_format = EXCLUDED.tag_format,
+			tag_title = EXCLUDED.tag_title, tag_artist = EXCLUDED.tag_artist, tag_album = EXCLUDED.tag_album,
+			tag_date = EXCLUDED.tag_date, updated_at = now()
+		RETURNING ` + songAudioColumns + `;
+	`
+
+	// deleteSongAudioQuery is
+	deleteSongAudioQuery = `
+		DELETE FROM song_audio
+		WHERE song_id = $1
+		RETURNING ` + songAudioColumns + `;
+	`
+
+	// countAudioBlobReferencesQuery counts songs sharing blob
+	countAudioBlobReferencesQuery = `
+		SELECT COUNT(*) FROM song_audio WHERE blob_key = $1;
+	`
+
+	// moveSongAudioQuery gives audio of lowest song id of $1 to song $2 when it
+	// has none, rows left with songs $1 are deleted with them
+	moveSongAudioQuery = `
+		UPDATE song_audio SET song_id = $2, updated_at = now()
+		WHERE song_id = (SELECT min(song_id) FROM song_audio WHERE song_id = ANY($1))
+			AND NOT EXISTS (SELECT 1 FROM song_audio WHERE song_id = $2);
+	`
+
+	// songCoverColumns are selected columns of song_covers
+	songCoverColumns = `
+		song_id, blob_key, content_type, size, sha256, width, height, created_at, updated_at
+	`
+
+	// getSongCoverQuery is
+	getSongCoverQuery = `
+		SELECT ` + songCoverColumns + `
+		FROM song_covers
+		WHERE song_id = $1;
+	`
+
+	// saveSongCoverQuery replaces cover of song
+	saveSongCoverQuery = `
+		INSERT INTO song_covers (song_id, blob_key, content_type, size, sha256, width, height)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (song_id) DO UPDATE
+		SET blob_key = EXCLUDED.blob_key, content_type = EXCLUDED.content_type, size = EXCLUDED.size,
+			sha256 = EXCLUDED.sha256, width = EXCLUDED.width, height = EXCLUDED.height, updated_at = now()
+		RETURNING ` + songCoverColumns + `;
+	`
+
+	// deleteSongCoverQuery is
+	deleteSongCoverQuery = `
+		DELETE FROM song_covers
+		WHERE song_id = $1
+		RETURNING ` + songCoverColumns + `;
+	`
+
+	// countCoverBlobReferencesQuery counts songs sharing cover
+	countCoverBlobReferencesQuery = `
+		SELECT COUNT(*) FROM song_covers WHERE blob_key = $1;
+	`
+
+	// moveSongCoverQuery gives cover of lowest song id of $1 to song $2 when it
+	// has none, rows left with songs $1 are deleted with them
+	moveSongCoverQuery = `
+		UPDATE song_covers SET song_id = $2, updated_at = now()
+		WHERE song_id = (SELECT min(song_id) FROM song_covers WHERE song_id = ANY($1))
+			AND NOT EXISTS (SELECT 1 FROM song_covers WHERE song_id = $2);
+	`
+
+	// recordPlayQuery inserts play only if song exists, so missing song gives no rows
+	recordPlayQuery = `
+		INSERT INTO song_plays (song_id, duration_ms, source, actor)
+		SELECT id, $2, $3, $4 FROM songs WHERE id = $1
 		RETURNING id;
 	`
+
+	// lockPlayRollupQuery serializes rollups of many app instances
+	lockPlayRollupQuery = `
+		SELECT rolled_until FROM song_play_rollups FOR UPDATE;
+	`
+
+	// rollupPlaysQuery adds plays of [$1, $2) to hourly and daily counts and returns their count,
+	// hours and days are UTC
+	rollupPlaysQuery = `
+		WITH plays AS (
+			SELECT song_id, played_at AT TIME ZONE 'UTC' AS played_at
+			FROM song_plays
+			WHERE played_at >= $1 AND played_at < $2
+		), hourly AS (
+			INSERT INTO song_plays_hourly (song_id, hour, plays)
+			SELECT song_id, date_trunc('hour', played_at) AT TIME ZONE 'UTC', COUNT(*)
+			FROM plays
+			GROUP BY 1, 2
+			ON CONFLICT (song_id, hour) DO UPDATE SET plays = song_plays_hourly.plays + EXCLUDED.plays
+		), daily AS (
+			INSERT INTO song_plays_daily (song_id, day, plays)
+			SELECT song_id, played_at::date, COUNT(*)
+			FROM plays
+			GROUP BY 1, 2
+			ON CONFLICT (song_id, day) DO UPDATE SET plays = song_plays_daily.plays + EXCLUDED.plays
+		)
+		SELECT COUNT(*) FROM plays;
+	`
+
+	// setPlayRollupQuery is
+	setPlayRollupQuery = `
+		UPDATE song_play_rollups SET rolled_until = $1;
+	`
+
+	// listTopSongsHourlyQuery and listTopSongsDailyQuery rank songs by plays since $1,
+	// $2 is normalized group or empty for all groups
+	listTopSongsHourlyQuery = `
+		SELECT songs.id AS song_id, songs."group", songs.title, SUM(rollup.plays)::BIGINT AS plays,
+			NULL::DOUBLE PRECISION AS score
+		FROM song_plays_hourly AS rollup
+		JOIN songs ON songs.id = rollup.song_id
+		WHERE rollup.hour >= $1 AND ($2::TEXT = '' OR lower(trim(songs."group")) = $2)
+		GROUP BY songs.id
+		ORDER BY plays DESC, songs.id
+		LIMIT $3;
+	`
+
+	listTopSongsDailyQuery = `
+		SELECT songs.id AS song_id, songs."group", songs.title, SUM(rollup.plays)::BIGINT AS plays,
+			NULL::DOUBLE PRECISION AS score
+		FROM song_plays_daily AS rollup
+		JOIN songs ON songs.id = rollup.song_id
+		WHERE rollup.day >= ($1::TIMESTAMPTZ AT TIME ZONE 'UTC')::DATE
+			AND ($2::TEXT = '' OR lower(trim(songs."group")) = $2)
+		GROUP BY songs.id
+		ORDER BY plays DESC, songs.id
+		LIMIT $3;
+	`
+
+	// listTrendingSongsQuery ranks songs by plays since $3 weighted by exp decay,
+	// play of hour which started $2 seconds before $1 weighs half
+	listTrendingSongsQuery = `
+		SELECT songs.id AS song_id, songs."group", songs.title, SUM(rollup.plays)::BIGINT AS plays,
+			SUM(rollup.plays * exp(-ln(2) * extract(epoch FROM $1::TIMESTAMPTZ - rollup.hour) / $2::DOUBLE PRECISION))::DOUBLE PRECISION AS score
+		FROM song_plays_hourly AS rollup
+		JOIN songs ON songs.id = rollup.song_id
+		WHERE rollup.hour >= $3 AND ($4::TEXT = '' OR lower(trim(songs."group")) = $4)
+		GROUP BY songs.id
+		ORDER BY score DESC, songs.id
+		LIMIT $5;
+	`
+
+	// moveSongPlaysQuery moves plays and their counts of songs $1 to song $2,
+	// rows of songs $1 left in rollups are deleted with songs
+	moveSongPlaysQuery = `
+		WITH plays AS (
+			UPDATE song_plays SET song_id = $2 WHERE song_id = ANY($1)
+		), hourly AS (
+			INSERT INTO song_plays_hourly (song_id, hour, plays)
+			SELECT $2, hour, SUM(plays) FROM song_plays_hourly WHERE song_id = ANY($1) GROUP BY hour
+			ON CONFLICT (song_id, hour) DO UPDATE SET plays = song_plays_hourly.plays + EXCLUDED.plays
+		)
+		INSERT INTO song_plays_daily (song_id, day, plays)
+		SELECT $2, day, SUM(plays) FROM song_plays_daily WHERE song_id = ANY($1) GROUP BY day
+		ON CONFLICT (song_id, day) DO UPDATE SET plays = song_plays_daily.plays + EXCLUDED.plays;
+	`
+
+	// songColumns are selected columns of songs
+	songColumns = `
+		songs.id, songs."group", songs.title, songs.release_date, songs.release_date_precision,
+		songs.text, songs.link, songs.link_provider, songs.link_id, songs.link_duration, songs.link_thumbnail_url,
+		songs.link_status, songs.link_status_code, songs.link_failures, songs.link_checked_at,
+		songs.version, songs.created_at, songs.updated_at
+	`
+
+	// countSongsQuery is, WHERE is built from filter
+	countSongsQuery = `
+		SELECT COUNT(*) FROM songs
+	`
+
+	// listSongsQuery is base of song list, WHERE, ORDER BY and LIMIT are built from
+	// parsed filter and pagination query, see SongRepository.ListSongs
+	listSongsQuery = `
+		SELECT` + songColumns + `
+		FROM songs
+	`
+
+	// exportSongsQuery is base of export, selected columns, WHERE and ORDER BY are
+	// built from export columns, filter and sort, see SongRepository.ExportSongs
+	exportSongsQuery = `
+		SELECT %s
+		FROM songs
+	`
+
+	// listUnenrichedSongsQuery is
+	listUnenrichedSongsQuery = `
+		SELECT` + songColumns + `
+		FROM songs
+		WHERE songs.link_enriched_at IS NULL
+		ORDER BY songs.id
+		LIMIT $1;
+	`
+
+	// saveLinkMetadataQuery doesn't bump version, metadata isn't edit of song.
+	// Link changed meanwhile is left for next enrichment.
+	saveLinkMetadataQuery = `
+		UPDATE songs
+		SET link_provider = $3, link_id = $4, link_duration = $5, link_thumbnail_url = $6, link_enriched_at = now()
+		WHERE id = $1 AND link = $2;
+	`
+
+	// claimLinkChecksQuery takes up to $1 links not checked for $2 seconds, check time is
+	// set at claim, so other instances skip them and link of crashed check waits for next round.
+	// Empty and non-http(s) links of songs stored before link validation are never checked.
+	claimLinkChecksQuery = `
+		UPDATE songs
+		SET link_checked_at = now()
+		WHERE id IN (
+			SELECT id
+			FROM songs
+			WHERE link <> '' AND link ~* '^https?://'
+				AND (link_checked_at IS NULL OR link_checked_at < now() - make_interval(secs => $2))
+			ORDER BY link_checked_at NULLS FIRST, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, link;
+	`
+
+	// saveLinkCheckQuery counts consecutive failures, link is broken after $5 of them.
+	// Link changed meanwhile is left for next check.
+	saveLinkCheckQuery = `
+		UPDATE songs
+		SET link_failures = CASE WHEN $3 THEN link_failures + 1 ELSE 0 END,
+			link_status = CASE
+				WHEN NOT $3 THEN 'ok'
+				WHEN link_failures + 1 >= $5 THEN 'broken'
+				ELSE 'failing'
+			END,
+			link_status_code = $4, link_checked_at = now()
+		WHERE id = $1 AND link = $2;
+	`
+
+	// listSongDuplicatesQuery wraps union of duplicateQueries, $1 is lowest lyrics
+	// similarity (exact matches have 1), $2 and $3 are limit and offset
+	listSongDuplicatesQuery = `
+		SELECT reason, similarity, song_ids, count(*) OVER () AS total_count
+		FROM (%s) AS duplicates
+		WHERE similarity >= $1::float8
+		ORDER BY reason, similarity DESC, song_ids[1]
+		LIMIT $2 OFFSET $3;
+	`
 )
+
+// releaseDateExpression formats release date like releasedate.Date.String
+const releaseDateExpression = `CASE songs.release_date_precision
+		WHEN 'year' THEN to_char(songs.release_date, 'YYYY')
+		WHEN 'month' THEN to_char(songs.release_date, 'YYYY-MM')
+		ELSE to_char(songs.release_date, 'YYYY-MM-DD')
+	END`
+
+// songExportColumns maps export columns to SQL expressions, release date is
+// exported in form of its precision, so import reads it back the same
+var songExportColumns = map[string]string{
+	"id":                 "songs.id",
+	"group":              `songs."group"`,
+	"title":              "songs.title",
+	"release_date":       releaseDateExpression,
+	"text":               "songs.text",
+	"link":               "songs.link",
+	"link_provider":      "songs.link_provider",
+	"link_id":            "songs.link_id",
+	"link_duration":      "songs.link_duration",
+	"link_thumbnail_url": "songs.link_thumbnail_url",
+	"link_status":        "songs.link_status",
+	"link_status_code":   "songs.link_status_code",
+	"link_failures":      "songs.link_failures",
+	"link_checked_at":    "songs.link_checked_at",
+	"version":            "songs.version",
+	"created_at":         "songs.created_at",
+	"updated_at":         "songs.updated_at",
+}
+
+// duplicateQueries find duplicate candidates of every reason, lyrics are compared
+// with pg_trgm, % uses trigram index and similarity refines its 0.3 default threshold
+var duplicateQueries = map[string]string{
+	songModel.DuplicateSameKey: `
+		SELECT 'same_key' AS reason, 1::float8 AS similarity, array_agg(id ORDER BY id) AS song_ids
+		FROM songs
+		GROUP BY lower(trim("group")), lower(trim(title))
+		HAVING count(*) > 1
+	`,
+	songModel.DuplicateSameLink: `
+		SELECT 'same_link' AS reason, 1::float8 AS similarity, array_agg(id ORDER BY id) AS song_ids
+		FROM songs
+		WHERE trim(link) <> ''
+		GROUP BY CASE WHEN link_id <> '' THEN link_provider || ':' || link_id ELSE lower(trim(link)) END
+		HAVING count(*) > 1
+	`,
+	songModel.DuplicateSimilarLyrics: `
+		SELECT 'similar_lyrics' AS reason, similarity(a.text, b.text)::float8 AS similarity, ARRAY[a.id, b.id] AS song_ids
+		FROM songs a
+		JOIN songs b ON a.id < b.id AND a.text % b.text
+		WHERE similarity(a.text, b.text) >= $1
+	`,
+}
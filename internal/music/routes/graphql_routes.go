@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/jumayevgadam/music-app/internal/database"
+	"github.com/jumayevgadam/music-app/internal/music/graph"
+	"github.com/jumayevgadam/music-app/internal/music/service"
+	"github.com/jumayevgadam/music-app/pkg/blobstore"
+	"github.com/labstack/echo/v4"
+)
+
+// GraphQLRoutes is
+func GraphQLRoutes(e *echo.Echo, dataStore database.DataStore, blobs blobstore.BlobStore) {
+	// init Service
+	Service := service.NewSongService(dataStore, blobs)
+	// init Handler
+	Handler := graph.NewGraphQLHandler(Service)
+
+	// Endpoints are
+	{
+		e.GET("/graphql", Handler.Query())
+		e.POST("/graphql", Handler.Query())
+	}
+}
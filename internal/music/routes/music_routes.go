@@ -1,26 +1,67 @@
 package routes
 
 import (
+	"github.com/jumayevgadam/music-app/internal/config"
 	"github.com/jumayevgadam/music-app/internal/database"
 	"github.com/jumayevgadam/music-app/internal/music/handler"
 	"github.com/jumayevgadam/music-app/internal/music/service"
+	"github.com/jumayevgadam/music-app/pkg/blobstore"
 	"github.com/labstack/echo/v4"
 )
 
 // We use in routes package needed http routes for songs
 
-// Routes is
-func Routes(e *echo.Group, dataStore database.DataStore) {
+// Routes is, idempotent middleware guards song creation from retried requests,
+// signed middleware lets players stream by signed URLs, blobs keep uploaded audio
+func Routes(
+	e *echo.Group,
+	dataStore database.DataStore,
+	blobs blobstore.BlobStore,
+	storage config.Storage,
+	idempotent echo.MiddlewareFunc,
+	signed echo.MiddlewareFunc,
+) {
 	// init Service
-	Service := service.NewSongService(dataStore)
+	Service := service.NewSongService(dataStore, blobs)
 	// init Handler
-	Handler := handler.NewSongHandler(Service)
+	Handler := handler.NewSongHandler(Service, storage)
 
 	// init main group for songs
 	songGroup := e.Group("/song")
 
 	// Endpoints are
 	{
-		songGroup.POST("/create", Handler.AddSong())
+		songGroup.POST("/create", Handler.AddSong(), idempotent)
+		songGroup.GET("", Handler.ListSongs())
+		songGroup.POST("/import", Handler.ImportSongs())
+		songGroup.GET("/export", Handler.ExportSongs())
+		songGroup.GET("/duplicates", Handler.ListSongDuplicates())
+		songGroup.GET("/charts/top", Handler.TopSongs())
+		songGroup.GET("/charts/trending", Handler.TrendingSongs())
+		songGroup.GET("/:id", Handler.GetSong())
+		songGroup.PUT("/:id", Handler.UpdateSong())
+		songGroup.PATCH("/:id", Handler.PatchSong())
+		songGroup.DELETE("/:id", Handler.DeleteSong())
+		songGroup.GET("/:id/history", Handler.ListSongHistory())
+		songGroup.POST("/:id/history/:history_id/restore", Handler.RestoreSong())
+		songGroup.POST("/:id/merge", Handler.MergeSongs())
+		songGroup.GET("/:id/lyrics", Handler.GetSongLyrics())
+		songGroup.PUT("/:id/lyrics", Handler.PutSongLyrics())
+		songGroup.GET("/:id/lyrics/line", Handler.GetLyricLineAt())
+		songGroup.GET("/:id/lyrics/variants", Handler.ListLyricVariants())
+		songGroup.GET("/:id/lyrics/variants/:language", Handler.GetLyricVariant())
+		songGroup.PUT("/:id/lyrics/variants/:language", Handler.PutLyricVariant())
+		songGroup.DELETE("/:id/lyrics/variants/:language", Handler.DeleteLyricVariant())
+		songGroup.POST("/:id/audio", Handler.UploadSongAudio())
+		songGroup.GET("/:id/audio", Handler.GetSongAudio())
+		songGroup.DELETE("/:id/audio", Handler.DeleteSongAudio())
+		songGroup.GET("/:id/stream", Handler.StreamSongAudio(), signed)
+		songGroup.HEAD("/:id/stream", Handler.StreamSongAudio(), signed)
+		songGroup.GET("/:id/stream/url", Handler.SignSongStreamURL(), signed)
+		songGroup.POST("/:id/cover", Handler.UploadSongCover())
+		songGroup.GET("/:id/cover", Handler.GetSongCover())
+		songGroup.HEAD("/:id/cover", Handler.GetSongCover())
+		songGroup.DELETE("/:id/cover", Handler.DeleteSongCover())
+		songGroup.POST("/:id/play", Handler.RecordPlay())
 	}
 }
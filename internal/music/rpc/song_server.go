@@ -0,0 +1,311 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	songv1 "github.com/jumayevgadam/music-app/api/song/v1"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/internal/music/songio"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/filter"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+	"github.com/jumayevgadam/music-app/pkg/releasedate"
+	"github.com/jumayevgadam/music-app/pkg/reqvalidator"
+	"google.golang.org/grpc"
+)
+
+// We use in rpc package gRPC transport of songs, it calls same music.Service
+// as HTTP handlers, errors are mapped with errlst.GRPCError
+
+var _ songv1.SongServiceServer = (*SongServer)(nil)
+
+// SongServer struct is
+type SongServer struct {
+	songv1.UnimplementedSongServiceServer
+	service musicOps.Service
+}
+
+// NewSongServer method is
+func NewSongServer(service musicOps.Service) *SongServer {
+	return &SongServer{service: service}
+}
+
+// Register adds song service to gRPC server
+func Register(server *grpc.Server, service musicOps.Service) {
+	songv1.RegisterSongServiceServer(server, NewSongServer(service))
+}
+
+// AddSong is
+func (s *SongServer) AddSong(ctx context.Context, req *songv1.AddSongRequest) (*songv1.AddSongResponse, error) {
+	releaseDate, err := parseReleaseDate(req.GetReleaseDate())
+	if err != nil {
+		return nil, errlst.GRPCError(err)
+	}
+
+	song := &songModel.DTO{
+		Group:       req.GetGroup(),
+		Title:       req.GetTitle(),
+		ReleaseDate: releaseDate,
+		Text:        req.GetText(),
+		Link:        req.GetLink(),
+	}
+
+	if err := reqvalidator.ValidateStruct(ctx, song); err != nil {
+		return nil, errlst.GRPCError(err)
+	}
+
+	songID, err := s.service.AddSong(ctx, song)
+	if err != nil {
+		return nil, errlst.GRPCError(err)
+	}
+
+	return &songv1.AddSongResponse{Id: int64(songID)}, nil
+}
+
+// GetSong is
+func (s *SongServer) GetSong(ctx context.Context, req *songv1.GetSongRequest) (*songv1.Song, error) {
+	song, err := s.service.GetSong(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, errlst.GRPCError(err)
+	}
+
+	return toProto(song), nil
+}
+
+// UpdateSong is
+func (s *SongServer) UpdateSong(ctx context.Context, req *songv1.UpdateSongRequest) (*songv1.Song, error) {
+	releaseDate, err := parseReleaseDate(req.GetReleaseDate())
+	if err != nil {
+		return nil, errlst.GRPCError(err)
+	}
+
+	song := &songModel.DTO{
+		ID:          int(req.GetId()),
+		Group:       req.GetGroup(),
+		Title:       req.GetTitle(),
+		ReleaseDate: releaseDate,
+		Text:        req.GetText(),
+		Link:        req.GetLink(),
+		Version:     int(req.GetVersion()),
+	}
+
+	if err := reqvalidator.ValidateStruct(ctx, song); err != nil {
+		return nil, errlst.GRPCError(err)
+	}
+
+	updated, err := s.service.UpdateSong(ctx, song)
+	if err != nil {
+		return nil, errlst.GRPCError(err)
+	}
+
+	return toProto(updated), nil
+}
+
+// DeleteSong is
+func (s *SongServer) DeleteSong(ctx context.Context, req *songv1.DeleteSongRequest) (*songv1.DeleteSongResponse, error) {
+	if err := s.service.DeleteSong(ctx, int(req.GetId()), int(req.GetVersion())); err != nil {
+		return nil, errlst.GRPCError(err)
+	}
+
+	return &songv1.DeleteSongResponse{}, nil
+}
+
+// ListSongs streams songs of one page, then page info
+func (s *SongServer) ListSongs(req *songv1.ListSongsRequest, stream songv1.SongService_ListSongsServer) error {
+	ctx := stream.Context()
+
+	songFilter, err := filter.Parse(req.GetFilter(), musicOps.SongFilterFields)
+	if err != nil {
+		return errlst.GRPCError(err)
+	}
+
+	paginationQuery, err := paginationFromRequest(req)
+	if err != nil {
+		return errlst.GRPCError(err)
+	}
+
+	list, err := s.service.ListSongs(ctx, songFilter, paginationQuery)
+	if err != nil {
+		return errlst.GRPCError(err)
+	}
+
+	for _, song := range list.Songs {
+		if err := stream.Send(&songv1.ListSongsResponse{Item: &songv1.ListSongsResponse_Song{Song: toProto(song)}}); err != nil {
+			return err
+		}
+	}
+
+	return stream.Send(&songv1.ListSongsResponse{Item: &songv1.ListSongsResponse_PageInfo{PageInfo: &songv1.PageInfo{
+		Page:       int32(list.Page),
+		Size:       int32(list.Size),
+		TotalCount: int32(list.TotalCount),
+		TotalPages: int32(list.TotalPages),
+		HasMore:    list.HasMore,
+		NextCursor: list.NextCursor,
+		PrevCursor: list.PrevCursor,
+	}}})
+}
+
+// ExportSongs streams every song matching filter straight from database cursor
+func (s *SongServer) ExportSongs(req *songv1.ExportSongsRequest, stream songv1.SongService_ExportSongsServer) error {
+	songFilter, err := filter.Parse(req.GetFilter(), musicOps.SongFilterFields)
+	if err != nil {
+		return errlst.GRPCError(err)
+	}
+
+	sort, err := pagination.ParseSort(req.GetOrderBy(), musicOps.SongSortFields, "id")
+	if err != nil {
+		return errlst.GRPCError(err)
+	}
+
+	writer := &streamWriter{stream: stream, columns: songio.Columns}
+	if err := s.service.ExportSongs(stream.Context(), songFilter, sort, songio.Columns, writer); err != nil {
+		return errlst.GRPCError(err)
+	}
+
+	return nil
+}
+
+// paginationFromRequest builds pagination query same way GetPaginationFromCtx does
+func paginationFromRequest(req *songv1.ListSongsRequest) (*pagination.PaginationQuery, error) {
+	page, size := "", ""
+	if req.GetPage() != 0 {
+		page = strconv.Itoa(int(req.GetPage()))
+	}
+	if req.GetSize() != 0 {
+		size = strconv.Itoa(int(req.GetSize()))
+	}
+
+	paginationQuery, err := pagination.GetPagination(page, size, req.GetOrderBy(), req.GetCursor())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := paginationQuery.SetSort(musicOps.SongSortFields, musicOps.DefaultSongSort); err != nil {
+		return nil, err
+	}
+
+	return paginationQuery, nil
+}
+
+// streamWriter is songio.RowWriter sending every row as Song message
+type streamWriter struct {
+	stream  songv1.SongService_ExportSongsServer
+	columns []string
+}
+
+// Write is
+func (w *streamWriter) Write(values []interface{}) error {
+	song := &songv1.Song{}
+
+	for i, column := range w.columns {
+		value := fmt.Sprint(values[i])
+		switch v := values[i].(type) {
+		case nil:
+			value = ""
+		case time.Time:
+			value = v.Format(time.RFC3339Nano)
+		}
+
+		switch column {
+		case "id":
+			id, _ := strconv.ParseInt(value, 10, 64)
+			song.Id = id
+		case "group":
+			song.Group = value
+		case "title":
+			song.Title = value
+		case "release_date":
+			song.ReleaseDate = value
+		case "text":
+			song.Text = value
+		case "link":
+			song.Link = value
+		case "created_at":
+			song.CreatedAt = value
+		case "updated_at":
+			song.UpdatedAt = value
+		case "version":
+			version, _ := strconv.ParseInt(value, 10, 64)
+			song.Version = version
+		case "link_provider":
+			song.LinkProvider = value
+		case "link_id":
+			song.LinkId = value
+		case "link_duration":
+			duration, _ := strconv.ParseInt(value, 10, 32)
+			song.LinkDuration = int32(duration)
+		case "link_thumbnail_url":
+			song.LinkThumbnailUrl = value
+		case "link_status":
+			song.LinkStatus = value
+		case "link_failures":
+			failures, _ := strconv.ParseInt(value, 10, 32)
+			song.LinkFailures = int32(failures)
+		case "link_checked_at":
+			song.LinkCheckedAt = value
+		}
+	}
+
+	return w.stream.Send(song)
+}
+
+// Close is
+func (w *streamWriter) Close() error {
+	return nil
+}
+
+// toProto converts DTO to Song message
+func toProto(song *songModel.DTO) *songv1.Song {
+	return &songv1.Song{
+		Id:               int64(song.ID),
+		Group:            song.Group,
+		Title:            song.Title,
+		ReleaseDate:      song.ReleaseDate.String(),
+		Text:             song.Text,
+		Link:             song.Link,
+		CreatedAt:        song.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt:        song.UpdatedAt.Format(time.RFC3339Nano),
+		Version:          int64(song.Version),
+		LinkProvider:     song.LinkProvider,
+		LinkId:           song.LinkID,
+		LinkDuration:     int32(ptrValue(song.LinkDuration)),
+		LinkThumbnailUrl: ptrValue(song.LinkThumbnailURL),
+		LinkStatus:       song.LinkStatus,
+		LinkFailures:     int32(song.LinkFailures),
+		LinkCheckedAt:    formatTime(song.LinkCheckedAt),
+	}
+}
+
+// parseReleaseDate is, empty date is left to validation of required fields
+func parseReleaseDate(raw string) (releasedate.Date, error) {
+	var date releasedate.Date
+	if err := date.UnmarshalText([]byte(raw)); err != nil {
+		return date, errlst.Validation(err.Error(), err)
+	}
+
+	return date, nil
+}
+
+// formatTime returns time in RFC 3339, empty string for nil
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
+
+// ptrValue returns value of nullable field, zero value for nil
+func ptrValue[T any](value *T) T {
+	var zero T
+	if value == nil {
+		return zero
+	}
+
+	return *value
+}
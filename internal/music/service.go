@@ -2,7 +2,16 @@ package music
 
 import (
 	"context"
+	"io"
+	"strings"
+	"time"
+
 	songModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/internal/music/songio"
+	"github.com/jumayevgadam/music-app/pkg/blobstore"
+	"github.com/jumayevgadam/music-app/pkg/filter"
+	"github.com/jumayevgadam/music-app/pkg/lrc"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
 )
 
 // write needed methods for service layer
@@ -10,4 +19,39 @@ import (
 // Service is
 type Service interface {
 	AddSong(ctx context.Context, dtoModel *songModel.DTO) (int, error)
+	GetSong(ctx context.Context, songID int) (*songModel.DTO, error)
+	GetSongsByIDs(ctx context.Context, songIDs []int) (map[int]*songModel.DTO, error)
+	ListSongsByGroups(ctx context.Context, groups []string, limit int) (map[string][]*songModel.DTO, error)
+	UpdateSong(ctx context.Context, dtoModel *songModel.DTO) (*songModel.DTO, error)
+	PatchSong(ctx context.Context, songID int, version int, patch *songModel.SongPatchDTO) (*songModel.DTO, error)
+	DeleteSong(ctx context.Context, songID int, version int) error
+	ListSongHistory(ctx context.Context, songID int) ([]*songModel.SongHistoryDTO, error)
+	RestoreSong(ctx context.Context, songID int, historyID int64) (*songModel.DTO, error)
+	ListSongDuplicates(ctx context.Context, duplicateFilter songModel.DuplicateFilterDAO, paginationQuery *pagination.PaginationQuery) (*songModel.SongDuplicateListDTO, error)
+	MergeSongs(ctx context.Context, canonicalID int, duplicateIDs []int) (*songModel.DTO, error)
+	GetSongLyrics(ctx context.Context, songID int, acceptLanguage string) (*songModel.SongLyricsDTO, error)
+	PutSongLyrics(ctx context.Context, songID int, version int, lyrics *lrc.Lyrics) (*songModel.SongLyricsDTO, error)
+	GetLyricLineAt(ctx context.Context, songID int, at time.Duration) (*songModel.LyricLineAtDTO, error)
+	ListLyricVariants(ctx context.Context, songID int) ([]*songModel.LyricVariantDTO, error)
+	GetLyricVariant(ctx context.Context, songID int, language string) (*songModel.LyricVariantDTO, error)
+	PutLyricVariant(ctx context.Context, songID int, version int, dtoModel *songModel.LyricVariantDTO) (*songModel.LyricVariantDTO, int, error)
+	DeleteLyricVariant(ctx context.Context, songID int, version int, language string) (int, error)
+	UploadSongAudio(ctx context.Context, songID int, upload *AudioUpload) (*songModel.SongAudioUploadDTO, error)
+	GetSongAudio(ctx context.Context, songID int) (*songModel.SongAudioDTO, error)
+	OpenSongAudio(ctx context.Context, songID int) (*songModel.SongAudioDTO, blobstore.Object, error)
+	DeleteSongAudio(ctx context.Context, songID int) error
+	UploadSongCover(ctx context.Context, songID int, file io.ReadSeeker) (*songModel.SongCoverDTO, error)
+	OpenSongCover(ctx context.Context, songID int, size int) (*songModel.SongCoverDTO, blobstore.Object, error)
+	DeleteSongCover(ctx context.Context, songID int) error
+	RecordPlay(ctx context.Context, songID int, dtoModel *songModel.SongPlayDTO) error
+	TopSongs(ctx context.Context, period string, group string, limit int) (*songModel.ChartDTO, error)
+	TrendingSongs(ctx context.Context, group string, limit int) (*songModel.ChartDTO, error)
+	ExportSongs(ctx context.Context, songFilter *filter.Filter, sort pagination.SortSpec, columns []string, writer songio.RowWriter) error
+	ImportSongs(ctx context.Context, rows songio.RowReader, dryRun bool) (*songModel.ImportReportDTO, error)
+	ListSongs(ctx context.Context, songFilter *filter.Filter, paginationQuery *pagination.PaginationQuery) (*songModel.SongListDTO, error)
+}
+
+// NormalizeGroup is key songs of same group are matched by
+func NormalizeGroup(group string) string {
+	return strings.ToLower(strings.TrimSpace(group))
 }
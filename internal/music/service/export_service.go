@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jumayevgadam/music-app/internal/music/songio"
+	"github.com/jumayevgadam/music-app/pkg/filter"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+	"go.opentelemetry.io/otel"
+)
+
+// ExportSongs service streams every song matching filter to writer, rows go
+// straight from database cursor to writer without collecting them in memory.
+// Writer is closed only when all rows are written.
+func (s *SongService) ExportSongs(
+	ctx context.Context,
+	songFilter *filter.Filter,
+	sort pagination.SortSpec,
+	columns []string,
+	writer songio.RowWriter,
+) error {
+	tracer := otel.Tracer("[ExportSongs][Service]")
+	ctx, span := tracer.Start(ctx, "ExportSongs")
+	defer span.End()
+
+	if err := s.repo.SongRepo().ExportSongs(ctx, songFilter, sort, columns, writer.Write); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
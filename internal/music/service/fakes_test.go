@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jumayevgadam/music-app/internal/database"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/internal/webhook"
+	"github.com/jumayevgadam/music-app/pkg/blobstore"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+)
+
+// fakeStore is in-memory DataStore of service tests, methods tests
+// don't need panic through nil embedded interface
+type fakeStore struct {
+	database.DataStore
+	songs        *fakeSongRepo
+	webhooks     *fakeWebhookRepo
+	blobs        *fakeBlobStore
+	transactions int
+}
+
+func newFakeStore(songs ...*songModel.DAO) *fakeStore {
+	repo := &fakeSongRepo{
+		songs:    make(map[int]*songModel.DAO),
+		variants: make(map[int][]*songModel.LyricVariantDAO),
+		audio:    make(map[int]*songModel.SongAudioDAO),
+		covers:   make(map[int]*songModel.SongCoverDAO),
+		plays:    make(map[int]int),
+	}
+	for _, song := range songs {
+		repo.songs[song.ID] = song
+	}
+
+	return &fakeStore{songs: repo, webhooks: &fakeWebhookRepo{}, blobs: &fakeBlobStore{}}
+}
+
+func (f *fakeStore) SongRepo() music.Repository {
+	return f.songs
+}
+
+func (f *fakeStore) WebhookRepo() webhook.Repository {
+	return f.webhooks
+}
+
+func (f *fakeStore) WithTransaction(ctx context.Context, tx database.Transaction) error {
+	f.transactions++
+	return tx(f)
+}
+
+// fakeSongRepo keeps songs by id, stored songs are copied in and out
+// so tests see only changes made through repository
+type fakeSongRepo struct {
+	music.Repository
+	songs    map[int]*songModel.DAO
+	variants map[int][]*songModel.LyricVariantDAO
+	audio    map[int]*songModel.SongAudioDAO
+	covers   map[int]*songModel.SongCoverDAO
+	plays    map[int]int
+	copied   []*songModel.DAO
+	history  []*songModel.SongHistoryDAO
+	// charts are returned by chart queries, which are kept
+	charts        []*songModel.ChartEntryDAO
+	topQuery      *songModel.ChartQueryDAO
+	trendingQuery *songModel.TrendingQueryDAO
+	// beforeWrite runs before song is updated or deleted, tests use it
+	// to change song concurrently
+	beforeWrite func(songID int)
+}
+
+func (r *fakeSongRepo) GetSong(ctx context.Context, songID int) (*songModel.DAO, error) {
+	song, ok := r.songs[songID]
+	if !ok {
+		return nil, errlst.NotFound(fmt.Sprintf("song %d not found", songID), errlst.ErrNoRecord)
+	}
+
+	stored := *song
+	return &stored, nil
+}
+
+func (r *fakeSongRepo) UpdateSong(ctx context.Context, daoModel *songModel.DAO) error {
+	if r.beforeWrite != nil {
+		r.beforeWrite(daoModel.ID)
+	}
+
+	song, ok := r.songs[daoModel.ID]
+	if !ok || daoModel.Version != 0 && daoModel.Version != song.Version {
+		return errlst.NotFound(fmt.Sprintf("song %d not found", daoModel.ID), errlst.ErrNoRecord)
+	}
+
+	updated := *daoModel
+	updated.CreatedAt, updated.Version = song.CreatedAt, song.Version+1
+	r.songs[daoModel.ID] = &updated
+
+	return nil
+}
+
+func (r *fakeSongRepo) DeleteSong(ctx context.Context, songID int, version int) error {
+	if r.beforeWrite != nil {
+		r.beforeWrite(songID)
+	}
+
+	song, ok := r.songs[songID]
+	if !ok || version != 0 && version != song.Version {
+		return errlst.NotFound(fmt.Sprintf("song %d not found", songID), errlst.ErrNoRecord)
+	}
+	// rows referencing song are deleted with it
+	delete(r.songs, songID)
+	delete(r.variants, songID)
+	delete(r.audio, songID)
+	delete(r.covers, songID)
+	delete(r.plays, songID)
+
+	return nil
+}
+
+func (r *fakeSongRepo) RestoreSong(ctx context.Context, daoModel *songModel.DAO) error {
+	if _, ok := r.songs[daoModel.ID]; ok {
+		return errlst.Conflict(fmt.Sprintf("song %d exists", daoModel.ID), errlst.ErrConflict)
+	}
+
+	// version continues after last one in history
+	restored := *daoModel
+	restored.Version = 1
+	for _, entry := range r.songHistory(daoModel.ID) {
+		var snapshot songModel.DTO
+		if err := json.Unmarshal(entry.Snapshot, &snapshot); err == nil {
+			restored.Version = max(restored.Version, snapshot.Version+1)
+		}
+	}
+	r.songs[daoModel.ID] = &restored
+
+	return nil
+}
+
+func (r *fakeSongRepo) AddSongHistory(ctx context.Context, entries []*songModel.SongHistoryDAO) error {
+	for _, entry := range entries {
+		entry.ID = int64(len(r.history) + 1)
+		r.history = append(r.history, entry)
+	}
+
+	return nil
+}
+
+func (r *fakeSongRepo) GetSongHistory(ctx context.Context, songID int, historyID int64) (*songModel.SongHistoryDAO, error) {
+	for _, entry := range r.history {
+		if entry.ID == historyID && entry.SongID == songID {
+			return entry, nil
+		}
+	}
+
+	return nil, errlst.NotFound(fmt.Sprintf("history entry %d not found", historyID), errlst.ErrNoRecord)
+}
+
+// songHistory returns entries of song, oldest first
+func (r *fakeSongRepo) songHistory(songID int) []*songModel.SongHistoryDAO {
+	var entries []*songModel.SongHistoryDAO
+	for _, entry := range r.history {
+		if entry.SongID == songID {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+func (r *fakeSongRepo) FindSongsByKeys(ctx context.Context, keys []songModel.SongKeyDAO) ([]songModel.SongKeyDAO, error) {
+	var found []songModel.SongKeyDAO
+	for _, key := range keys {
+		for _, song := range r.songs {
+			if strings.ToLower(song.Group) == key.Group && strings.ToLower(song.Title) == key.Title {
+				found = append(found, songModel.SongKeyDAO{ID: song.ID, Group: key.Group, Title: key.Title})
+			}
+		}
+	}
+
+	return found, nil
+}
+
+func (r *fakeSongRepo) CopySongs(ctx context.Context, daoModels []*songModel.DAO) (int64, error) {
+	for _, song := range daoModels {
+		song.ID = r.nextID()
+		r.songs[song.ID] = song
+	}
+	r.copied = append(r.copied, daoModels...)
+
+	return int64(len(daoModels)), nil
+}
+
+func (r *fakeSongRepo) GetSongsByIDs(ctx context.Context, songIDs []int) ([]*songModel.DAO, error) {
+	var songs []*songModel.DAO
+	for _, songID := range songIDs {
+		if song, ok := r.songs[songID]; ok {
+			stored := *song
+			songs = append(songs, &stored)
+		}
+	}
+
+	return songs, nil
+}
+
+func (r *fakeSongRepo) MoveLyricVariants(ctx context.Context, fromSongIDs []int, toSongID int) ([]*songModel.LyricVariantDAO, error) {
+	var moved []*songModel.LyricVariantDAO
+	for _, songID := range slices.Sorted(slices.Values(fromSongIDs)) {
+		for _, variant := range r.variants[songID] {
+			if slices.ContainsFunc(r.variants[toSongID], func(v *songModel.LyricVariantDAO) bool {
+				return v.Language == variant.Language
+			}) {
+				continue
+			}
+
+			translation := *variant
+			translation.SongID, translation.Original = toSongID, false
+			r.variants[toSongID] = append(r.variants[toSongID], &translation)
+			moved = append(moved, &translation)
+		}
+	}
+
+	return moved, nil
+}
+
+func (r *fakeSongRepo) GetSongAudio(ctx context.Context, songID int) (*songModel.SongAudioDAO, error) {
+	audio, ok := r.audio[songID]
+	if !ok {
+		return nil, errlst.NotFound(fmt.Sprintf("audio of song %d not found", songID), errlst.ErrNoRecord)
+	}
+
+	stored := *audio
+	return &stored, nil
+}
+
+func (r *fakeSongRepo) CountAudioBlobReferences(ctx context.Context, blobKey string) (int, error) {
+	count := 0
+	for _, audio := range r.audio {
+		if audio.BlobKey == blobKey {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *fakeSongRepo) MoveSongAudio(ctx context.Context, fromSongIDs []int, toSongID int) error {
+	if _, ok := r.audio[toSongID]; ok {
+		return nil
+	}
+
+	for _, songID := range slices.Sorted(slices.Values(fromSongIDs)) {
+		if audio, ok := r.audio[songID]; ok {
+			audio.SongID = toSongID
+			r.audio[toSongID] = audio
+			delete(r.audio, songID)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeSongRepo) GetSongCover(ctx context.Context, songID int) (*songModel.SongCoverDAO, error) {
+	cover, ok := r.covers[songID]
+	if !ok {
+		return nil, errlst.NotFound(fmt.Sprintf("cover of song %d not found", songID), errlst.ErrNoRecord)
+	}
+
+	stored := *cover
+	return &stored, nil
+}
+
+func (r *fakeSongRepo) CountCoverBlobReferences(ctx context.Context, blobKey string) (int, error) {
+	count := 0
+	for _, cover := range r.covers {
+		if cover.BlobKey == blobKey {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *fakeSongRepo) MoveSongCover(ctx context.Context, fromSongIDs []int, toSongID int) error {
+	if _, ok := r.covers[toSongID]; ok {
+		return nil
+	}
+
+	for _, songID := range slices.Sorted(slices.Values(fromSongIDs)) {
+		if cover, ok := r.covers[songID]; ok {
+			cover.SongID = toSongID
+			r.covers[toSongID] = cover
+			delete(r.covers, songID)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeSongRepo) MoveSongPlays(ctx context.Context, fromSongIDs []int, toSongID int) error {
+	for _, songID := range fromSongIDs {
+		r.plays[toSongID] += r.plays[songID]
+		delete(r.plays, songID)
+	}
+
+	return nil
+}
+
+func (r *fakeSongRepo) ListTopSongs(ctx context.Context, chartQuery songModel.ChartQueryDAO) ([]*songModel.ChartEntryDAO, error) {
+	r.topQuery = &chartQuery
+	return r.charts, nil
+}
+
+func (r *fakeSongRepo) ListTrendingSongs(ctx context.Context, trendingQuery songModel.TrendingQueryDAO) ([]*songModel.ChartEntryDAO, error) {
+	r.trendingQuery = &trendingQuery
+	return r.charts, nil
+}
+
+func (r *fakeSongRepo) nextID() int {
+	next := 1
+	for songID := range r.songs {
+		next = max(next, songID+1)
+	}
+
+	return next
+}
+
+// fakeWebhookRepo keeps outbox events
+type fakeWebhookRepo struct {
+	webhook.Repository
+	events []*songModel.OutboxEventDAO
+}
+
+func (r *fakeWebhookRepo) AddOutboxEvents(ctx context.Context, events []*songModel.OutboxEventDAO) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+// fakeBlobStore records deleted blobs
+type fakeBlobStore struct {
+	blobstore.BlobStore
+	deleted []string
+}
+
+func (b *fakeBlobStore) Delete(ctx context.Context, key string) error {
+	b.deleted = append(b.deleted, key)
+	return nil
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jumayevgadam/music-app/internal/database"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/internal/music/songio"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/reqvalidator"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	// importBatchSize is number of rows written with one COPY
+	importBatchSize = 1000
+	// maxReportedRows caps rejected and duplicate lists of import report
+	maxReportedRows = 1000
+)
+
+// songImport keeps state of one running import
+type songImport struct {
+	report *songModel.ImportReportDTO
+	// seen maps normalized key to first row having it in this import
+	seen  map[songModel.SongKeyDAO]int
+	batch []importRow
+}
+
+// importRow is validated row waiting for batch flush
+type importRow struct {
+	row  int
+	key  songModel.SongKeyDAO
+	song *songModel.DAO
+}
+
+// ImportSongs service validates every row and writes accepted ones in batches
+// with COPY inside one transaction. In dry run nothing is written, but report
+// is the same, duplicates are checked against catalogue as well.
+func (s *SongService) ImportSongs(ctx context.Context, rows songio.RowReader, dryRun bool) (*songModel.ImportReportDTO, error) {
+	tracer := otel.Tracer("[ImportSongs][Service]")
+	ctx, span := tracer.Start(ctx, "ImportSongs")
+	defer span.End()
+
+	imp := &songImport{
+		report: &songModel.ImportReportDTO{
+			DryRun:     dryRun,
+			Rejected:   []songModel.ImportRejectedRow{},
+			Duplicates: []songModel.ImportDuplicateRow{},
+		},
+		seen: make(map[songModel.SongKeyDAO]int),
+	}
+
+	if dryRun {
+		if err := imp.run(ctx, s.repo, rows, true); err != nil {
+			return nil, err
+		}
+
+		return imp.report, nil
+	}
+
+	if err := s.repo.WithTransaction(ctx, func(db database.DataStore) error {
+		return imp.run(ctx, db, rows, false)
+	}); err != nil {
+		return nil, err
+	}
+
+	return imp.report, nil
+}
+
+// run reads rows till the end, flushing every full batch
+func (imp *songImport) run(ctx context.Context, db database.DataStore, rows songio.RowReader, dryRun bool) error {
+	for {
+		song, err := rows.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rowErr *songio.RowError
+		if errors.As(err, &rowErr) {
+			imp.reject(rowErr.Row, rowErr.Err.Error())
+			continue
+		}
+		if err != nil {
+			return errlst.Validation("can't read import body", err)
+		}
+
+		row := rows.Row()
+		if err := reqvalidator.ValidateStruct(ctx, song); err != nil {
+			imp.reject(row, fmt.Sprint(errlst.ParseValidatorError(err).Causes()))
+			continue
+		}
+
+		key := songModel.SongKeyDAO{
+			Group: musicOps.NormalizeGroup(song.Group),
+			Title: strings.ToLower(strings.TrimSpace(song.Title)),
+		}
+		if firstRow, ok := imp.seen[key]; ok {
+			imp.duplicate(songModel.ImportDuplicateRow{Row: row, Group: song.Group, Title: song.Title, DuplicateOfRow: firstRow})
+			continue
+		}
+		imp.seen[key] = row
+
+		imp.batch = append(imp.batch, importRow{row: row, key: key, song: song.ToStorage()})
+		if len(imp.batch) >= importBatchSize {
+			if err := imp.flush(ctx, db, dryRun); err != nil {
+				return err
+			}
+		}
+	}
+
+	return imp.flush(ctx, db, dryRun)
+}
+
+// flush drops rows already existing in catalogue and copies the rest
+func (imp *songImport) flush(ctx context.Context, db database.DataStore, dryRun bool) error {
+	if len(imp.batch) == 0 {
+		return nil
+	}
+	defer func() { imp.batch = imp.batch[:0] }()
+
+	keys := make([]songModel.SongKeyDAO, 0, len(imp.batch))
+	for _, r := range imp.batch {
+		keys = append(keys, r.key)
+	}
+
+	existing, err := db.SongRepo().FindSongsByKeys(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	existingIDs := make(map[songModel.SongKeyDAO]int, len(existing))
+	for _, e := range existing {
+		existingIDs[songModel.SongKeyDAO{Group: e.Group, Title: e.Title}] = e.ID
+	}
+
+	songs := make([]*songModel.DAO, 0, len(imp.batch))
+	newKeys := make([]songModel.SongKeyDAO, 0, len(imp.batch))
+	for _, r := range imp.batch {
+		if id, ok := existingIDs[r.key]; ok {
+			imp.duplicate(songModel.ImportDuplicateRow{Row: r.row, Group: r.song.Group, Title: r.song.Title, ExistingID: id})
+			continue
+		}
+		songs = append(songs, r.song)
+		newKeys = append(newKeys, r.key)
+	}
+
+	if !dryRun && len(songs) > 0 {
+		if _, err := db.SongRepo().CopySongs(ctx, songs); err != nil {
+			return err
+		}
+
+		if err := imp.recordCreatedSongs(ctx, db, newKeys); err != nil {
+			return err
+		}
+	}
+	imp.report.Accepted += len(songs)
+
+	return nil
+}
+
+// recordCreatedSongs records history and song.created events of songs copied from batch,
+// COPY doesn't return ids, so songs are found again by keys inside the same transaction
+func (imp *songImport) recordCreatedSongs(ctx context.Context, db database.DataStore, keys []songModel.SongKeyDAO) error {
+	created, err := db.SongRepo().FindSongsByKeys(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	songIDs := make([]int, 0, len(created))
+	for _, song := range created {
+		songIDs = append(songIDs, song.ID)
+	}
+
+	songs, err := db.SongRepo().GetSongsByIDs(ctx, songIDs)
+	if err != nil {
+		return err
+	}
+
+	changes := make([]songChange, 0, len(songs))
+	for _, song := range songs {
+		changes = append(changes, songChange{after: song})
+	}
+
+	return recordSongChanges(ctx, db, songModel.HistoryCreated, changes...)
+}
+
+func (imp *songImport) reject(row int, reason string) {
+	imp.report.RejectedCount++
+	if len(imp.report.Rejected) < maxReportedRows {
+		imp.report.Rejected = append(imp.report.Rejected, songModel.ImportRejectedRow{Row: row, Reason: reason})
+	}
+}
+
+func (imp *songImport) duplicate(dup songModel.ImportDuplicateRow) {
+	imp.report.DuplicateCount++
+	if len(imp.report.Duplicates) < maxReportedRows {
+		imp.report.Duplicates = append(imp.report.Duplicates, dup)
+	}
+}
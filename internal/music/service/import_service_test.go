@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/internal/music/songio"
+)
+
+const importCSV = `group,title,release_date,text,link
+Muse,Hysteria,01.12.2003,It's bugging me,https://example.com/hysteria
+Muse,,2003,No title,https://example.com/untitled
+ muse , HYSTERIA ,01.12.2003,Again,https://example.com/again
+Queen,Bohemian Rhapsody,31.10.1975,Is this the real life,https://example.com/queen
+broken,row
+Adele,Hello,23.10.2015,Hello from the other side,https://example.com/hello
+`
+
+func importSongs(t *testing.T, store *fakeStore, dryRun bool) *songModel.ImportReportDTO {
+	t.Helper()
+
+	rows, err := songio.NewReader(songio.FormatCSV, strings.NewReader(importCSV))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+
+	report, err := NewSongService(store, store.blobs).ImportSongs(context.Background(), rows, dryRun)
+	if err != nil {
+		t.Fatalf("ImportSongs: %v", err)
+	}
+
+	return report
+}
+
+func TestImportSongsReport(t *testing.T) {
+	for _, dryRun := range []bool{true, false} {
+		store := newFakeStore(&songModel.DAO{ID: 7, Group: "Queen", Title: "Bohemian Rhapsody"})
+
+		report := importSongs(t, store, dryRun)
+
+		if report.DryRun != dryRun || report.Accepted != 2 || report.RejectedCount != 2 || report.DuplicateCount != 2 {
+			t.Fatalf("dry run %v: report = %+v", dryRun, report)
+		}
+
+		var rejectedRows []int
+		for _, rejected := range report.Rejected {
+			if rejected.Reason == "" {
+				t.Fatalf("row %d is rejected without reason", rejected.Row)
+			}
+			rejectedRows = append(rejectedRows, rejected.Row)
+		}
+		if !reflect.DeepEqual(rejectedRows, []int{2, 5}) {
+			t.Fatalf("dry run %v: rejected rows = %v, want [2 5]", dryRun, rejectedRows)
+		}
+
+		wantDuplicates := []songModel.ImportDuplicateRow{
+			{Row: 3, Group: " muse ", Title: " HYSTERIA ", DuplicateOfRow: 1},
+			{Row: 4, Group: "Queen", Title: "Bohemian Rhapsody", ExistingID: 7},
+		}
+		if !reflect.DeepEqual(report.Duplicates, wantDuplicates) {
+			t.Fatalf("dry run %v: duplicates = %+v, want %+v", dryRun, report.Duplicates, wantDuplicates)
+		}
+	}
+}
+
+func TestImportSongsDryRunWritesNothing(t *testing.T) {
+	store := newFakeStore()
+
+	importSongs(t, store, true)
+
+	if store.transactions != 0 || len(store.songs.copied) != 0 || len(store.webhooks.events) != 0 {
+		t.Fatalf("dry run opened %d transactions, copied %d songs and added %d events",
+			store.transactions, len(store.songs.copied), len(store.webhooks.events))
+	}
+}
+
+func TestImportSongsCopiesAccepted(t *testing.T) {
+	store := newFakeStore()
+
+	importSongs(t, store, false)
+
+	if store.transactions != 1 {
+		t.Fatalf("import opened %d transactions, want 1", store.transactions)
+	}
+
+	var titles []string
+	for _, song := range store.songs.copied {
+		titles = append(titles, song.Title)
+	}
+	if !reflect.DeepEqual(titles, []string{"Hysteria", "Bohemian Rhapsody", "Hello"}) {
+		t.Fatalf("copied songs = %v", titles)
+	}
+
+	if len(store.webhooks.events) != len(titles) {
+		t.Fatalf("import added %d events, want %d", len(store.webhooks.events), len(titles))
+	}
+	for _, event := range store.webhooks.events {
+		if event.EventType != songModel.EventSongCreated {
+			t.Fatalf("event type = %s, want %s", event.EventType, songModel.EventSongCreated)
+		}
+	}
+}
@@ -2,20 +2,32 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
 	"github.com/jumayevgadam/music-app/internal/database"
 	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/pkg/blobstore"
 	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/filter"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+	"github.com/jumayevgadam/music-app/pkg/reqvalidator"
 	"go.opentelemetry.io/otel"
 )
 
-// SongService struct is
+// SongService struct is, blobs keep uploaded audio of songs
 type SongService struct {
-	repo database.DataStore
+	repo  database.DataStore
+	blobs blobstore.BlobStore
 }
 
 // NewSongService method is
-func NewSongService(repo database.DataStore) *SongService {
-	return &SongService{repo: repo}
+func NewSongService(repo database.DataStore, blobs blobstore.BlobStore) *SongService {
+	return &SongService{repo: repo, blobs: blobs}
 }
 
 // AddSong service is
@@ -32,13 +44,424 @@ func (s *SongService) AddSong(ctx context.Context, dtoModel *songModel.DTO) (int
 	if err := s.repo.WithTransaction(ctx, func(db database.DataStore) error {
 		songID, err = db.SongRepo().AddSong(ctx, dtoModel.ToStorage())
 		if err != nil {
-			return errlst.ParseErrors(err)
+			return err
 		}
 
-		return nil
+		song, err := db.SongRepo().GetSong(ctx, songID)
+		if err != nil {
+			return err
+		}
+
+		return recordSongChanges(ctx, db, songModel.HistoryCreated, songChange{after: song})
 	}); err != nil {
-		return -1, errlst.ParseErrors(err)
+		return -1, err
 	}
 
 	return songID, nil
 }
+
+// GetSong service is
+func (s *SongService) GetSong(ctx context.Context, songID int) (*songModel.DTO, error) {
+	tracer := otel.Tracer("[GetSong][Service]")
+	ctx, span := tracer.Start(ctx, "GetSong")
+	defer span.End()
+
+	song, err := s.repo.SongRepo().GetSong(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	return song.ToServer(), nil
+}
+
+// GetSongsByIDs service returns found songs keyed by id, missing ids are left out
+func (s *SongService) GetSongsByIDs(ctx context.Context, songIDs []int) (map[int]*songModel.DTO, error) {
+	tracer := otel.Tracer("[GetSongsByIDs][Service]")
+	ctx, span := tracer.Start(ctx, "GetSongsByIDs")
+	defer span.End()
+
+	songs, err := s.repo.SongRepo().GetSongsByIDs(ctx, songIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]*songModel.DTO, len(songs))
+	for _, song := range songs {
+		result[song.ID] = song.ToServer()
+	}
+
+	return result, nil
+}
+
+// ListSongsByGroups service returns up to limit latest songs of every group,
+// result is keyed by normalized group, see NormalizeGroup
+func (s *SongService) ListSongsByGroups(ctx context.Context, groups []string, limit int) (map[string][]*songModel.DTO, error) {
+	tracer := otel.Tracer("[ListSongsByGroups][Service]")
+	ctx, span := tracer.Start(ctx, "ListSongsByGroups")
+	defer span.End()
+
+	keys := make([]string, 0, len(groups))
+	for _, group := range groups {
+		keys = append(keys, musicOps.NormalizeGroup(group))
+	}
+
+	songs, err := s.repo.SongRepo().ListSongsByGroups(ctx, keys, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]*songModel.DTO, len(keys))
+	for _, song := range songs {
+		key := musicOps.NormalizeGroup(song.Group)
+		result[key] = append(result[key], song.ToServer())
+	}
+
+	return result, nil
+}
+
+// UpdateSong service replaces editable fields of song and returns stored song,
+// when dtoModel.Version isn't 0 song must still have that version
+func (s *SongService) UpdateSong(ctx context.Context, dtoModel *songModel.DTO) (*songModel.DTO, error) {
+	tracer := otel.Tracer("[UpdateSong][Service]")
+	ctx, span := tracer.Start(ctx, "UpdateSong")
+	defer span.End()
+
+	var updated *songModel.DAO
+
+	if err := s.repo.WithTransaction(ctx, func(db database.DataStore) error {
+		before, err := db.SongRepo().GetSong(ctx, dtoModel.ID)
+		if err != nil {
+			return err
+		}
+
+		updated, err = updateSong(ctx, db, songModel.HistoryUpdated, before, dtoModel.ToStorage())
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return updated.ToServer(), nil
+}
+
+// PatchSong service changes only given fields of song, version works as in UpdateSong
+func (s *SongService) PatchSong(ctx context.Context, songID int, version int, patch *songModel.SongPatchDTO) (*songModel.DTO, error) {
+	tracer := otel.Tracer("[PatchSong][Service]")
+	ctx, span := tracer.Start(ctx, "PatchSong")
+	defer span.End()
+
+	var updated *songModel.DAO
+
+	if err := s.repo.WithTransaction(ctx, func(db database.DataStore) error {
+		before, err := db.SongRepo().GetSong(ctx, songID)
+		if err != nil {
+			return err
+		}
+
+		song := before.ToServer()
+		patch.Apply(song)
+		song.Version = version
+
+		// patched song must be as valid as created one
+		if err := reqvalidator.ValidateStruct(ctx, song); err != nil {
+			return err
+		}
+
+		updated, err = updateSong(ctx, db, songModel.HistoryUpdated, before, song.ToStorage())
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return updated.ToServer(), nil
+}
+
+// updateSong writes song over its before state inside transaction, records change
+// under action and returns stored song
+func updateSong(ctx context.Context, db database.DataStore, action string, before, song *songModel.DAO) (*songModel.DAO, error) {
+	return updateSongWithLyrics(ctx, db, action, before, song, nil)
+}
+
+// updateSongWithLyrics is updateSong which also records changes of lyric variants,
+// song version is bumped even if only variants changed
+func updateSongWithLyrics(
+	ctx context.Context,
+	db database.DataStore,
+	action string,
+	before, song *songModel.DAO,
+	lyrics map[string]songModel.FieldChange,
+) (*songModel.DAO, error) {
+	if song.Version != 0 && song.Version != before.Version {
+		return nil, errlst.PreconditionFailed(
+			fmt.Sprintf("song %d has version %d, not %d", before.ID, before.Version, song.Version),
+			errlst.ErrPreconditionFailed,
+		)
+	}
+
+	if err := db.SongRepo().UpdateSong(ctx, song); err != nil {
+		// song was read by this transaction, so it was changed concurrently
+		if song.Version != 0 && errlst.KindOf(err) == errlst.KindNotFound {
+			return nil, errlst.PreconditionFailed(fmt.Sprintf("song %d was changed concurrently", before.ID), err)
+		}
+		return nil, err
+	}
+
+	// timings of old text don't fit new one, PutSongLyrics stores new lines after this.
+	// Original lyric variant always has text of song.
+	if song.Text != before.Text {
+		if err := db.SongRepo().DeleteLyricLines(ctx, song.ID); err != nil {
+			return nil, err
+		}
+		if err := db.SongRepo().SetOriginalLyricsText(ctx, song.ID, song.Text); err != nil {
+			return nil, err
+		}
+	}
+
+	after, err := db.SongRepo().GetSong(ctx, song.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recordSongChanges(ctx, db, action, songChange{before: before, after: after, lyrics: lyrics}); err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+// DeleteSong service is, when version isn't 0 song must still have that version
+func (s *SongService) DeleteSong(ctx context.Context, songID int, version int) error {
+	tracer := otel.Tracer("[DeleteSong][Service]")
+	ctx, span := tracer.Start(ctx, "DeleteSong")
+	defer span.End()
+
+	var (
+		audio *songModel.SongAudioDAO
+		cover *songModel.SongCoverDAO
+	)
+
+	if err := s.repo.WithTransaction(ctx, func(db database.DataStore) error {
+		// history and deleted event keep last state of song
+		song, err := db.SongRepo().GetSong(ctx, songID)
+		if err != nil {
+			return err
+		}
+
+		// audio and cover rows go with song, their blobs are released after commit
+		audio, err = db.SongRepo().GetSongAudio(ctx, songID)
+		if err != nil && errlst.KindOf(err) != errlst.KindNotFound {
+			return err
+		}
+		cover, err = db.SongRepo().GetSongCover(ctx, songID)
+		if err != nil && errlst.KindOf(err) != errlst.KindNotFound {
+			return err
+		}
+
+		if version != 0 && song.Version != version {
+			return errlst.PreconditionFailed(
+				fmt.Sprintf("song %d has version %d, not %d", songID, song.Version, version),
+				errlst.ErrPreconditionFailed,
+			)
+		}
+
+		if err := db.SongRepo().DeleteSong(ctx, songID, version); err != nil {
+			if version != 0 && errlst.KindOf(err) == errlst.KindNotFound {
+				return errlst.PreconditionFailed(fmt.Sprintf("song %d was changed concurrently", songID), err)
+			}
+			return err
+		}
+
+		return recordSongChanges(ctx, db, songModel.HistoryDeleted, songChange{before: song})
+	}); err != nil {
+		return err
+	}
+
+	if audio != nil {
+		s.releaseAudioBlob(ctx, audio.BlobKey)
+	}
+	if cover != nil {
+		s.releaseCoverBlobs(ctx, cover.BlobKey)
+	}
+
+	return nil
+}
+
+// ListSongHistory service returns changes of song, newest first, also for deleted songs
+func (s *SongService) ListSongHistory(ctx context.Context, songID int) ([]*songModel.SongHistoryDTO, error) {
+	tracer := otel.Tracer("[ListSongHistory][Service]")
+	ctx, span := tracer.Start(ctx, "ListSongHistory")
+	defer span.End()
+
+	entries, err := s.repo.SongRepo().ListSongHistory(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, errlst.NotFound("song has no history", errlst.ErrNotFound)
+	}
+
+	result := make([]*songModel.SongHistoryDTO, 0, len(entries))
+	for _, entry := range entries {
+		dto, err := entry.ToServer()
+		if err != nil {
+			return nil, errlst.NewDomainError(errlst.KindInternal, "can't decode song history", err)
+		}
+		result = append(result, dto)
+	}
+
+	return result, nil
+}
+
+// RestoreSong service brings song back to snapshot of history entry, deleted
+// song is inserted again with its id. Restore itself is recorded in history.
+func (s *SongService) RestoreSong(ctx context.Context, songID int, historyID int64) (*songModel.DTO, error) {
+	tracer := otel.Tracer("[RestoreSong][Service]")
+	ctx, span := tracer.Start(ctx, "RestoreSong")
+	defer span.End()
+
+	var restored *songModel.DAO
+
+	if err := s.repo.WithTransaction(ctx, func(db database.DataStore) error {
+		entry, err := db.SongRepo().GetSongHistory(ctx, songID, historyID)
+		if err != nil {
+			return err
+		}
+
+		var snapshot songModel.DTO
+		if err := json.Unmarshal(entry.Snapshot, &snapshot); err != nil {
+			return errlst.NewDomainError(errlst.KindInternal, "can't decode song snapshot", err)
+		}
+		// restore overwrites whatever version song has now
+		target := snapshot.ToStorage()
+		target.ID, target.Version = songID, 0
+
+		before, err := db.SongRepo().GetSong(ctx, songID)
+		if err == nil {
+			restored, err = updateSong(ctx, db, songModel.HistoryRestored, before, target)
+			return err
+		}
+		if errlst.KindOf(err) != errlst.KindNotFound {
+			return err
+		}
+
+		// song is deleted, it is inserted back
+		if err := db.SongRepo().RestoreSong(ctx, target); err != nil {
+			return err
+		}
+
+		restored, err = db.SongRepo().GetSong(ctx, songID)
+		if err != nil {
+			return err
+		}
+
+		return recordSongChanges(ctx, db, songModel.HistoryRestored, songChange{after: restored})
+	}); err != nil {
+		return nil, err
+	}
+
+	return restored.ToServer(), nil
+}
+
+// ListSongs service is
+func (s *SongService) ListSongs(ctx context.Context, songFilter *filter.Filter, paginationQuery *pagination.PaginationQuery) (*songModel.SongListDTO, error) {
+	tracer := otel.Tracer("[ListSongs][Service]")
+	ctx, span := tracer.Start(ctx, "ListSongs")
+	defer span.End()
+
+	cursor := paginationQuery.GetCursor()
+	sort := paginationQuery.GetSort()
+
+	songs, err := s.repo.SongRepo().ListSongs(ctx, songFilter, paginationQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	size := paginationQuery.GetLimit()
+	hasMore := len(songs) > size
+	if hasMore {
+		songs = songs[:size]
+	}
+
+	// rows before cursor come in reversed order
+	if cursor != nil && cursor.Prev {
+		slices.Reverse(songs)
+	}
+
+	list := &songModel.SongListDTO{
+		Songs: make([]*songModel.DTO, 0, len(songs)),
+		Size:  size,
+	}
+	for _, song := range songs {
+		list.Songs = append(list.Songs, song.ToServer())
+	}
+
+	if len(songs) > 0 {
+		first, last := songs[0], songs[len(songs)-1]
+
+		switch {
+		case cursor != nil && cursor.Prev:
+			// we came from later page, so there is always next one
+			list.HasMore = true
+			list.NextCursor = songListCursor(last, sort, false)
+			if hasMore {
+				list.PrevCursor = songListCursor(first, sort, true)
+			}
+		default:
+			list.HasMore = hasMore
+			if hasMore {
+				list.NextCursor = songListCursor(last, sort, false)
+			}
+			if cursor != nil || paginationQuery.GetPage() > 1 {
+				list.PrevCursor = songListCursor(first, sort, true)
+			}
+		}
+	}
+
+	// total count is only needed by page mode
+	if !paginationQuery.IsCursorMode() {
+		list.Page = max(paginationQuery.GetPage(), 1)
+
+		list.TotalCount, err = s.repo.SongRepo().CountSongs(ctx, songFilter)
+		if err != nil {
+			return nil, err
+		}
+		list.TotalPages = pagination.GetTotalPages(list.TotalCount, size)
+	}
+
+	return list, nil
+}
+
+// songListCursor builds cursor pointing to song in given order
+func songListCursor(song *songModel.DAO, sort pagination.SortSpec, prev bool) string {
+	keys := make([]string, 0, len(sort))
+	for _, field := range sort {
+		keys = append(keys, songSortKey(song, field.Name))
+	}
+
+	return pagination.EncodeCursor(pagination.Cursor{
+		Keys: keys,
+		ID:   song.ID,
+		Prev: prev,
+		Sort: sort.String(),
+	})
+}
+
+// songSortKey returns value of sortable field of song, see music.SongSortFields
+func songSortKey(song *songModel.DAO, field string) string {
+	switch field {
+	case "id":
+		return strconv.Itoa(song.ID)
+	case "group":
+		return song.Group
+	case "title":
+		return song.Title
+	case "release_date":
+		return song.ReleaseDate.Format(time.DateOnly)
+	case "created_at":
+		return song.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		return song.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		return ""
+	}
+}
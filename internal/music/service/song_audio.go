@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"path"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/jumayevgadam/music-app/internal/database"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/pkg/audiotag"
+	"github.com/jumayevgadam/music-app/pkg/blobstore"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/releasedate"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+)
+
+// UploadSongAudio service stores audio file of song replacing previous one. File is
+// sniffed, checksummed and its tags are read, with Prefill tags replace group, title
+// and release date of song. Audio itself isn't versioned, only prefilled song is.
+func (s *SongService) UploadSongAudio(ctx context.Context, songID int, upload *musicOps.AudioUpload) (*songModel.SongAudioUploadDTO, error) {
+	tracer := otel.Tracer("[UploadSongAudio][Service]")
+	ctx, span := tracer.Start(ctx, "UploadSongAudio")
+	defer span.End()
+
+	// missing song is 404 before anything is written to blob store
+	if _, err := s.repo.SongRepo().GetSong(ctx, songID); err != nil {
+		return nil, err
+	}
+
+	audio, tags, err := inspectAudio(upload)
+	if err != nil {
+		return nil, err
+	}
+	audio.SongID = songID
+
+	if _, err := upload.File.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if _, err := s.blobs.Put(ctx, audio.BlobKey, upload.File); err != nil {
+		return nil, err
+	}
+
+	var (
+		saved     *songModel.SongAudioDAO
+		previous  *songModel.SongAudioDAO
+		song      *songModel.DAO
+		prefilled = []string{}
+	)
+
+	if err := s.repo.WithTransaction(ctx, func(db database.DataStore) error {
+		before, err := db.SongRepo().GetSong(ctx, songID)
+		if err != nil {
+			return err
+		}
+		song = before
+
+		previous, err = db.SongRepo().GetSongAudio(ctx, songID)
+		if err != nil && errlst.KindOf(err) != errlst.KindNotFound {
+			return err
+		}
+
+		saved, err = db.SongRepo().SaveSongAudio(ctx, audio)
+		if err != nil {
+			return err
+		}
+
+		if !upload.Prefill || tags == nil {
+			return nil
+		}
+
+		prefill := *before
+		prefill.Version = upload.Version
+		prefilled = prefillSong(&prefill, tags)
+		if len(prefilled) == 0 {
+			return nil
+		}
+
+		song, err = updateSong(ctx, db, songModel.HistoryUpdated, before, &prefill)
+		return err
+	}); err != nil {
+		// blob may be new and referenced by nothing
+		s.releaseAudioBlob(ctx, audio.BlobKey)
+		return nil, err
+	}
+
+	if previous != nil && previous.BlobKey != saved.BlobKey {
+		s.releaseAudioBlob(ctx, previous.BlobKey)
+	}
+
+	return &songModel.SongAudioUploadDTO{Audio: saved.ToServer(), Song: song.ToServer(), Prefilled: prefilled}, nil
+}
+
+// GetSongAudio service is
+func (s *SongService) GetSongAudio(ctx context.Context, songID int) (*songModel.SongAudioDTO, error) {
+	tracer := otel.Tracer("[GetSongAudio][Service]")
+	ctx, span := tracer.Start(ctx, "GetSongAudio")
+	defer span.End()
+
+	audio, err := s.songAudio(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	return audio.ToServer(), nil
+}
+
+// OpenSongAudio service returns audio of song with its file opened for reading,
+// caller closes file
+func (s *SongService) OpenSongAudio(ctx context.Context, songID int) (*songModel.SongAudioDTO, blobstore.Object, error) {
+	tracer := otel.Tracer("[OpenSongAudio][Service]")
+	ctx, span := tracer.Start(ctx, "OpenSongAudio")
+	defer span.End()
+
+	audio, err := s.songAudio(ctx, songID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	object, err := s.blobs.Open(ctx, audio.BlobKey)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			// row outlived its blob, e.g. blob store was restored from older backup
+			logrus.Errorf("[SongService][OpenSongAudio]: song %d: blob %s is missing", songID, audio.BlobKey)
+			return nil, nil, errlst.NotFound(musicOps.ErrNoAudio.Error(), err)
+		}
+		return nil, nil, err
+	}
+
+	return audio.ToServer(), object, nil
+}
+
+// DeleteSongAudio service deletes audio of song and its blob when no other song shares it
+func (s *SongService) DeleteSongAudio(ctx context.Context, songID int) error {
+	tracer := otel.Tracer("[DeleteSongAudio][Service]")
+	ctx, span := tracer.Start(ctx, "DeleteSongAudio")
+	defer span.End()
+
+	deleted, err := s.repo.SongRepo().DeleteSongAudio(ctx, songID)
+	if err != nil {
+		if errlst.KindOf(err) == errlst.KindNotFound {
+			return errlst.NotFound(musicOps.ErrNoAudio.Error(), musicOps.ErrNoAudio)
+		}
+		return err
+	}
+
+	s.releaseAudioBlob(ctx, deleted.BlobKey)
+
+	return nil
+}
+
+// songAudio returns audio of song, missing song and song without audio are both 404
+func (s *SongService) songAudio(ctx context.Context, songID int) (*songModel.SongAudioDAO, error) {
+	audio, err := s.repo.SongRepo().GetSongAudio(ctx, songID)
+	if err == nil {
+		return audio, nil
+	}
+	if errlst.KindOf(err) != errlst.KindNotFound {
+		return nil, err
+	}
+
+	if _, err := s.repo.SongRepo().GetSong(ctx, songID); err != nil {
+		return nil, err
+	}
+
+	return nil, errlst.NotFound(musicOps.ErrNoAudio.Error(), musicOps.ErrNoAudio)
+}
+
+// releaseAudioBlob deletes blob no song refers to, failures only leave unused blob behind
+func (s *SongService) releaseAudioBlob(ctx context.Context, blobKey string) {
+	references, err := s.repo.SongRepo().CountAudioBlobReferences(ctx, blobKey)
+	if err != nil {
+		logrus.Errorf("[SongService][releaseAudioBlob]: %s: %v", blobKey, err)
+		return
+	}
+	if references > 0 {
+		return
+	}
+
+	if err := s.blobs.Delete(ctx, blobKey); err != nil {
+		logrus.Errorf("[SongService][releaseAudioBlob]: %s: %v", blobKey, err)
+	}
+}
+
+// inspectAudio sniffs type, checksums and reads tags of upload. Blob key is
+// content address, so same file uploaded twice is stored once.
+func inspectAudio(upload *musicOps.AudioUpload) (*songModel.SongAudioDAO, *audiotag.Tags, error) {
+	if _, err := upload.File.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+
+	mime, err := mimetype.DetectReader(upload.File)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isAudioType(mime) {
+		return nil, nil, errlst.Validation(
+			musicOps.ErrUnsupportedAudio.Error()+" "+mime.String(), musicOps.ErrUnsupportedAudio,
+		)
+	}
+
+	if _, err := upload.File.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, upload.File)
+	if err != nil {
+		return nil, nil, err
+	}
+	if size == 0 {
+		return nil, nil, errlst.Validation("audio file is empty", errlst.ErrBadRequest)
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	// files without tags or with broken ones are still accepted
+	tags, err := audiotag.Read(upload.File)
+	switch {
+	case errors.Is(err, audiotag.ErrNoTags):
+		tags = nil
+	case errors.Is(err, audiotag.ErrMalformed):
+		logrus.Warnf("[SongService][inspectAudio]: %s: %v", upload.Filename, err)
+		tags = nil
+	case err != nil:
+		return nil, nil, err
+	}
+
+	audio := &songModel.SongAudioDAO{
+		BlobKey:     path.Join("audio", sum[:2], sum+mime.Extension()),
+		ContentType: mime.String(),
+		Size:        size,
+		SHA256:      sum,
+	}
+	if upload.Filename != "" {
+		audio.Filename = path.Base(upload.Filename)
+	}
+	audio.SetTags(tags)
+
+	return audio, tags, nil
+}
+
+// isAudioType is
+func isAudioType(mime *mimetype.MIME) bool {
+	for _, audioType := range musicOps.AudioTypes {
+		if mime.Is(audioType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prefillSong sets fields of song found in tags and returns their names,
+// date which can't be parsed is skipped
+func prefillSong(song *songModel.DAO, tags *audiotag.Tags) []string {
+	prefilled := []string{}
+
+	if tags.Artist != "" && tags.Artist != song.Group {
+		song.Group = tags.Artist
+		prefilled = append(prefilled, "group")
+	}
+
+	if tags.Title != "" && tags.Title != song.Title {
+		song.Title = tags.Title
+		prefilled = append(prefilled, "title")
+	}
+
+	if date, err := releasedate.Parse(tags.Date); err == nil && !date.IsZero() {
+		if !date.Time.Equal(song.ReleaseDate) || string(date.Precision) != song.ReleaseDatePrecision {
+			song.ReleaseDate, song.ReleaseDatePrecision = date.Time, string(date.Precision)
+			prefilled = append(prefilled, "release_date")
+		}
+	}
+
+	return prefilled
+}
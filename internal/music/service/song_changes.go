@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jumayevgadam/music-app/internal/database"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/pkg/auth"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+)
+
+// songChange is state of song before and after change, before is nil
+// for created songs and after is nil for deleted ones. Merges set mergedInto
+// on removed duplicates and mergedFrom on canonical song. Changed lyric
+// variants are recorded in diff as lyrics.<language>.
+type songChange struct {
+	before     *songModel.DAO
+	after      *songModel.DAO
+	mergedInto *int
+	mergedFrom []int
+	lyrics     map[string]songModel.FieldChange
+}
+
+// mergedSongPayload is payload of song.merged event
+type mergedSongPayload struct {
+	*songModel.DTO
+	MergedFrom []int `json:"mergedFrom"`
+}
+
+// recordSongChanges writes history entries and outbox events of changes, db must be
+// transactional DataStore of the change, so both exist only if the change is committed
+func recordSongChanges(ctx context.Context, db database.DataStore, action string, changes ...songChange) error {
+	actor := auth.ActorFromContext(ctx)
+	if actor == "" {
+		actor = songModel.AnonymousActor
+	}
+
+	entries := make([]*songModel.SongHistoryDAO, 0, len(changes))
+	events := make([]*songModel.OutboxEventDAO, 0, len(changes))
+
+	for _, change := range changes {
+		var before, after *songModel.DTO
+		if change.before != nil {
+			before = change.before.ToServer()
+		}
+		if change.after != nil {
+			after = change.after.ToServer()
+		}
+
+		// deleted song keeps its last state as snapshot
+		current, eventType := after, songModel.EventSongUpdated
+		switch {
+		case after == nil:
+			current, eventType = before, songModel.EventSongDeleted
+		case before == nil:
+			eventType = songModel.EventSongCreated
+		}
+
+		snapshot, err := json.Marshal(current)
+		if err != nil {
+			return errlst.NewDomainError(errlst.KindInternal, "can't encode song snapshot", err)
+		}
+
+		// event payload is song in the shape API returns it
+		payload := snapshot
+		if len(change.mergedFrom) > 0 {
+			eventType = songModel.EventSongMerged
+			if payload, err = json.Marshal(mergedSongPayload{DTO: current, MergedFrom: change.mergedFrom}); err != nil {
+				return errlst.NewDomainError(errlst.KindInternal, "can't encode song event", err)
+			}
+		}
+
+		changes := songDiff(before, after)
+		for language, change := range change.lyrics {
+			changes["lyrics."+language] = change
+		}
+
+		diff, err := json.Marshal(changes)
+		if err != nil {
+			return errlst.NewDomainError(errlst.KindInternal, "can't encode song diff", err)
+		}
+
+		entries = append(entries, &songModel.SongHistoryDAO{
+			SongID:     current.ID,
+			Action:     action,
+			Actor:      actor,
+			Diff:       diff,
+			Snapshot:   snapshot,
+			MergedInto: change.mergedInto,
+			MergedFrom: change.mergedFrom,
+		})
+
+		events = append(events, &songModel.OutboxEventDAO{
+			EventType:   eventType,
+			AggregateID: current.ID,
+			Payload:     payload,
+		})
+	}
+
+	if err := db.SongRepo().AddSongHistory(ctx, entries); err != nil {
+		return err
+	}
+
+	return db.WebhookRepo().AddOutboxEvents(ctx, events)
+}
+
+// songHistoryFields are fields compared by songDiff
+var songHistoryFields = []struct {
+	name  string
+	value func(song *songModel.DTO) interface{}
+}{
+	{"group", func(song *songModel.DTO) interface{} { return song.Group }},
+	{"title", func(song *songModel.DTO) interface{} { return song.Title }},
+	{"release_date", func(song *songModel.DTO) interface{} { return song.ReleaseDate.String() }},
+	{"text", func(song *songModel.DTO) interface{} { return song.Text }},
+	{"link", func(song *songModel.DTO) interface{} { return song.Link }},
+}
+
+// songDiff returns changed editable fields keyed by JSON name, missing side is null
+func songDiff(before, after *songModel.DTO) map[string]songModel.FieldChange {
+	diff := make(map[string]songModel.FieldChange)
+
+	for _, field := range songHistoryFields {
+		var change songModel.FieldChange
+		if before != nil {
+			change.Before = field.value(before)
+		}
+		if after != nil {
+			change.After = field.value(after)
+		}
+
+		if before != nil && after != nil && change.Before == change.After {
+			continue
+		}
+		diff[field.name] = change
+	}
+
+	return diff
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/pkg/auth"
+	"github.com/jumayevgadam/music-app/pkg/releasedate"
+)
+
+func testSong(id int, title string) *songModel.DAO {
+	return &songModel.DAO{
+		ID:                   id,
+		Group:                "Muse",
+		Title:                title,
+		ReleaseDate:          time.Date(2003, time.December, 1, 0, 0, 0, 0, time.UTC),
+		ReleaseDatePrecision: string(releasedate.PrecisionDay),
+		Text:                 "It's bugging me",
+		Link:                 "https://example.com/hysteria",
+		Version:              1,
+	}
+}
+
+// historyDiff decodes diff of history entry
+func historyDiff(t *testing.T, entry *songModel.SongHistoryDAO) map[string]songModel.FieldChange {
+	t.Helper()
+
+	var diff map[string]songModel.FieldChange
+	if err := json.Unmarshal(entry.Diff, &diff); err != nil {
+		t.Fatalf("decode diff: %v", err)
+	}
+
+	return diff
+}
+
+func TestSongDiff(t *testing.T) {
+	before := testSong(1, "Hysteria").ToServer()
+	after := testSong(1, "Hysteria (live)").ToServer()
+	after.Link = "https://example.com/live"
+
+	want := map[string]songModel.FieldChange{
+		"title": {Before: "Hysteria", After: "Hysteria (live)"},
+		"link":  {Before: "https://example.com/hysteria", After: "https://example.com/live"},
+	}
+	if got := songDiff(before, after); !reflect.DeepEqual(got, want) {
+		t.Fatalf("songDiff = %v, want %v", got, want)
+	}
+
+	if got := songDiff(before, before); len(got) != 0 {
+		t.Fatalf("songDiff of same song = %v, want empty", got)
+	}
+
+	// created and deleted songs have every field with null side
+	for name, diff := range map[string]map[string]songModel.FieldChange{
+		"created": songDiff(nil, after),
+		"deleted": songDiff(before, nil),
+	} {
+		if len(diff) != len(songHistoryFields) {
+			t.Fatalf("%s diff = %v, want all fields", name, diff)
+		}
+	}
+	if change := songDiff(nil, after)["title"]; change.Before != nil || change.After != "Hysteria (live)" {
+		t.Fatalf("created title change = %+v", change)
+	}
+	if change := songDiff(before, nil)["title"]; change.Before != "Hysteria" || change.After != nil {
+		t.Fatalf("deleted title change = %+v", change)
+	}
+}
+
+func TestUpdateSongRecordsHistory(t *testing.T) {
+	store := newFakeStore(testSong(1, "Hysteria"))
+	ctx := auth.WithActor(context.Background(), "editor")
+
+	update := testSong(1, "Hysteria (live)").ToServer()
+	if _, err := NewSongService(store, store.blobs).UpdateSong(ctx, update); err != nil {
+		t.Fatalf("UpdateSong: %v", err)
+	}
+
+	entries := store.songs.songHistory(1)
+	if len(entries) != 1 || entries[0].Action != songModel.HistoryUpdated || entries[0].Actor != "editor" {
+		t.Fatalf("history = %+v, want one update of editor", entries)
+	}
+
+	want := map[string]songModel.FieldChange{"title": {Before: "Hysteria", After: "Hysteria (live)"}}
+	if diff := historyDiff(t, entries[0]); !reflect.DeepEqual(diff, want) {
+		t.Fatalf("diff = %v, want %v", diff, want)
+	}
+
+	if len(store.webhooks.events) != 1 || store.webhooks.events[0].EventType != songModel.EventSongUpdated {
+		t.Fatalf("events = %+v, want one song.updated", store.webhooks.events)
+	}
+}
+
+func TestDeleteSongRecordsAnonymousActor(t *testing.T) {
+	store := newFakeStore(testSong(1, "Hysteria"))
+
+	if err := NewSongService(store, store.blobs).DeleteSong(context.Background(), 1, 0); err != nil {
+		t.Fatalf("DeleteSong: %v", err)
+	}
+
+	entries := store.songs.songHistory(1)
+	if len(entries) != 1 || entries[0].Action != songModel.HistoryDeleted || entries[0].Actor != songModel.AnonymousActor {
+		t.Fatalf("history = %+v, want one anonymous delete", entries)
+	}
+
+	// deleted song keeps its last state as snapshot
+	var snapshot songModel.DTO
+	if err := json.Unmarshal(entries[0].Snapshot, &snapshot); err != nil || snapshot.Title != "Hysteria" {
+		t.Fatalf("snapshot = %+v, %v", snapshot, err)
+	}
+}
+
+func TestRestoreSong(t *testing.T) {
+	store := newFakeStore(testSong(1, "Hysteria"))
+	service := NewSongService(store, store.blobs)
+	ctx := context.Background()
+
+	for _, title := range []string{"Hysteria (live)", "Hysteria (demo)"} {
+		update := testSong(1, title).ToServer()
+		update.Version = 0
+		if _, err := service.UpdateSong(ctx, update); err != nil {
+			t.Fatalf("UpdateSong: %v", err)
+		}
+	}
+
+	// first update is brought back
+	first := store.songs.songHistory(1)[0]
+	restored, err := service.RestoreSong(ctx, 1, first.ID)
+	if err != nil {
+		t.Fatalf("RestoreSong: %v", err)
+	}
+	// restore is a change too, so version goes on
+	if restored.Title != "Hysteria (live)" || restored.Version != 4 || store.songs.songs[1].Title != "Hysteria (live)" {
+		t.Fatalf("restored = %+v, stored = %+v", restored, store.songs.songs[1])
+	}
+
+	entries := store.songs.songHistory(1)
+	last := entries[len(entries)-1]
+	want := map[string]songModel.FieldChange{"title": {Before: "Hysteria (demo)", After: "Hysteria (live)"}}
+	if last.Action != songModel.HistoryRestored || !reflect.DeepEqual(historyDiff(t, last), want) {
+		t.Fatalf("restore entry = %+v, diff %v", last, historyDiff(t, last))
+	}
+}
+
+func TestRestoreDeletedSong(t *testing.T) {
+	store := newFakeStore(testSong(1, "Hysteria"))
+	service := NewSongService(store, store.blobs)
+	ctx := context.Background()
+
+	if err := service.DeleteSong(ctx, 1, 0); err != nil {
+		t.Fatalf("DeleteSong: %v", err)
+	}
+
+	deleted := store.songs.songHistory(1)[0]
+	restored, err := service.RestoreSong(ctx, 1, deleted.ID)
+	if err != nil {
+		t.Fatalf("RestoreSong: %v", err)
+	}
+	if restored.ID != 1 || restored.Title != "Hysteria" || restored.Version != 2 {
+		t.Fatalf("restored = %+v", restored)
+	}
+
+	// song is inserted again, so restore entry has no before side
+	entries := store.songs.songHistory(1)
+	if change := historyDiff(t, entries[len(entries)-1])["title"]; change.Before != nil || change.After != "Hysteria" {
+		t.Fatalf("restore title change = %+v", change)
+	}
+}
+
+func TestRestoreSongUnknownEntry(t *testing.T) {
+	store := newFakeStore(testSong(1, "Hysteria"))
+
+	if _, err := NewSongService(store, store.blobs).RestoreSong(context.Background(), 1, 42); err == nil {
+		t.Fatal("RestoreSong of unknown history entry succeeded")
+	}
+}
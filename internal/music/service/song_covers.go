@@ -0,0 +1,231 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/jumayevgadam/music-app/internal/database"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/pkg/blobstore"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/thumbnail"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+)
+
+// UploadSongCover service stores cover image of song with its thumbnails, replacing
+// previous cover. Image is sniffed, must be JPEG, PNG or WebP and is decoded to make
+// thumbnails, so broken images are refused. Cover isn't versioned with song.
+func (s *SongService) UploadSongCover(ctx context.Context, songID int, file io.ReadSeeker) (*songModel.SongCoverDTO, error) {
+	tracer := otel.Tracer("[UploadSongCover][Service]")
+	ctx, span := tracer.Start(ctx, "UploadSongCover")
+	defer span.End()
+
+	if _, err := s.repo.SongRepo().GetSong(ctx, songID); err != nil {
+		return nil, err
+	}
+
+	cover, thumbnails, err := inspectCover(file)
+	if err != nil {
+		return nil, err
+	}
+	cover.SongID = songID
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if _, err := s.blobs.Put(ctx, cover.BlobKey, file); err != nil {
+		return nil, err
+	}
+	for size, thumb := range thumbnails {
+		if _, err := s.blobs.Put(ctx, coverThumbnailKey(cover.BlobKey, size), bytes.NewReader(thumb)); err != nil {
+			s.releaseCoverBlobs(ctx, cover.BlobKey)
+			return nil, err
+		}
+	}
+
+	var saved, previous *songModel.SongCoverDAO
+
+	if err := s.repo.WithTransaction(ctx, func(db database.DataStore) error {
+		previous, err = db.SongRepo().GetSongCover(ctx, songID)
+		if err != nil && errlst.KindOf(err) != errlst.KindNotFound {
+			return err
+		}
+
+		saved, err = db.SongRepo().SaveSongCover(ctx, cover)
+		return err
+	}); err != nil {
+		s.releaseCoverBlobs(ctx, cover.BlobKey)
+		return nil, err
+	}
+
+	if previous != nil && previous.BlobKey != saved.BlobKey {
+		s.releaseCoverBlobs(ctx, previous.BlobKey)
+	}
+
+	return saved.ToServer(musicOps.CoverSizes), nil
+}
+
+// OpenSongCover service returns cover of song with its image opened for reading,
+// size 0 is original image, other sizes are JPEG thumbnails. Caller closes image.
+func (s *SongService) OpenSongCover(ctx context.Context, songID int, size int) (*songModel.SongCoverDTO, blobstore.Object, error) {
+	tracer := otel.Tracer("[OpenSongCover][Service]")
+	ctx, span := tracer.Start(ctx, "OpenSongCover")
+	defer span.End()
+
+	if size != 0 && !slices.Contains(musicOps.CoverSizes, size) {
+		return nil, nil, errlst.Validation(
+			fmt.Sprintf("size must be one of %v", musicOps.CoverSizes), errlst.ErrBadQueryParams,
+		)
+	}
+
+	cover, err := s.repo.SongRepo().GetSongCover(ctx, songID)
+	if err != nil {
+		if errlst.KindOf(err) != errlst.KindNotFound {
+			return nil, nil, err
+		}
+		if _, err := s.repo.SongRepo().GetSong(ctx, songID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, errlst.NotFound(musicOps.ErrNoCover.Error(), musicOps.ErrNoCover)
+	}
+
+	key := cover.BlobKey
+	if size != 0 {
+		key = coverThumbnailKey(cover.BlobKey, size)
+	}
+
+	object, err := s.blobs.Open(ctx, key)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			logrus.Errorf("[SongService][OpenSongCover]: song %d: blob %s is missing", songID, key)
+			return nil, nil, errlst.NotFound(musicOps.ErrNoCover.Error(), err)
+		}
+		return nil, nil, err
+	}
+
+	return cover.ToServer(musicOps.CoverSizes), object, nil
+}
+
+// DeleteSongCover service deletes cover of song and its images when no other song shares them
+func (s *SongService) DeleteSongCover(ctx context.Context, songID int) error {
+	tracer := otel.Tracer("[DeleteSongCover][Service]")
+	ctx, span := tracer.Start(ctx, "DeleteSongCover")
+	defer span.End()
+
+	deleted, err := s.repo.SongRepo().DeleteSongCover(ctx, songID)
+	if err != nil {
+		if errlst.KindOf(err) == errlst.KindNotFound {
+			return errlst.NotFound(musicOps.ErrNoCover.Error(), musicOps.ErrNoCover)
+		}
+		return err
+	}
+
+	s.releaseCoverBlobs(ctx, deleted.BlobKey)
+
+	return nil
+}
+
+// releaseCoverBlobs deletes cover and its thumbnails no song refers to,
+// failures only leave unused blobs behind
+func (s *SongService) releaseCoverBlobs(ctx context.Context, blobKey string) {
+	references, err := s.repo.SongRepo().CountCoverBlobReferences(ctx, blobKey)
+	if err != nil {
+		logrus.Errorf("[SongService][releaseCoverBlobs]: %s: %v", blobKey, err)
+		return
+	}
+	if references > 0 {
+		return
+	}
+
+	keys := []string{blobKey}
+	for _, size := range musicOps.CoverSizes {
+		keys = append(keys, coverThumbnailKey(blobKey, size))
+	}
+
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			logrus.Errorf("[SongService][releaseCoverBlobs]: %s: %v", key, err)
+		}
+	}
+}
+
+// inspectCover sniffs type, checksums and decodes image, returning JPEG thumbnails
+// of CoverSizes. Blob key is content address as for audio.
+func inspectCover(file io.ReadSeeker) (*songModel.SongCoverDAO, map[int][]byte, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+
+	mime, err := mimetype.DetectReader(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !slices.ContainsFunc(musicOps.CoverTypes, mime.Is) {
+		return nil, nil, errlst.Validation(
+			"cover must be "+strings.Join(musicOps.CoverTypes, ", ")+", got "+mime.String(),
+			errlst.ErrNotAllowedImageHeader,
+		)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return nil, nil, err
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+
+	img, _, err := thumbnail.Decode(file, musicOps.MaxCoverPixels)
+	switch {
+	case errors.Is(err, thumbnail.ErrUnsupportedImage):
+		return nil, nil, errlst.Validation(err.Error(), errlst.ErrNotAllowedImageHeader)
+	case errors.Is(err, thumbnail.ErrTooManyPixels):
+		return nil, nil, errlst.Validation(err.Error(), err)
+	case err != nil:
+		return nil, nil, err
+	}
+
+	thumbnails := make(map[int][]byte, len(musicOps.CoverSizes))
+	for _, side := range musicOps.CoverSizes {
+		var buf bytes.Buffer
+		if err := thumbnail.EncodeJPEG(&buf, thumbnail.Square(img, side)); err != nil {
+			return nil, nil, err
+		}
+		thumbnails[side] = buf.Bytes()
+	}
+
+	cover := &songModel.SongCoverDAO{
+		BlobKey:     path.Join("covers", sum[:2], sum+mime.Extension()),
+		ContentType: mime.String(),
+		Size:        size,
+		SHA256:      sum,
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}
+
+	return cover, thumbnails, nil
+}
+
+// coverThumbnailKey is key of thumbnail kept next to cover, e.g. covers/ab/ab12..._300.jpg
+func coverThumbnailKey(blobKey string, size int) string {
+	return strings.TrimSuffix(blobKey, path.Ext(blobKey)) + "_" + strconv.Itoa(size) + ".jpg"
+}
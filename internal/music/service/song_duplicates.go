@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jumayevgadam/music-app/internal/database"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+	"go.opentelemetry.io/otel"
+)
+
+// ListSongDuplicates service returns page of likely duplicates with their songs
+func (s *SongService) ListSongDuplicates(
+	ctx context.Context,
+	duplicateFilter songModel.DuplicateFilterDAO,
+	paginationQuery *pagination.PaginationQuery,
+) (*songModel.SongDuplicateListDTO, error) {
+	tracer := otel.Tracer("[ListSongDuplicates][Service]")
+	ctx, span := tracer.Start(ctx, "ListSongDuplicates")
+	defer span.End()
+
+	duplicates, err := s.repo.SongRepo().ListSongDuplicates(ctx, duplicateFilter, paginationQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	var songIDs []int
+	for _, duplicate := range duplicates {
+		songIDs = append(songIDs, duplicate.SongIDs...)
+	}
+
+	songs, err := s.GetSongsByIDs(ctx, songIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &songModel.SongDuplicateListDTO{
+		Duplicates: make([]*songModel.SongDuplicateDTO, 0, len(duplicates)),
+		Page:       max(paginationQuery.GetPage(), 1),
+		Size:       paginationQuery.GetLimit(),
+	}
+	for _, duplicate := range duplicates {
+		dto := &songModel.SongDuplicateDTO{
+			Reason:     duplicate.Reason,
+			Similarity: duplicate.Similarity,
+			Songs:      make([]*songModel.DTO, 0, len(duplicate.SongIDs)),
+		}
+		for _, songID := range duplicate.SongIDs {
+			// song may be deleted between both reads
+			if song, ok := songs[songID]; ok {
+				dto.Songs = append(dto.Songs, song)
+			}
+		}
+		list.Duplicates = append(list.Duplicates, dto)
+		list.TotalCount = duplicate.TotalCount
+	}
+
+	list.TotalPages = pagination.GetTotalPages(list.TotalCount, list.Size)
+	list.HasMore = list.Page < list.TotalPages
+
+	return list, nil
+}
+
+// MergeSongs service removes duplicates and keeps canonical song, both sides
+// get merged entry in history, so duplicates can be restored later. Restored
+// duplicate doesn't get back plays, variants or media moved to canonical song.
+func (s *SongService) MergeSongs(ctx context.Context, canonicalID int, duplicateIDs []int) (*songModel.DTO, error) {
+	tracer := otel.Tracer("[MergeSongs][Service]")
+	ctx, span := tracer.Start(ctx, "MergeSongs")
+	defer span.End()
+
+	duplicateIDs = slices.Compact(slices.Sorted(slices.Values(duplicateIDs)))
+	if slices.Contains(duplicateIDs, canonicalID) {
+		return nil, errlst.Validation("song can't be merged into itself", errlst.ErrBadRequest)
+	}
+
+	var (
+		merged *songModel.DAO
+		audio  []*songModel.SongAudioDAO
+		covers []*songModel.SongCoverDAO
+	)
+
+	if err := s.repo.WithTransaction(ctx, func(db database.DataStore) error {
+		canonical, err := db.SongRepo().GetSong(ctx, canonicalID)
+		if err != nil {
+			return err
+		}
+
+		duplicates, err := db.SongRepo().GetSongsByIDs(ctx, duplicateIDs)
+		if err != nil {
+			return err
+		}
+
+		if len(duplicates) != len(duplicateIDs) {
+			found := make([]int, 0, len(duplicates))
+			for _, duplicate := range duplicates {
+				found = append(found, duplicate.ID)
+			}
+
+			missing := slices.DeleteFunc(slices.Clone(duplicateIDs), func(id int) bool { return slices.Contains(found, id) })
+			return errlst.NotFound(fmt.Sprintf("songs %v don't exist", missing), errlst.ErrNotFound)
+		}
+
+		// media of duplicates which isn't moved goes with them, blobs are released after commit
+		for _, duplicate := range duplicates {
+			duplicateAudio, err := db.SongRepo().GetSongAudio(ctx, duplicate.ID)
+			switch {
+			case err == nil:
+				audio = append(audio, duplicateAudio)
+			case errlst.KindOf(err) != errlst.KindNotFound:
+				return err
+			}
+
+			duplicateCover, err := db.SongRepo().GetSongCover(ctx, duplicate.ID)
+			switch {
+			case err == nil:
+				covers = append(covers, duplicateCover)
+			case errlst.KindOf(err) != errlst.KindNotFound:
+				return err
+			}
+		}
+
+		// plays of duplicates count for canonical song, lyric variants become its
+		// translations in languages it lacks and audio or cover is taken when it has
+		// none. Synced lyric lines time text of duplicate, so they are deleted with
+		// it. No other table references songs yet, when playlists or albums are
+		// added their references to duplicates must be moved here
+		if err := db.SongRepo().MoveSongPlays(ctx, duplicateIDs, canonicalID); err != nil {
+			return err
+		}
+		variants, err := db.SongRepo().MoveLyricVariants(ctx, duplicateIDs, canonicalID)
+		if err != nil {
+			return err
+		}
+		if err := db.SongRepo().MoveSongAudio(ctx, duplicateIDs, canonicalID); err != nil {
+			return err
+		}
+		if err := db.SongRepo().MoveSongCover(ctx, duplicateIDs, canonicalID); err != nil {
+			return err
+		}
+
+		changes := make([]songChange, 0, len(duplicates)+1)
+		for _, duplicate := range duplicates {
+			if err := db.SongRepo().DeleteSong(ctx, duplicate.ID, duplicate.Version); err != nil {
+				if errlst.KindOf(err) == errlst.KindNotFound {
+					return errlst.PreconditionFailed(fmt.Sprintf("song %d was changed concurrently", duplicate.ID), err)
+				}
+				return err
+			}
+			changes = append(changes, songChange{before: duplicate, mergedInto: &canonicalID})
+		}
+
+		// new translations change lyrics of canonical song, so its version is bumped
+		merged = canonical
+		var lyrics map[string]songModel.FieldChange
+		if len(variants) > 0 {
+			if err := db.SongRepo().UpdateSong(ctx, canonical); err != nil {
+				if errlst.KindOf(err) == errlst.KindNotFound {
+					return errlst.PreconditionFailed(fmt.Sprintf("song %d was changed concurrently", canonicalID), err)
+				}
+				return err
+			}
+			if merged, err = db.SongRepo().GetSong(ctx, canonicalID); err != nil {
+				return err
+			}
+
+			lyrics = make(map[string]songModel.FieldChange, len(variants))
+			for _, variant := range variants {
+				lyrics[variant.Language] = songModel.FieldChange{After: variant.Text}
+			}
+		}
+		changes = append(changes, songChange{before: canonical, after: merged, mergedFrom: duplicateIDs, lyrics: lyrics})
+
+		return recordSongChanges(ctx, db, songModel.HistoryMerged, changes...)
+	}); err != nil {
+		return nil, err
+	}
+
+	// moved blobs are still referenced, so only blobs of deleted rows are removed
+	for _, duplicateAudio := range audio {
+		s.releaseAudioBlob(ctx, duplicateAudio.BlobKey)
+	}
+	for _, duplicateCover := range covers {
+		s.releaseCoverBlobs(ctx, duplicateCover.BlobKey)
+	}
+
+	return merged.ToServer(), nil
+}
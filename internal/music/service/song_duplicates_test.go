@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"testing"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+)
+
+// newMergeStore returns store with canonical song 1 and its duplicates 2 and 3
+func newMergeStore() *fakeStore {
+	store := newFakeStore(testSong(1, "Hysteria"), testSong(2, "hysteria"), testSong(3, "Hysteria "))
+	store.songs.variants = map[int][]*songModel.LyricVariantDAO{
+		1: {{SongID: 1, Language: "en", Text: "It's bugging me", Original: true}},
+		2: {
+			{SongID: 2, Language: "en", Text: "It's bugging me", Original: true},
+			{SongID: 2, Language: "de", Text: "Es nervt mich"},
+		},
+		3: {
+			{SongID: 3, Language: "de", Text: "Es stört mich"},
+			{SongID: 3, Language: "fr", Text: "Ça me dérange"},
+		},
+	}
+	store.songs.audio = map[int]*songModel.SongAudioDAO{
+		2: {SongID: 2, BlobKey: "audio/aa/aa11"},
+		3: {SongID: 3, BlobKey: "audio/bb/bb22"},
+	}
+	store.songs.plays = map[int]int{1: 5, 2: 3, 3: 2}
+	store.songs.covers = map[int]*songModel.SongCoverDAO{
+		3: {SongID: 3, BlobKey: "covers/dd/dd44.png"},
+	}
+
+	return store
+}
+
+func TestMergeSongs(t *testing.T) {
+	store := newMergeStore()
+
+	merged, err := NewSongService(store, store.blobs).MergeSongs(context.Background(), 1, []int{3, 2, 3})
+	if err != nil {
+		t.Fatalf("MergeSongs: %v", err)
+	}
+	// new translations bump version of canonical song
+	if merged.ID != 1 || merged.Title != "Hysteria" || merged.Version != 2 {
+		t.Fatalf("merged = %+v, want canonical song with version 2", merged)
+	}
+
+	if _, ok := store.songs.songs[1]; !ok || len(store.songs.songs) != 1 {
+		t.Fatalf("songs left = %v, want only canonical song", store.songs.songs)
+	}
+
+	// duplicates point to canonical song, so they can be found and restored
+	for _, duplicateID := range []int{2, 3} {
+		entries := store.songs.songHistory(duplicateID)
+		if len(entries) != 1 || entries[0].Action != songModel.HistoryMerged ||
+			entries[0].MergedInto == nil || *entries[0].MergedInto != 1 {
+			t.Fatalf("history of duplicate %d = %+v", duplicateID, entries)
+		}
+	}
+
+	// plays of duplicates count for canonical song
+	if !reflect.DeepEqual(store.songs.plays, map[int]int{1: 10}) {
+		t.Fatalf("plays = %v, want 10 plays of canonical song", store.songs.plays)
+	}
+
+	// languages canonical song lacks come from lowest duplicate id
+	variants := make(map[string]string)
+	for _, variant := range store.songs.variants[1] {
+		variants[variant.Language] = variant.Text
+	}
+	wantVariants := map[string]string{"en": "It's bugging me", "de": "Es nervt mich", "fr": "Ça me dérange"}
+	if !reflect.DeepEqual(variants, wantVariants) {
+		t.Fatalf("variants of canonical song = %v, want %v", variants, wantVariants)
+	}
+
+	// canonical song without media takes it from lowest duplicate id having
+	// it, blobs of media deleted with other duplicates are released
+	if audio := store.songs.audio[1]; audio == nil || audio.BlobKey != "audio/aa/aa11" || len(store.songs.audio) != 1 {
+		t.Fatalf("audio = %v, want audio of song 2 on canonical song", store.songs.audio)
+	}
+	if cover := store.songs.covers[1]; cover == nil || cover.BlobKey != "covers/dd/dd44.png" || len(store.songs.covers) != 1 {
+		t.Fatalf("covers = %v, want cover of song 3 on canonical song", store.songs.covers)
+	}
+	if !slices.Equal(store.blobs.deleted, []string{"audio/bb/bb22"}) {
+		t.Fatalf("deleted blobs = %v, want audio of song 3", store.blobs.deleted)
+	}
+
+	entries := store.songs.songHistory(1)
+	if len(entries) != 1 || entries[0].Action != songModel.HistoryMerged || !slices.Equal(entries[0].MergedFrom, []int{2, 3}) {
+		t.Fatalf("history of canonical song = %+v", entries)
+	}
+	diff := historyDiff(t, entries[0])
+	if diff["lyrics.de"].After != "Es nervt mich" || diff["lyrics.fr"].After != "Ça me dérange" || len(diff) != 2 {
+		t.Fatalf("diff of canonical song = %v, want new translations", diff)
+	}
+
+	var eventTypes []string
+	for _, event := range store.webhooks.events {
+		eventTypes = append(eventTypes, event.EventType)
+	}
+	want := []string{songModel.EventSongDeleted, songModel.EventSongDeleted, songModel.EventSongMerged}
+	if !slices.Equal(eventTypes, want) {
+		t.Fatalf("events = %v, want %v", eventTypes, want)
+	}
+
+	var payload struct {
+		ID         int   `json:"id"`
+		MergedFrom []int `json:"mergedFrom"`
+	}
+	if err := json.Unmarshal(store.webhooks.events[2].Payload, &payload); err != nil ||
+		payload.ID != 1 || !slices.Equal(payload.MergedFrom, []int{2, 3}) {
+		t.Fatalf("merged event payload = %s, %v", store.webhooks.events[2].Payload, err)
+	}
+}
+
+func TestMergeSongsRejects(t *testing.T) {
+	tests := []struct {
+		name         string
+		duplicateIDs []int
+		status       int
+	}{
+		{name: "into itself", duplicateIDs: []int{2, 1}, status: http.StatusBadRequest},
+		{name: "missing duplicate", duplicateIDs: []int{2, 4}, status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMergeStore()
+
+			_, err := NewSongService(store, store.blobs).MergeSongs(context.Background(), 1, tt.duplicateIDs)
+			if err == nil || errlst.ParseErrors(err).Status() != tt.status {
+				t.Fatalf("MergeSongs error = %v, want %d", err, tt.status)
+			}
+			if len(store.songs.songs) != 3 || len(store.songs.history) != 0 {
+				t.Fatal("rejected merge changed songs")
+			}
+		})
+	}
+}
+
+func TestMergeSongsChangedConcurrently(t *testing.T) {
+	store := newMergeStore()
+	store.songs.beforeWrite = func(songID int) { store.songs.songs[songID].Version++ }
+
+	if _, err := NewSongService(store, store.blobs).MergeSongs(context.Background(), 1, []int{2}); !isPreconditionFailed(err) {
+		t.Fatalf("MergeSongs error = %v, want 412", err)
+	}
+}
+
+func TestMergeSongsWithoutNewVariants(t *testing.T) {
+	store := newMergeStore()
+
+	// song 2 has only language canonical song already has
+	store.songs.variants[2] = store.songs.variants[2][:1]
+
+	merged, err := NewSongService(store, store.blobs).MergeSongs(context.Background(), 1, []int{2})
+	if err != nil {
+		t.Fatalf("MergeSongs: %v", err)
+	}
+	if merged.Version != 1 || len(store.songs.variants[1]) != 1 {
+		t.Fatalf("merged version %d with %d variants, want unchanged canonical song", merged.Version, len(store.songs.variants[1]))
+	}
+}
+
+func TestMergeSongsKeepsCanonicalMedia(t *testing.T) {
+	store := newMergeStore()
+	store.songs.audio[1] = &songModel.SongAudioDAO{SongID: 1, BlobKey: "audio/cc/cc33"}
+	store.songs.covers[1] = &songModel.SongCoverDAO{SongID: 1, BlobKey: "covers/ee/ee55.png"}
+
+	if _, err := NewSongService(store, store.blobs).MergeSongs(context.Background(), 1, []int{2, 3}); err != nil {
+		t.Fatalf("MergeSongs: %v", err)
+	}
+
+	if audio := store.songs.audio[1]; audio.BlobKey != "audio/cc/cc33" || len(store.songs.audio) != 1 {
+		t.Fatalf("audio = %v, want own audio of canonical song", store.songs.audio)
+	}
+	if cover := store.songs.covers[1]; cover.BlobKey != "covers/ee/ee55.png" || len(store.songs.covers) != 1 {
+		t.Fatalf("covers = %v, want own cover of canonical song", store.songs.covers)
+	}
+
+	want := []string{
+		"audio/aa/aa11", "audio/bb/bb22",
+		"covers/dd/dd44.png", "covers/dd/dd44_96.jpg", "covers/dd/dd44_300.jpg", "covers/dd/dd44_600.jpg",
+	}
+	if !slices.Equal(store.blobs.deleted, want) {
+		t.Fatalf("deleted blobs = %v, want %v", store.blobs.deleted, want)
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jumayevgadam/music-app/internal/database"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"go.opentelemetry.io/otel"
+)
+
+// ListLyricVariants service returns lyric variants of song, original first
+func (s *SongService) ListLyricVariants(ctx context.Context, songID int) ([]*songModel.LyricVariantDTO, error) {
+	tracer := otel.Tracer("[ListLyricVariants][Service]")
+	ctx, span := tracer.Start(ctx, "ListLyricVariants")
+	defer span.End()
+
+	// missing song is 404, song without variants is empty list
+	if _, err := s.repo.SongRepo().GetSong(ctx, songID); err != nil {
+		return nil, err
+	}
+
+	variants, err := s.repo.SongRepo().ListLyricVariants(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*songModel.LyricVariantDTO, 0, len(variants))
+	for _, variant := range variants {
+		result = append(result, variant.ToServer())
+	}
+
+	return result, nil
+}
+
+// GetLyricVariant service is
+func (s *SongService) GetLyricVariant(ctx context.Context, songID int, language string) (*songModel.LyricVariantDTO, error) {
+	tracer := otel.Tracer("[GetLyricVariant][Service]")
+	ctx, span := tracer.Start(ctx, "GetLyricVariant")
+	defer span.End()
+
+	variant, err := s.repo.SongRepo().GetLyricVariant(ctx, songID, language)
+	if err != nil {
+		return nil, err
+	}
+
+	return variant.ToServer(), nil
+}
+
+// PutLyricVariant service creates or replaces variant and returns it with new
+// version of song. Variant saved as original gives its text to song, original
+// variant can't be unmarked. Version works as in UpdateSong.
+func (s *SongService) PutLyricVariant(
+	ctx context.Context,
+	songID int,
+	version int,
+	dtoModel *songModel.LyricVariantDTO,
+) (*songModel.LyricVariantDTO, int, error) {
+	tracer := otel.Tracer("[PutLyricVariant][Service]")
+	ctx, span := tracer.Start(ctx, "PutLyricVariant")
+	defer span.End()
+
+	var (
+		saved   *songModel.LyricVariantDAO
+		updated *songModel.DAO
+	)
+
+	if err := s.repo.WithTransaction(ctx, func(db database.DataStore) error {
+		before, err := db.SongRepo().GetSong(ctx, songID)
+		if err != nil {
+			return err
+		}
+
+		change := songModel.FieldChange{After: dtoModel.Text}
+		existing, err := db.SongRepo().GetLyricVariant(ctx, songID, dtoModel.Language)
+		switch {
+		case err == nil:
+			if existing.Original && !dtoModel.Original {
+				return errlst.Conflict(musicOps.ErrOriginalLyricVariant.Error(), musicOps.ErrOriginalLyricVariant)
+			}
+			change.Before = existing.Text
+		case errlst.KindOf(err) != errlst.KindNotFound:
+			return err
+		}
+
+		saved, err = db.SongRepo().SaveLyricVariant(ctx, dtoModel.ToStorage(songID))
+		if err != nil {
+			return err
+		}
+
+		song := *before
+		song.Version = version
+		if saved.Original {
+			song.Text = saved.Text
+		}
+
+		updated, err = updateSongWithLyrics(ctx, db, songModel.HistoryUpdated, before, &song,
+			map[string]songModel.FieldChange{saved.Language: change})
+		return err
+	}); err != nil {
+		return nil, 0, err
+	}
+
+	return saved.ToServer(), updated.Version, nil
+}
+
+// DeleteLyricVariant service deletes translation and returns new version of song,
+// original variant can't be deleted. Version works as in UpdateSong.
+func (s *SongService) DeleteLyricVariant(ctx context.Context, songID int, version int, language string) (int, error) {
+	tracer := otel.Tracer("[DeleteLyricVariant][Service]")
+	ctx, span := tracer.Start(ctx, "DeleteLyricVariant")
+	defer span.End()
+
+	var updated *songModel.DAO
+
+	if err := s.repo.WithTransaction(ctx, func(db database.DataStore) error {
+		before, err := db.SongRepo().GetSong(ctx, songID)
+		if err != nil {
+			return err
+		}
+
+		existing, err := db.SongRepo().GetLyricVariant(ctx, songID, language)
+		if err != nil {
+			return err
+		}
+		if existing.Original {
+			return errlst.Conflict(musicOps.ErrOriginalLyricVariant.Error(), musicOps.ErrOriginalLyricVariant)
+		}
+
+		if err := db.SongRepo().DeleteLyricVariant(ctx, songID, language); err != nil {
+			return err
+		}
+
+		song := *before
+		song.Version = version
+
+		updated, err = updateSongWithLyrics(ctx, db, songModel.HistoryUpdated, before, &song,
+			map[string]songModel.FieldChange{language: {Before: existing.Text}})
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	return updated.Version, nil
+}
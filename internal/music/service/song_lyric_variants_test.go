@@ -0,0 +1,66 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+)
+
+func TestNegotiateLyrics(t *testing.T) {
+	song := testSong(1, "Hysteria")
+	lines := []*songModel.LyricLineDAO{{SongID: 1, Position: 1, TimeMs: 1000, Text: "It's bugging me"}}
+	variants := []*songModel.LyricVariantDAO{
+		{SongID: 1, Language: "en", Text: song.Text, Original: true},
+		{SongID: 1, Language: "de", Text: "Es nervt mich"},
+		{SongID: 1, Language: "pt-BR", Text: "Está me incomodando"},
+	}
+
+	tests := []struct {
+		acceptLanguage string
+		language       string
+		original       bool
+	}{
+		{acceptLanguage: "", language: "en", original: true},
+		{acceptLanguage: "de", language: "de"},
+		{acceptLanguage: "de-AT, en;q=0.5", language: "de"},
+		{acceptLanguage: "fr;q=0.9, de;q=0.8", language: "de"},
+		{acceptLanguage: "en-GB", language: "en", original: true},
+		{acceptLanguage: "pt", language: "pt-BR"},
+		{acceptLanguage: "ja", language: "en", original: true},
+		{acceptLanguage: "not a language!", language: "en", original: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			got := negotiateLyrics(song, lines, variants, tt.acceptLanguage)
+
+			if got.Language != tt.language || got.Original != tt.original {
+				t.Fatalf("lyrics in %q (original %v), want %q (original %v)", got.Language, got.Original, tt.language, tt.original)
+			}
+			if want := []string{"en", "de", "pt-BR"}; !slices.Equal(got.Languages, want) {
+				t.Fatalf("languages = %v, want %v", got.Languages, want)
+			}
+
+			// timings belong to original text only
+			if tt.original && (got.Text != song.Text || len(got.Lines) != 1) || !tt.original && len(got.Lines) != 0 {
+				t.Fatalf("lyrics text %q with %d lines", got.Text, len(got.Lines))
+			}
+		})
+	}
+}
+
+func TestNegotiateLyricsWithoutOriginal(t *testing.T) {
+	song := testSong(1, "Hysteria")
+	variants := []*songModel.LyricVariantDAO{{SongID: 1, Language: "de", Text: "Es nervt mich"}}
+
+	// original language is unknown, so it is fallback without language
+	got := negotiateLyrics(song, nil, variants, "en")
+	if !got.Original || got.Language != "" || got.Text != song.Text {
+		t.Fatalf("lyrics = %+v, want original text of unknown language", got)
+	}
+
+	if got := negotiateLyrics(song, nil, variants, "de"); got.Original || got.Text != "Es nervt mich" {
+		t.Fatalf("lyrics = %+v, want german translation", got)
+	}
+}
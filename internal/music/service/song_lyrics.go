@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/jumayevgadam/music-app/internal/database"
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/lrc"
+	"github.com/jumayevgadam/music-app/pkg/reqvalidator"
+	"go.opentelemetry.io/otel"
+	"golang.org/x/text/language"
+)
+
+// GetSongLyrics service returns lyrics in language picked by Accept-Language,
+// original lyrics with synced lines are returned when no variant matches
+func (s *SongService) GetSongLyrics(ctx context.Context, songID int, acceptLanguage string) (*songModel.SongLyricsDTO, error) {
+	tracer := otel.Tracer("[GetSongLyrics][Service]")
+	ctx, span := tracer.Start(ctx, "GetSongLyrics")
+	defer span.End()
+
+	song, err := s.repo.SongRepo().GetSong(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.repo.SongRepo().ListLyricLines(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := s.repo.SongRepo().ListLyricVariants(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	return negotiateLyrics(song, lines, variants, acceptLanguage), nil
+}
+
+// PutSongLyrics service stores synced lyrics and replaces text of song with their
+// plain text, it is recorded as update of song and version works as in UpdateSong
+func (s *SongService) PutSongLyrics(ctx context.Context, songID int, version int, lyrics *lrc.Lyrics) (*songModel.SongLyricsDTO, error) {
+	tracer := otel.Tracer("[PutSongLyrics][Service]")
+	ctx, span := tracer.Start(ctx, "PutSongLyrics")
+	defer span.End()
+
+	var (
+		updated  *songModel.DAO
+		variants []*songModel.LyricVariantDAO
+		lines    = songModel.LyricLinesToStorage(songID, lyrics.Lines)
+	)
+
+	if err := s.repo.WithTransaction(ctx, func(db database.DataStore) error {
+		before, err := db.SongRepo().GetSong(ctx, songID)
+		if err != nil {
+			return err
+		}
+
+		song := before.ToServer()
+		song.Text = lyrics.Plain()
+		song.Version = version
+
+		if err := reqvalidator.ValidateStruct(ctx, song); err != nil {
+			return err
+		}
+
+		updated, err = updateSong(ctx, db, songModel.HistoryUpdated, before, song.ToStorage())
+		if err != nil {
+			return err
+		}
+
+		if err := db.SongRepo().ReplaceLyricLines(ctx, songID, lines); err != nil {
+			return err
+		}
+
+		variants, err = db.SongRepo().ListLyricVariants(ctx, songID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return negotiateLyrics(updated, lines, variants, ""), nil
+}
+
+// GetLyricLineAt service returns line sung at given time of song
+func (s *SongService) GetLyricLineAt(ctx context.Context, songID int, at time.Duration) (*songModel.LyricLineAtDTO, error) {
+	tracer := otel.Tracer("[GetLyricLineAt][Service]")
+	ctx, span := tracer.Start(ctx, "GetLyricLineAt")
+	defer span.End()
+
+	songLyrics, err := s.GetSongLyrics(ctx, songID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(songLyrics.Lines) == 0 {
+		return nil, errlst.NotFound(musicOps.ErrNoSyncedLyrics.Error(), musicOps.ErrNoSyncedLyrics)
+	}
+
+	return lyricLineAt(songID, songLyrics.Lines, at), nil
+}
+
+// lyricLineAt finds line of lines sung at given time, last line has no next time
+func lyricLineAt(songID int, lines []*songModel.LyricLineDTO, at time.Duration) *songModel.LyricLineAtDTO {
+	lyrics := &lrc.Lyrics{Lines: songModel.LyricLinesToLRC(lines)}
+	index := lyrics.LineAt(at)
+
+	lineAt := &songModel.LyricLineAtDTO{SongID: songID, TimeMs: at.Milliseconds()}
+	if index >= 0 {
+		lineAt.Line = lines[index]
+	}
+	if index+1 < len(lines) {
+		lineAt.NextTimeMs = &lines[index+1].TimeMs
+	}
+
+	return lineAt
+}
+
+// negotiateLyrics picks variant of lyrics for Accept-Language. Original lyrics
+// are fallback, their language isn't known when no variant is marked original.
+func negotiateLyrics(
+	song *songModel.DAO,
+	lines []*songModel.LyricLineDAO,
+	variants []*songModel.LyricVariantDAO,
+	acceptLanguage string,
+) *songModel.SongLyricsDTO {
+	songLyrics := &songModel.SongLyricsDTO{
+		SongID:    song.ID,
+		Group:     song.Group,
+		Title:     song.Title,
+		Version:   song.Version,
+		Original:  true,
+		Languages: make([]string, 0, len(variants)),
+		Text:      song.Text,
+		Lines:     make([]*songModel.LyricLineDTO, 0, len(lines)),
+	}
+
+	// supported languages are original first, then translations
+	supported := []language.Tag{language.Und}
+	translations := make([]*songModel.LyricVariantDAO, 0, len(variants))
+	for _, variant := range variants {
+		if variant.Original {
+			supported[0] = language.Make(variant.Language)
+			songLyrics.Language = variant.Language
+			songLyrics.Languages = append([]string{variant.Language}, songLyrics.Languages...)
+			continue
+		}
+		supported = append(supported, language.Make(variant.Language))
+		translations = append(translations, variant)
+		songLyrics.Languages = append(songLyrics.Languages, variant.Language)
+	}
+
+	if index := musicOps.MatchLanguage(acceptLanguage, supported); index > 0 {
+		translation := translations[index-1]
+		songLyrics.Language, songLyrics.Original, songLyrics.Text = translation.Language, false, translation.Text
+		return songLyrics
+	}
+
+	for _, line := range lines {
+		songLyrics.Lines = append(songLyrics.Lines, line.ToServer())
+	}
+
+	return songLyrics
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+)
+
+func TestLyricLineAt(t *testing.T) {
+	lines := []*songModel.LyricLineDTO{
+		{Position: 1, TimeMs: 1000, Timestamp: "00:01.00", Text: "one"},
+		{Position: 2, TimeMs: 2500, Timestamp: "00:02.50", Text: "two"},
+		{Position: 3, TimeMs: 4000, Timestamp: "00:04.00", Text: "three"},
+	}
+
+	tests := []struct {
+		name     string
+		at       time.Duration
+		wantLine int
+		wantNext *int64
+	}{
+		{name: "before first line", at: 500 * time.Millisecond, wantLine: 0, wantNext: &lines[0].TimeMs},
+		{name: "at first line", at: time.Second, wantLine: 1, wantNext: &lines[1].TimeMs},
+		{name: "between lines", at: 3 * time.Second, wantLine: 2, wantNext: &lines[2].TimeMs},
+		{name: "at last line", at: 4 * time.Second, wantLine: 3},
+		{name: "after last line", at: time.Hour, wantLine: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lyricLineAt(7, lines, tt.at)
+
+			if got.SongID != 7 || got.TimeMs != tt.at.Milliseconds() {
+				t.Fatalf("lyricLineAt = song %d at %d, want song 7 at %d", got.SongID, got.TimeMs, tt.at.Milliseconds())
+			}
+
+			switch {
+			case tt.wantLine == 0 && got.Line != nil:
+				t.Fatalf("line = %+v, want nil", got.Line)
+			case tt.wantLine > 0 && (got.Line == nil || got.Line.Position != tt.wantLine):
+				t.Fatalf("line = %+v, want position %d", got.Line, tt.wantLine)
+			}
+
+			switch {
+			case tt.wantNext == nil && got.NextTimeMs != nil:
+				t.Fatalf("NextTimeMs = %d, want nil at last line", *got.NextTimeMs)
+			case tt.wantNext != nil && (got.NextTimeMs == nil || *got.NextTimeMs != *tt.wantNext):
+				t.Fatalf("NextTimeMs = %v, want %d", got.NextTimeMs, *tt.wantNext)
+			}
+		})
+	}
+}
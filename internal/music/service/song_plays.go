@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/pkg/auth"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"go.opentelemetry.io/otel"
+)
+
+// RecordPlay service appends play event of song, it is counted in charts after next rollup
+func (s *SongService) RecordPlay(ctx context.Context, songID int, dtoModel *songModel.SongPlayDTO) error {
+	tracer := otel.Tracer("[RecordPlay][Service]")
+	ctx, span := tracer.Start(ctx, "RecordPlay")
+	defer span.End()
+
+	return s.repo.SongRepo().RecordPlay(ctx, dtoModel.ToStorage(songID, auth.ActorFromContext(ctx)))
+}
+
+// TopSongs service returns most played songs of period, optionally of one group
+func (s *SongService) TopSongs(ctx context.Context, period string, group string, limit int) (*songModel.ChartDTO, error) {
+	tracer := otel.Tracer("[TopSongs][Service]")
+	ctx, span := tracer.Start(ctx, "TopSongs")
+	defer span.End()
+
+	duration, ok := musicOps.ChartPeriods[period]
+	if !ok {
+		return nil, errlst.Validation(
+			fmt.Sprintf("period must be one of %s", strings.Join(musicOps.ChartPeriodNames, ", ")),
+			errlst.ErrBadQueryParams,
+		)
+	}
+
+	limit, err := chartLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	chart := &songModel.ChartDTO{
+		Chart:       musicOps.ChartTop,
+		Period:      period,
+		Group:       group,
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	chartQuery := songModel.ChartQueryDAO{
+		Hourly: musicOps.UsesHourlyRollup(duration),
+		Group:  musicOps.NormalizeGroup(group),
+		Limit:  limit,
+	}
+	if duration > 0 {
+		since := chart.GeneratedAt.Add(-duration)
+		chart.Since = &since
+		chartQuery.Since = since
+	}
+
+	entries, err := s.repo.SongRepo().ListTopSongs(ctx, chartQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	chart.Entries = chartEntries(entries)
+
+	return chart, nil
+}
+
+// TrendingSongs service returns songs ranked by recent plays, optionally of one group
+func (s *SongService) TrendingSongs(ctx context.Context, group string, limit int) (*songModel.ChartDTO, error) {
+	tracer := otel.Tracer("[TrendingSongs][Service]")
+	ctx, span := tracer.Start(ctx, "TrendingSongs")
+	defer span.End()
+
+	limit, err := chartLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	since := now.Add(-musicOps.TrendingWindow)
+
+	entries, err := s.repo.SongRepo().ListTrendingSongs(ctx, songModel.TrendingQueryDAO{
+		Now:      now,
+		Since:    since,
+		HalfLife: musicOps.TrendingHalfLife,
+		Group:    musicOps.NormalizeGroup(group),
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &songModel.ChartDTO{
+		Chart:       musicOps.ChartTrending,
+		Group:       group,
+		Since:       &since,
+		GeneratedAt: now,
+		Entries:     chartEntries(entries),
+	}, nil
+}
+
+// chartLimit returns DefaultChartLimit for zero limit
+func chartLimit(limit int) (int, error) {
+	switch {
+	case limit == 0:
+		return musicOps.DefaultChartLimit, nil
+	case limit < 0 || limit > musicOps.MaxChartLimit:
+		return 0, errlst.Validation(
+			fmt.Sprintf("limit must be between 1 and %d", musicOps.MaxChartLimit),
+			errlst.ErrBadQueryParams,
+		)
+	}
+
+	return limit, nil
+}
+
+// chartEntries ranks entries in order they are returned, starting from 1
+func chartEntries(entries []*songModel.ChartEntryDAO) []*songModel.ChartEntryDTO {
+	result := make([]*songModel.ChartEntryDTO, 0, len(entries))
+	for i, entry := range entries {
+		result = append(result, entry.ToServer(i+1))
+	}
+
+	return result
+}
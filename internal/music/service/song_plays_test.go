@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+	musicOps "github.com/jumayevgadam/music-app/internal/music"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+)
+
+func chartScore(score float64) *float64 {
+	return &score
+}
+
+func TestTrendingSongs(t *testing.T) {
+	store := newFakeStore()
+	store.songs.charts = []*songModel.ChartEntryDAO{
+		{SongID: 7, Group: "Muse", Title: "Hysteria", Plays: 10, Score: chartScore(8.5)},
+		{SongID: 3, Group: "Muse", Title: "Uprising", Plays: 40, Score: chartScore(2.1)},
+	}
+
+	chart, err := NewSongService(store, store.blobs).TrendingSongs(context.Background(), " MUSE ", 0)
+	if err != nil {
+		t.Fatalf("TrendingSongs: %v", err)
+	}
+
+	query := store.songs.trendingQuery
+	if query.Group != "muse" || query.Limit != musicOps.DefaultChartLimit || query.HalfLife != musicOps.TrendingHalfLife {
+		t.Fatalf("query = %+v", query)
+	}
+	if !query.Now.Equal(chart.GeneratedAt) || query.Now.Sub(query.Since) != musicOps.TrendingWindow || !chart.Since.Equal(query.Since) {
+		t.Fatalf("query covers [%v, %v], chart since %v generated at %v", query.Since, query.Now, chart.Since, chart.GeneratedAt)
+	}
+
+	// entries keep order of score, not of plays
+	if chart.Chart != musicOps.ChartTrending || len(chart.Entries) != 2 ||
+		chart.Entries[0].Rank != 1 || chart.Entries[0].SongID != 7 ||
+		chart.Entries[1].Rank != 2 || chart.Entries[1].SongID != 3 {
+		t.Fatalf("chart = %+v", chart)
+	}
+}
+
+func TestTrendingDecay(t *testing.T) {
+	// score weighs play 2^(-age/half life), so plays outside window are negligible
+	weight := func(age time.Duration) float64 {
+		return math.Exp(-math.Ln2 * age.Seconds() / musicOps.TrendingHalfLife.Seconds())
+	}
+
+	if got := weight(musicOps.TrendingHalfLife); math.Abs(got-0.5) > 1e-9 {
+		t.Fatalf("weight after half life = %v, want 0.5", got)
+	}
+	if got := weight(musicOps.TrendingWindow); got >= 0.01 {
+		t.Fatalf("weight at end of window = %v, want under 1%%", got)
+	}
+
+	// 10 plays of last hour outweigh 40 plays of three days ago
+	if recent, old := 10*weight(time.Hour), 40*weight(72*time.Hour); recent <= old {
+		t.Fatalf("recent score %v <= old score %v", recent, old)
+	}
+}
+
+func TestTopSongs(t *testing.T) {
+	tests := []struct {
+		period string
+		hourly bool
+		since  bool
+	}{
+		{period: "day", hourly: true, since: true},
+		{period: "week", hourly: true, since: true},
+		{period: "month", since: true},
+		{period: "all"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			store := newFakeStore()
+
+			chart, err := NewSongService(store, store.blobs).TopSongs(context.Background(), tt.period, "", 10)
+			if err != nil {
+				t.Fatalf("TopSongs: %v", err)
+			}
+
+			query := store.songs.topQuery
+			if query.Hourly != tt.hourly || query.Limit != 10 || query.Since.IsZero() == tt.since || (chart.Since != nil) != tt.since {
+				t.Fatalf("query = %+v, chart since %v", query, chart.Since)
+			}
+			if tt.since && chart.GeneratedAt.Sub(query.Since) != musicOps.ChartPeriods[tt.period] {
+				t.Fatalf("query since %v, want %v before %v", query.Since, musicOps.ChartPeriods[tt.period], chart.GeneratedAt)
+			}
+		})
+	}
+}
+
+func TestChartsReject(t *testing.T) {
+	store := newFakeStore()
+	service := NewSongService(store, store.blobs)
+
+	for name, err := range map[string]error{
+		"unknown period": func() error { _, err := service.TopSongs(context.Background(), "decade", "", 0); return err }(),
+		"negative limit": func() error { _, err := service.TopSongs(context.Background(), "day", "", -1); return err }(),
+		"big limit": func() error {
+			_, err := service.TrendingSongs(context.Background(), "", musicOps.MaxChartLimit+1)
+			return err
+		}(),
+	} {
+		if err == nil || errlst.ParseErrors(err).Status() != http.StatusBadRequest {
+			t.Fatalf("%s: error = %v, want 400", name, err)
+		}
+	}
+}
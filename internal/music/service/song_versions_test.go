@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+)
+
+// isPreconditionFailed reports whether err is answered with 412
+func isPreconditionFailed(err error) bool {
+	return errlst.KindOf(err) == errlst.KindPrecondition &&
+		errlst.ParseErrors(err).Status() == http.StatusPreconditionFailed
+}
+
+func TestUpdateSongVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		ok      bool
+	}{
+		{name: "current version", version: 3, ok: true},
+		{name: "any version", version: 0, ok: true},
+		{name: "stale version", version: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			song := testSong(1, "Hysteria")
+			song.Version = 3
+			store := newFakeStore(song)
+
+			update := testSong(1, "Hysteria (live)").ToServer()
+			update.Version = tt.version
+
+			updated, err := NewSongService(store, store.blobs).UpdateSong(context.Background(), update)
+			if tt.ok {
+				if err != nil || updated.Version != 4 {
+					t.Fatalf("UpdateSong = %+v, %v, want version 4", updated, err)
+				}
+				return
+			}
+
+			if !isPreconditionFailed(err) {
+				t.Fatalf("UpdateSong error = %v, want 412", err)
+			}
+			if store.songs.songs[1].Title != "Hysteria" || len(store.songs.history) != 0 {
+				t.Fatal("rejected update changed song")
+			}
+		})
+	}
+}
+
+func TestUpdateSongChangedConcurrently(t *testing.T) {
+	store := newFakeStore(testSong(1, "Hysteria"))
+	store.songs.beforeWrite = func(songID int) { store.songs.songs[songID].Version++ }
+
+	update := testSong(1, "Hysteria (live)").ToServer()
+	if _, err := NewSongService(store, store.blobs).UpdateSong(context.Background(), update); !isPreconditionFailed(err) {
+		t.Fatalf("UpdateSong error = %v, want 412", err)
+	}
+}
+
+func TestDeleteSongVersion(t *testing.T) {
+	store := newFakeStore(testSong(1, "Hysteria"))
+	service := NewSongService(store, store.blobs)
+
+	if err := service.DeleteSong(context.Background(), 1, 2); !isPreconditionFailed(err) {
+		t.Fatalf("DeleteSong of stale version error = %v, want 412", err)
+	}
+	if _, ok := store.songs.songs[1]; !ok {
+		t.Fatal("song of stale version was deleted")
+	}
+
+	if err := service.DeleteSong(context.Background(), 1, 1); err != nil {
+		t.Fatalf("DeleteSong: %v", err)
+	}
+	if _, ok := store.songs.songs[1]; ok {
+		t.Fatal("song of current version wasn't deleted")
+	}
+}
@@ -0,0 +1,210 @@
+package songio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	songModel "github.com/jumayevgadam/music-app/internal/models"
+)
+
+// songio package reads and writes streams of songs in CSV and JSON formats,
+// it's used by bulk import and export.
+
+// Format is
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+	FormatJSON   Format = "json"
+)
+
+// maxLineSize limits one NDJSON line, lyrics can be long
+const maxLineSize = 4 << 20
+
+// RowReader reads song rows one by one. Read returns io.EOF at the end and
+// *RowError when only current row is broken, reading can go on after it.
+type RowReader interface {
+	Read() (*songModel.DTO, error)
+	// Row returns number of last read row, starting from 1
+	Row() int
+}
+
+// RowError is
+type RowError struct {
+	Row int
+	Err error
+}
+
+// Error is
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+// Unwrap is
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// ParseFormat returns format by its name or by content type of request
+func ParseFormat(name, contentType string) (Format, error) {
+	if name == "" {
+		mediaType, _, _ := strings.Cut(contentType, ";")
+		switch strings.TrimSpace(strings.ToLower(mediaType)) {
+		case "text/csv", "application/csv":
+			return FormatCSV, nil
+		case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+			return FormatNDJSON, nil
+		case "application/json":
+			return FormatJSON, nil
+		}
+	}
+
+	switch Format(strings.ToLower(name)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatNDJSON, "jsonl":
+		return FormatNDJSON, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	}
+
+	return "", fmt.Errorf("unsupported format %q, allowed values: csv, ndjson, json", name+contentType)
+}
+
+// NewReader returns row reader of format, only csv and ndjson can be streamed in
+func NewReader(format Format, r io.Reader) (RowReader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+		return &ndjsonReader{scanner: scanner}, nil
+	default:
+		return nil, fmt.Errorf("format %q can't be imported, use csv or ndjson", format)
+	}
+}
+
+// csvColumns are columns which can be imported, other known columns are ignored
+var (
+	csvColumns        = []string{"group", "title", "release_date", "text", "link"}
+	csvIgnoredColumns = []string{
+		"id", "created_at", "updated_at", "version",
+		"link_provider", "link_id", "link_duration", "link_thumbnail_url",
+		"link_status", "link_status_code", "link_failures", "link_checked_at",
+	}
+)
+
+// csvReader reads CSV with header row
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+
+		if !slices.Contains(csvColumns, name) && !slices.Contains(csvIgnoredColumns, name) {
+			return nil, fmt.Errorf("unknown csv column %q, allowed values: %s", name, strings.Join(csvColumns, ", "))
+		}
+		columns[name] = i
+	}
+
+	for _, c := range csvColumns {
+		if _, ok := columns[c]; !ok {
+			return nil, fmt.Errorf("csv header misses column %q", c)
+		}
+	}
+
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+// Read is
+func (r *csvReader) Read() (*songModel.DTO, error) {
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	r.row++
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, &RowError{Row: r.row, Err: parseErr.Err}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	song := &songModel.DTO{
+		Group: record[r.columns["group"]],
+		Title: record[r.columns["title"]],
+		Text:  record[r.columns["text"]],
+		Link:  record[r.columns["link"]],
+	}
+	if err := song.ReleaseDate.UnmarshalText([]byte(record[r.columns["release_date"]])); err != nil {
+		return nil, &RowError{Row: r.row, Err: err}
+	}
+
+	return song, nil
+}
+
+// Row is
+func (r *csvReader) Row() int {
+	return r.row
+}
+
+// ndjsonReader reads one JSON object per line, empty lines are skipped
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+// Read is
+func (r *ndjsonReader) Read() (*songModel.DTO, error) {
+	for r.scanner.Scan() {
+		r.row++
+
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var song songModel.DTO
+		if err := json.Unmarshal(line, &song); err != nil {
+			return nil, &RowError{Row: r.row, Err: err}
+		}
+		song.ID = 0
+
+		return &song, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read ndjson line %d: %w", r.row+1, err)
+	}
+
+	return nil, io.EOF
+}
+
+// Row is
+func (r *ndjsonReader) Row() int {
+	return r.row
+}
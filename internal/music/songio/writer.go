@@ -0,0 +1,168 @@
+package songio
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Columns are song columns which can be exported, in default order
+var Columns = []string{
+	"id", "group", "title", "release_date", "text", "link", "created_at", "updated_at", "version",
+	"link_provider", "link_id", "link_duration", "link_thumbnail_url",
+	"link_status", "link_status_code", "link_failures", "link_checked_at",
+}
+
+// ParseColumns parses comma separated column names, empty value gives all columns
+func ParseColumns(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return slices.Clone(Columns), nil
+	}
+
+	var columns []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(Columns, name) {
+			return nil, fmt.Errorf("unknown column %q, allowed values: %s", name, strings.Join(Columns, ", "))
+		}
+		if slices.Contains(columns, name) {
+			return nil, fmt.Errorf("column %q is given more than once", name)
+		}
+		columns = append(columns, name)
+	}
+
+	return columns, nil
+}
+
+// ContentType returns content type of format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/json"
+	}
+}
+
+// RowWriter writes rows of selected columns, Close must be called to finish output
+type RowWriter interface {
+	Write(values []interface{}) error
+	Close() error
+}
+
+// NewWriter returns row writer of format, values given to Write follow columns order
+func NewWriter(format Format, w io.Writer, columns []string) (RowWriter, error) {
+	switch format {
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(columns); err != nil {
+			return nil, err
+		}
+		return &csvWriter{writer: writer, record: make([]string, len(columns))}, nil
+	case FormatNDJSON:
+		return &jsonWriter{w: w, columns: columns, separator: "\n"}, nil
+	case FormatJSON:
+		if _, err := io.WriteString(w, "["); err != nil {
+			return nil, err
+		}
+		return &jsonWriter{w: w, columns: columns, separator: ",", array: true}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q, allowed values: csv, ndjson, json", format)
+	}
+}
+
+// csvWriter is
+type csvWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+// Write is
+func (w *csvWriter) Write(values []interface{}) error {
+	for i, v := range values {
+		if v == nil {
+			w.record[i] = ""
+			continue
+		}
+		w.record[i] = formatValue(v)
+	}
+
+	return w.writer.Write(w.record)
+}
+
+// formatValue returns text of exported value, times are written in RFC 3339 like in JSON
+func formatValue(v interface{}) string {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+
+	return fmt.Sprint(v)
+}
+
+// Close is
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// jsonWriter writes objects with keys in columns order, one per line
+// for NDJSON or as elements of one array for JSON
+type jsonWriter struct {
+	w         io.Writer
+	columns   []string
+	separator string
+	array     bool
+	written   int
+	buf       bytes.Buffer
+}
+
+// Write is
+func (w *jsonWriter) Write(values []interface{}) error {
+	w.buf.Reset()
+	if w.array && w.written > 0 {
+		w.buf.WriteString(w.separator)
+	}
+
+	w.buf.WriteByte('{')
+	for i, column := range w.columns {
+		if i > 0 {
+			w.buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(column)
+		value, err := json.Marshal(values[i])
+		if err != nil {
+			return fmt.Errorf("marshal column %s: %w", column, err)
+		}
+
+		w.buf.Write(key)
+		w.buf.WriteByte(':')
+		w.buf.Write(value)
+	}
+	w.buf.WriteByte('}')
+
+	if !w.array {
+		w.buf.WriteString(w.separator)
+	}
+	w.written++
+
+	_, err := w.w.Write(w.buf.Bytes())
+	return err
+}
+
+// Close is
+func (w *jsonWriter) Close() error {
+	if !w.array {
+		return nil
+	}
+
+	_, err := io.WriteString(w.w, "]")
+	return err
+}
@@ -0,0 +1,16 @@
+package music
+
+import "github.com/jumayevgadam/music-app/pkg/pagination"
+
+// SongSortFields are fields songs can be sorted by, mapped to SQL columns
+var SongSortFields = pagination.SortWhitelist{
+	"id":           "songs.id",
+	"group":        `songs."group"`,
+	"title":        "songs.title",
+	"release_date": "songs.release_date",
+	"created_at":   "songs.created_at",
+	"updated_at":   "songs.updated_at",
+}
+
+// DefaultSongSort is order of song list when orderBy is not given, newest releases first
+const DefaultSongSort = "-release_date"
@@ -2,6 +2,7 @@ package server
 
 import (
 	songHttp "github.com/jumayevgadam/music-app/internal/music/routes"
+	webhookHttp "github.com/jumayevgadam/music-app/internal/webhook/routes"
 	"github.com/labstack/echo/v4"
 )
 
@@ -11,10 +12,19 @@ const (
 
 // MapHandlers is
 func (s *Server) MapHandlers(e *echo.Echo) error {
-	//* v1 is
-	v1 := s.Echo.Group(v1URL)
-	// song-http route is
-	songHttp.Routes(v1, s.DataStore)
+	// actor of request is recorded in song history
+	e.Use(s.actorMiddleware)
+
+	//* v1 is, its reads go to primary when they must see recent writes
+	v1 := s.Echo.Group(v1URL, s.primaryMiddleware)
+	// song-http route is, song creation honors Idempotency-Key and audio stream
+	// accepts signed URLs
+	songHttp.Routes(v1, s.DataStore, s.Blobs, s.Cfg.Storage, s.Idempotency.Handle, s.signedMiddleware)
+	// webhook-http route is, it needs token even for reads
+	webhookHttp.Routes(v1, s.DataStore, s.authorizedMiddleware)
+
+	// graphql route is
+	songHttp.GraphQLRoutes(e, s.DataStore, s.Blobs)
 
 	return nil
 }
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/jumayevgadam/music-app/pkg/auth"
+	"github.com/jumayevgadam/music-app/pkg/tracing"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcInterceptors keeps dependencies of interceptors
+type grpcInterceptors struct {
+	tokens *auth.TokenStore
+}
+
+// authenticate checks bearer token from authorization metadata and puts actor to context
+func (i *grpcInterceptors) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	// without tokens editor names itself, same as X-Actor header of HTTP API
+	if !i.tokens.Enabled() {
+		if actors := md.Get("x-actor"); len(actors) > 0 && actors[0] != "" {
+			return auth.WithActor(ctx, actors[0]), nil
+		}
+		return ctx, nil
+	}
+
+	for _, value := range md.Get("authorization") {
+		token, ok := auth.BearerToken(value)
+		if !ok {
+			continue
+		}
+
+		actor, err := i.tokens.Authenticate(token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return auth.WithActor(ctx, actor), nil
+	}
+
+	return nil, status.Error(codes.Unauthenticated, auth.ErrInvalidToken.Error())
+}
+
+func (i *grpcInterceptors) authUnary(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := i.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (i *grpcInterceptors) authStream(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := i.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+func (i *grpcInterceptors) loggingUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logRPC(info.FullMethod, start, err)
+
+	return resp, err
+}
+
+func (i *grpcInterceptors) loggingStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logRPC(info.FullMethod, start, err)
+
+	return err
+}
+
+// logRPC logs method, status code and duration of call
+func logRPC(method string, start time.Time, err error) {
+	entry := logrus.WithFields(logrus.Fields{
+		"method":   method,
+		"code":     status.Code(err).String(),
+		"duration": time.Since(start).String(),
+	})
+
+	if err != nil {
+		entry.Errorf("[grpc]: %v", err)
+		return
+	}
+	entry.Info("[grpc]")
+}
+
+func (i *grpcInterceptors) tracingUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, span := startRPCSpan(ctx, info.FullMethod)
+	defer span.End()
+
+	resp, err := handler(ctx, req)
+	endRPCSpan(span, err)
+
+	return resp, err
+}
+
+func (i *grpcInterceptors) tracingStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := startRPCSpan(ss.Context(), info.FullMethod)
+	defer span.End()
+
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	endRPCSpan(span, err)
+
+	return err
+}
+
+func startRPCSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	tracer := otel.Tracer("[grpc]")
+	return tracer.Start(ctx, method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.method", method),
+	))
+}
+
+func endRPCSpan(span trace.Span, err error) {
+	span.SetAttributes(attribute.String("rpc.grpc.status_code", status.Code(err).String()))
+	if err != nil {
+		tracing.ErrorTracer(span, err)
+	}
+}
+
+// contextStream replaces context of server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context is
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"net"
+
+	musicRPC "github.com/jumayevgadam/music-app/internal/music/rpc"
+	"github.com/jumayevgadam/music-app/internal/music/service"
+	"google.golang.org/grpc"
+)
+
+// NewGRPCServer builds gRPC server with auth, logging and tracing interceptors
+// and registers services, they share business logic with HTTP routes
+func (s *Server) NewGRPCServer() *grpc.Server {
+	interceptors := &grpcInterceptors{tokens: s.Tokens}
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors.tracingUnary, interceptors.loggingUnary, interceptors.authUnary),
+		grpc.ChainStreamInterceptor(interceptors.tracingStream, interceptors.loggingStream, interceptors.authStream),
+	)
+
+	// song-rpc service is
+	musicRPC.Register(grpcServer, service.NewSongService(s.DataStore, s.Blobs))
+
+	return grpcServer
+}
+
+// runGRPC serves gRPC on its own port till server is stopped
+func (s *Server) runGRPC(grpcServer *grpc.Server) error {
+	listener, err := net.Listen("tcp", ":"+s.Cfg.Server.GrpcPort)
+	if err != nil {
+		return err
+	}
+
+	return grpcServer.Serve(listener)
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jumayevgadam/music-app/internal/connection"
+	"github.com/jumayevgadam/music-app/pkg/auth"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/urlsign"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderActor names editor of request when authentication is disabled
+const HeaderActor = "X-Actor"
+
+// CookieLastWrite keeps unix milliseconds of client's last write request
+const CookieLastWrite = "last_write"
+
+// actorMiddleware puts actor of request to context, it is recorded in song history.
+// With tokens configured actor is principal of bearer token and only reads
+// (GET, HEAD, OPTIONS) may stay anonymous, as gRPC every other request needs
+// valid token. Without tokens X-Actor header is trusted and requests without
+// it stay anonymous.
+func (s *Server) actorMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := c.Request()
+		actor := ""
+
+		if s.Tokens.Enabled() {
+			if token, ok := auth.BearerToken(request.Header.Get(echo.HeaderAuthorization)); ok {
+				name, err := s.Tokens.Authenticate(token)
+				if err != nil {
+					return errlst.Write(c, errlst.NewUnAuthorizedError(err.Error()))
+				}
+				actor = name
+			} else if !isReadMethod(request.Method) {
+				return errlst.Write(c, errlst.NewUnAuthorizedError(auth.ErrInvalidToken.Error()))
+			}
+		} else {
+			actor = request.Header.Get(HeaderActor)
+		}
+
+		if actor != "" {
+			c.SetRequest(request.WithContext(auth.WithActor(request.Context(), actor)))
+		}
+
+		return next(c)
+	}
+}
+
+// authorizedMiddleware guards admin routes, with tokens configured every request,
+// reads included, needs valid bearer token. Without tokens every request passes
+// as on other routes.
+func (s *Server) authorizedMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if s.Tokens.Enabled() && auth.ActorFromContext(c.Request().Context()) == "" {
+			return errlst.Write(c, errlst.NewUnAuthorizedError(auth.ErrInvalidToken.Error()))
+		}
+
+		return next(c)
+	}
+}
+
+// isReadMethod reports whether method doesn't change state
+func isReadMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// primaryMiddleware sends reads of request to primary database when request must
+// see recent writes which replica may not have yet: writes themselves, conditional
+// requests comparing ETag with current version and requests of client whose last
+// write, remembered in cookie, was less than ReadYourWritesWindow ago.
+func (s *Server) primaryMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	window := s.Cfg.Postgres.ReadYourWritesWindow
+
+	return func(c echo.Context) error {
+		request := c.Request()
+		now := time.Now()
+
+		if !isReadMethod(request.Method) {
+			c.SetCookie(&http.Cookie{
+				Name:     CookieLastWrite,
+				Value:    strconv.FormatInt(now.UnixMilli(), 10),
+				Path:     "/",
+				MaxAge:   int(window.Seconds()) + 1,
+				HttpOnly: true,
+			})
+		}
+
+		if !isReadMethod(request.Method) || isConditional(request) || wroteSince(request, now.Add(-window)) {
+			c.SetRequest(request.WithContext(connection.WithPrimary(request.Context())))
+		}
+
+		return next(c)
+	}
+}
+
+// isConditional reports whether request has precondition headers
+func isConditional(request *http.Request) bool {
+	for _, header := range []string{"If-Match", "If-None-Match", "If-Modified-Since", "If-Unmodified-Since"} {
+		if request.Header.Get(header) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// wroteSince reports whether last write cookie of request is after t
+func wroteSince(request *http.Request, t time.Time) bool {
+	cookie, err := request.Cookie(CookieLastWrite)
+	if err != nil {
+		return false
+	}
+
+	millis, err := strconv.ParseInt(cookie.Value, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return time.UnixMilli(millis).After(t)
+}
+
+// signedMiddleware guards routes players open without token, e.g. audio stream.
+// With tokens configured request needs bearer token or valid signature of its path,
+// without tokens every request passes as on other routes.
+func (s *Server) signedMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	signer := urlsign.New([]byte(s.Cfg.Storage.URLSecret))
+
+	return func(c echo.Context) error {
+		if !s.Tokens.Enabled() || auth.ActorFromContext(c.Request().Context()) != "" {
+			return next(c)
+		}
+
+		err := signer.Verify(c.Request().URL.Path, c.QueryParams(), time.Now())
+		switch {
+		case err == nil:
+			return next(c)
+		case errors.Is(err, urlsign.ErrMissingSignature):
+			return errlst.Write(c, errlst.NewUnAuthorizedError(auth.ErrInvalidToken.Error()))
+		default:
+			return errlst.Write(c, errlst.NewForbiddenError(err.Error()))
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jumayevgadam/music-app/internal/config"
+	"github.com/jumayevgadam/music-app/internal/connection"
+	"github.com/jumayevgadam/music-app/pkg/auth"
+	"github.com/jumayevgadam/music-app/pkg/urlsign"
+	"github.com/labstack/echo/v4"
+)
+
+const testStreamPath = "/api/v1/song/7/audio/stream"
+
+// newTestServer returns server with URL secret and given tokens
+func newTestServer(t *testing.T, tokens ...string) *Server {
+	t.Helper()
+
+	tokenStore, err := auth.NewTokenStore(tokens)
+	if err != nil {
+		t.Fatalf("NewTokenStore: %v", err)
+	}
+
+	return &Server{
+		Cfg:    &config.Config{Storage: config.Storage{URLSecret: "s3cret"}},
+		Tokens: tokenStore,
+	}
+}
+
+// stream requests target through actor and signed middlewares
+func stream(s *Server, target, authorization string) int {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if authorization != "" {
+		req.Header.Set(echo.HeaderAuthorization, authorization)
+	}
+	rec := httptest.NewRecorder()
+
+	handler := s.actorMiddleware(s.signedMiddleware(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}))
+	if err := handler(echo.New().NewContext(req, rec)); err != nil {
+		return -1
+	}
+
+	return rec.Code
+}
+
+// signedTarget returns stream path signed with secret till expires
+func signedTarget(secret string, expires time.Time) string {
+	return testStreamPath + "?" + urlsign.New([]byte(secret)).Sign(testStreamPath, expires).Encode()
+}
+
+func TestSignedMiddleware(t *testing.T) {
+	s := newTestServer(t, "editor:t0ken")
+	valid := signedTarget("s3cret", time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name          string
+		target        string
+		authorization string
+		status        int
+	}{
+		{name: "signed", target: valid, status: http.StatusOK},
+		{name: "bearer token", target: testStreamPath, authorization: "Bearer t0ken", status: http.StatusOK},
+		{name: "unsigned", target: testStreamPath, status: http.StatusUnauthorized},
+		{name: "unknown token", target: valid, authorization: "Bearer other", status: http.StatusUnauthorized},
+		{name: "expired", target: signedTarget("s3cret", time.Now().Add(-time.Second)), status: http.StatusForbidden},
+		{name: "other secret", target: signedTarget("other", time.Now().Add(time.Hour)), status: http.StatusForbidden},
+		{name: "other path", target: "/api/v1/song/8/audio/stream?" + valid[len(testStreamPath)+1:], status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := stream(s, tt.target, tt.authorization); status != tt.status {
+				t.Fatalf("GET %s = %d, want %d", tt.target, status, tt.status)
+			}
+		})
+	}
+}
+
+func TestSignedMiddlewareWithoutTokens(t *testing.T) {
+	// without tokens stream is open as every other route
+	if status := stream(newTestServer(t), testStreamPath, ""); status != http.StatusOK {
+		t.Fatalf("GET %s = %d, want 200", testStreamPath, status)
+	}
+}
+
+func TestPrimaryMiddleware(t *testing.T) {
+	s := &Server{Cfg: &config.Config{Postgres: config.Postgres{ReadYourWritesWindow: 5 * time.Second}}}
+	lastWrite := func(ago time.Duration) *http.Cookie {
+		return &http.Cookie{Name: CookieLastWrite, Value: strconv.FormatInt(time.Now().Add(-ago).UnixMilli(), 10)}
+	}
+
+	tests := []struct {
+		name      string
+		method    string
+		header    string
+		cookie    *http.Cookie
+		primary   bool
+		setCookie bool
+	}{
+		{name: "read", method: http.MethodGet},
+		{name: "write", method: http.MethodPut, primary: true, setCookie: true},
+		{name: "delete", method: http.MethodDelete, primary: true, setCookie: true},
+		{name: "if-none-match", method: http.MethodGet, header: "If-None-Match", primary: true},
+		{name: "if-modified-since", method: http.MethodHead, header: "If-Modified-Since", primary: true},
+		{name: "read after write", method: http.MethodGet, cookie: lastWrite(time.Second), primary: true},
+		{name: "read long after write", method: http.MethodGet, cookie: lastWrite(time.Minute)},
+		{name: "malformed cookie", method: http.MethodGet, cookie: &http.Cookie{Name: CookieLastWrite, Value: "yesterday"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/song/7", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, `"3"`)
+			}
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			rec := httptest.NewRecorder()
+
+			var primary bool
+			handler := s.primaryMiddleware(func(c echo.Context) error {
+				primary = connection.UsesPrimary(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+			if err := handler(echo.New().NewContext(req, rec)); err != nil {
+				t.Fatalf("handler: %v", err)
+			}
+
+			if primary != tt.primary {
+				t.Fatalf("uses primary = %v, want %v", primary, tt.primary)
+			}
+			if setCookie := rec.Header().Get("Set-Cookie") != ""; setCookie != tt.setCookie {
+				t.Fatalf("sets cookie = %v, want %v", setCookie, tt.setCookie)
+			}
+		})
+	}
+}
+
+func TestAuthorizedMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		tokens        []string
+		method        string
+		authorization string
+		status        int
+	}{
+		{name: "read without token", tokens: []string{"editor:t0ken"}, method: http.MethodGet, status: http.StatusUnauthorized},
+		{name: "write without token", tokens: []string{"editor:t0ken"}, method: http.MethodPost, status: http.StatusUnauthorized},
+		{name: "read with token", tokens: []string{"editor:t0ken"}, method: http.MethodGet, authorization: "Bearer t0ken", status: http.StatusOK},
+		{name: "unknown token", tokens: []string{"editor:t0ken"}, method: http.MethodGet, authorization: "Bearer other", status: http.StatusUnauthorized},
+		{name: "tokens disabled", method: http.MethodGet, status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, tt.tokens...)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/webhook/subscriptions", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			handler := s.actorMiddleware(s.authorizedMiddleware(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}))
+			if err := handler(echo.New().NewContext(req, rec)); err != nil {
+				t.Fatalf("handler: %v", err)
+			}
+
+			if rec.Code != tt.status {
+				t.Fatalf("%s = %d, want %d", tt.method, rec.Code, tt.status)
+			}
+		})
+	}
+}
@@ -1,26 +1,54 @@
 package server
 
 import (
+	"context"
+
 	"github.com/jumayevgadam/music-app/internal/config"
 	"github.com/jumayevgadam/music-app/internal/database"
+	idempotency "github.com/jumayevgadam/music-app/internal/idempotency/middleware"
+	"github.com/jumayevgadam/music-app/internal/music/enricher"
+	"github.com/jumayevgadam/music-app/internal/music/linkcheck"
+	"github.com/jumayevgadam/music-app/internal/music/playrollup"
+	"github.com/jumayevgadam/music-app/internal/webhook/dispatcher"
+	"github.com/jumayevgadam/music-app/pkg/auth"
+	"github.com/jumayevgadam/music-app/pkg/blobstore"
 	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 )
 
 // Server struct keeps all needed configurations for project
 type Server struct {
-	Echo      *echo.Echo
-	Cfg       *config.Config
-	DataStore database.DataStore
+	Echo        *echo.Echo
+	Cfg         *config.Config
+	DataStore   database.DataStore
+	Tokens      *auth.TokenStore
+	Idempotency *idempotency.Middleware
+	Blobs       blobstore.BlobStore
 }
 
 // NewServer is
 func NewServer(cfg *config.Config, dataStore database.DataStore) *Server {
+	errlst.SetFormat(errlst.Format(cfg.Server.ErrorFormat))
+	pagination.SetCursorSecret([]byte(cfg.Pagination.CursorSecret))
+
 	server := &Server{
-		Echo:      echo.New(),
-		Cfg:       cfg,
-		DataStore: dataStore,
+		Echo:        echo.New(),
+		Cfg:         cfg,
+		DataStore:   dataStore,
+		Idempotency: idempotency.NewMiddleware(dataStore, cfg.Idempotency),
+	}
+
+	// errors returned from handlers and echo itself (e.g. unknown route) use same format
+	server.Echo.HTTPErrorHandler = func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		if err := errlst.Write(c, err); err != nil {
+			logrus.Errorf("[server][HTTPErrorHandler]: %v", err)
+		}
 	}
 
 	return server
@@ -28,12 +56,47 @@ func NewServer(cfg *config.Config, dataStore database.DataStore) *Server {
 
 // Run the application
 func (s *Server) Run() error {
+	tokens, err := auth.NewTokenStore(s.Cfg.Auth.Tokens)
+	if err != nil {
+		return err
+	}
+	if !tokens.Enabled() {
+		logrus.Warn("[server][Run]: AUTH_TOKENS is empty, authentication is disabled")
+	}
+	s.Tokens = tokens
+
+	blobs, err := blobstore.NewLocal(s.Cfg.Storage.Dir)
+	if err != nil {
+		return err
+	}
+	s.Blobs = blobs
+
 	// Call MapHandlers from here
 	if err := s.MapHandlers(s.Echo); err != nil {
 		logrus.Println("can not map handlers in Run method")
 		return errlst.ParseErrors(err)
 	}
 
+	// dispatcher delivers song events to webhooks till server stops
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go dispatcher.NewDispatcher(s.DataStore, s.Cfg.Webhook).Run(ctx)
+	go s.Idempotency.PurgeExpired(ctx)
+	// enricher classifies song links and fetches their metadata from providers
+	go enricher.NewEnricher(s.DataStore, enricher.NewOEmbedFetcher(s.Cfg.Links), s.Cfg.Links).Run(ctx)
+	go linkcheck.NewChecker(s.DataStore, s.Cfg.LinkCheck).Run(ctx)
+	go playrollup.NewRoller(s.DataStore, s.Cfg.Plays).Run(ctx)
+
+	// run grpc port, it stops together with http server
+	grpcServer := s.NewGRPCServer()
+	defer grpcServer.GracefulStop()
+
+	go func() {
+		if err := s.runGRPC(grpcServer); err != nil {
+			logrus.Errorf("[server][runGRPC]: %v", err)
+		}
+	}()
+
 	// run http port
 	return s.Echo.Start(":" + s.Cfg.Server.HttpPort)
 }
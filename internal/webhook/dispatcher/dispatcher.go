@@ -0,0 +1,242 @@
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jumayevgadam/music-app/internal/config"
+	"github.com/jumayevgadam/music-app/internal/database"
+	webhookModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Dispatcher polls outbox, turns new events into deliveries for subscriptions
+// and POSTs due deliveries. Deliveries are claimed with SKIP LOCKED, so several
+// app instances can run dispatchers on the same database. Delivery is at least
+// once, consumers deduplicate by X-Webhook-Id.
+
+const (
+	// HeaderID is id of event, same for every delivery of it
+	HeaderID = "X-Webhook-Id"
+	// HeaderDelivery is id of delivery
+	HeaderDelivery = "X-Webhook-Delivery"
+	// HeaderEvent is type of event
+	HeaderEvent = "X-Webhook-Event"
+	// HeaderTimestamp is unix time request was signed at
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderSignature is "sha256=" + hex HMAC of "<timestamp>.<body>" with subscription secret
+	HeaderSignature = "X-Webhook-Signature"
+
+	// maxErrorBody is how much of failed response body is kept in last_error
+	maxErrorBody = 512
+)
+
+// Dispatcher struct is
+type Dispatcher struct {
+	dataStore database.DataStore
+	client    *http.Client
+	cfg       config.Webhook
+}
+
+// NewDispatcher method is
+func NewDispatcher(dataStore database.DataStore, cfg config.Webhook) *Dispatcher {
+	return &Dispatcher{
+		dataStore: dataStore,
+		client:    &http.Client{Timeout: cfg.Timeout},
+		cfg:       cfg,
+	}
+}
+
+// envelope is body POSTed to subscribers
+type envelope struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Run dispatches events till ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick fans out new events and sends due deliveries, full batches are repeated
+// right away so backlog doesn't wait for next tick
+func (d *Dispatcher) tick(ctx context.Context) {
+	for ctx.Err() == nil {
+		dispatched, err := d.dataStore.WebhookRepo().FanOutEvents(ctx, d.cfg.BatchSize)
+		if err != nil {
+			logrus.Errorf("[dispatcher][FanOutEvents]: %v", err)
+			return
+		}
+		if dispatched < int64(d.cfg.BatchSize) {
+			break
+		}
+	}
+
+	for ctx.Err() == nil {
+		var jobs []*webhookModel.DeliveryJobDAO
+
+		// lease outlives request timeout, so claimed delivery isn't taken twice
+		if err := d.dataStore.WithTransaction(ctx, func(db database.DataStore) error {
+			var err error
+			jobs, err = db.WebhookRepo().ClaimDeliveries(ctx, d.cfg.BatchSize, 2*d.cfg.Timeout)
+			return err
+		}); err != nil {
+			logrus.Errorf("[dispatcher][ClaimDeliveries]: %v", err)
+			return
+		}
+
+		d.deliverAll(ctx, jobs)
+		if len(jobs) < d.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// deliverAll sends jobs with bounded concurrency
+func (d *Dispatcher) deliverAll(ctx context.Context, jobs []*webhookModel.DeliveryJobDAO) {
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, d.cfg.Concurrency)
+	)
+
+	for _, job := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(job *webhookModel.DeliveryJobDAO) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			result := d.deliver(ctx, job)
+
+			// attempt is recorded even when dispatcher is stopping
+			if err := d.dataStore.WebhookRepo().SaveDeliveryResult(context.WithoutCancel(ctx), result); err != nil {
+				logrus.Errorf("[dispatcher][SaveDeliveryResult]: delivery %d: %v", job.ID, err)
+			}
+		}(job)
+	}
+
+	wg.Wait()
+}
+
+// deliver POSTs signed event and decides what happens to delivery next
+func (d *Dispatcher) deliver(ctx context.Context, job *webhookModel.DeliveryJobDAO) *webhookModel.DeliveryResultDAO {
+	statusCode, err := d.send(ctx, job)
+	if err == nil {
+		return &webhookModel.DeliveryResultDAO{
+			ID:            job.ID,
+			Status:        webhookModel.DeliverySucceeded,
+			NextAttemptAt: time.Now(),
+			StatusCode:    statusCode,
+		}
+	}
+
+	message := err.Error()
+	result := &webhookModel.DeliveryResultDAO{
+		ID:         job.ID,
+		Status:     webhookModel.DeliveryPending,
+		StatusCode: statusCode,
+		Error:      &message,
+	}
+
+	if job.Attempts >= d.cfg.MaxAttempts {
+		logrus.Warnf("[dispatcher][deliver]: delivery %d is dead after %d attempts: %v", job.ID, job.Attempts, err)
+		result.Status = webhookModel.DeliveryDead
+		result.NextAttemptAt = time.Now()
+		return result
+	}
+
+	result.NextAttemptAt = time.Now().Add(d.backoff(job.Attempts))
+	return result
+}
+
+// send returns status code of response when there was one
+func (d *Dispatcher) send(ctx context.Context, job *webhookModel.DeliveryJobDAO) (*int, error) {
+	body, err := json.Marshal(envelope{
+		ID:        job.EventID,
+		Type:      job.EventType,
+		CreatedAt: job.EventCreatedAt,
+		Data:      job.Payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't encode event: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "music-app-webhooks/1")
+	req.Header.Set(HeaderID, strconv.FormatInt(job.EventID, 10))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(job.ID, 10))
+	req.Header.Set(HeaderEvent, job.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(job.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	statusCode := resp.StatusCode
+	if statusCode >= 200 && statusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return &statusCode, nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return &statusCode, fmt.Errorf("unexpected status %d: %s", statusCode, bytes.TrimSpace(snippet))
+}
+
+// backoff doubles delay with every attempt up to MaxBackoff, jitter spreads
+// retries of many deliveries failing at once
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.MinBackoff
+	for i := 1; i < attempts && delay < d.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, d.cfg.MaxBackoff)
+
+	return delay + rand.N(delay/10+1)
+}
+
+// Sign returns value of X-Webhook-Signature, subscribers compute the same
+// over received timestamp header and raw body to verify request
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
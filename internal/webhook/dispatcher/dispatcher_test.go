@@ -0,0 +1,167 @@
+package dispatcher
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jumayevgadam/music-app/internal/config"
+	webhookModel "github.com/jumayevgadam/music-app/internal/models"
+)
+
+var testConfig = config.Webhook{
+	Timeout:     time.Second,
+	MaxAttempts: 3,
+	MinBackoff:  30 * time.Second,
+	MaxBackoff:  5 * time.Minute,
+	BatchSize:   10,
+	Concurrency: 2,
+}
+
+func testJob(url string, attempts int) *webhookModel.DeliveryJobDAO {
+	return &webhookModel.DeliveryJobDAO{
+		ID:             11,
+		Attempts:       attempts,
+		URL:            url,
+		Secret:         "s3cret",
+		EventID:        5,
+		EventType:      "song.updated",
+		Payload:        json.RawMessage(`{"id":1}`),
+		EventCreatedAt: time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":5}`)
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("s3cret", 1700000000, body); got != want {
+		t.Fatalf("Sign = %s, want %s", got, want)
+	}
+
+	for name, got := range map[string]string{
+		"other secret":    Sign("other", 1700000000, body),
+		"other timestamp": Sign("s3cret", 1700000001, body),
+		"other body":      Sign("s3cret", 1700000000, []byte(`{"id":6}`)),
+	} {
+		if got == want {
+			t.Fatalf("%s gives same signature", name)
+		}
+	}
+}
+
+func TestDeliverSignsRequest(t *testing.T) {
+	var (
+		header http.Header
+		body   []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	result := NewDispatcher(nil, testConfig).deliver(context.Background(), testJob(server.URL, 1))
+
+	if result.Status != webhookModel.DeliverySucceeded || result.StatusCode == nil ||
+		*result.StatusCode != http.StatusNoContent || result.Error != nil {
+		t.Fatalf("result = %+v", result)
+	}
+
+	timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("bad %s header: %v", HeaderTimestamp, err)
+	}
+	if got, want := header.Get(HeaderSignature), Sign("s3cret", timestamp, body); got != want {
+		t.Fatalf("signature = %s, want %s", got, want)
+	}
+	if header.Get(HeaderID) != "5" || header.Get(HeaderDelivery) != "11" || header.Get(HeaderEvent) != "song.updated" {
+		t.Fatalf("headers = %v", header)
+	}
+
+	var got envelope
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if got.ID != 5 || got.Type != "song.updated" || string(got.Data) != `{"id":1}` {
+		t.Fatalf("envelope = %+v", got)
+	}
+}
+
+func TestDeliverRetriesAndDies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = io.WriteString(w, "upstream down"+strings.Repeat("!", 2*maxErrorBody))
+	}))
+	defer server.Close()
+
+	d := NewDispatcher(nil, testConfig)
+
+	// attempts left, delivery waits for backoff
+	before := time.Now()
+	result := d.deliver(context.Background(), testJob(server.URL, 2))
+
+	if result.Status != webhookModel.DeliveryPending || result.StatusCode == nil || *result.StatusCode != http.StatusBadGateway {
+		t.Fatalf("result = %+v", result)
+	}
+	if result.Error == nil || !strings.Contains(*result.Error, "upstream down") || len(*result.Error) > maxErrorBody+64 {
+		t.Fatalf("error = %v, want truncated response body", result.Error)
+	}
+	if delay := result.NextAttemptAt.Sub(before); delay < 2*testConfig.MinBackoff || delay > 3*testConfig.MinBackoff {
+		t.Fatalf("next attempt in %v, want about %v", delay, 2*testConfig.MinBackoff)
+	}
+
+	// last attempt failed, delivery is dead
+	result = d.deliver(context.Background(), testJob(server.URL, testConfig.MaxAttempts))
+	if result.Status != webhookModel.DeliveryDead || result.Error == nil {
+		t.Fatalf("result = %+v, want dead delivery", result)
+	}
+}
+
+func TestDeliverNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	result := NewDispatcher(nil, testConfig).deliver(context.Background(), testJob(server.URL, 1))
+
+	if result.Status != webhookModel.DeliveryPending || result.StatusCode != nil || result.Error == nil {
+		t.Fatalf("result = %+v, want pending delivery without status code", result)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := NewDispatcher(nil, testConfig)
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 4, want: 4 * time.Minute},
+		{attempts: 5, want: 5 * time.Minute},
+		{attempts: 50, want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			// jitter adds at most tenth of delay
+			if got := d.backoff(tt.attempts); got < tt.want || got > tt.want+tt.want/10 {
+				t.Fatalf("backoff(%d) = %v, want %v plus jitter", tt.attempts, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package webhook
+
+import "github.com/labstack/echo/v4"
+
+// write needed methods for Handler layer
+
+// Handler interface is
+type Handler interface {
+	AddSubscription() echo.HandlerFunc
+	ListSubscriptions() echo.HandlerFunc
+	DeleteSubscription() echo.HandlerFunc
+	ListDeliveries() echo.HandlerFunc
+	ReplayDelivery() echo.HandlerFunc
+	ReplayDeadDeliveries() echo.HandlerFunc
+}
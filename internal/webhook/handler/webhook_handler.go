@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	webhookModel "github.com/jumayevgadam/music-app/internal/models"
+	webhookOps "github.com/jumayevgadam/music-app/internal/webhook"
+	httpError "github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+	"github.com/jumayevgadam/music-app/pkg/reqvalidator"
+	"github.com/jumayevgadam/music-app/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+)
+
+// WebhookHandler struct is
+type WebhookHandler struct {
+	service webhookOps.Service
+}
+
+// NewWebhookHandler method is
+func NewWebhookHandler(service webhookOps.Service) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// AddSubscription handler is, empty event_types subscribes to every event
+func (wh *WebhookHandler) AddSubscription() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[WebhookHandler][AddSubscription]")
+		ctx, span := tracer.Start(c.Request().Context(), "[WebhookHandler][AddSubscription]")
+		defer span.End()
+
+		var subscriptionRequest webhookModel.WebhookSubscriptionDTO
+		if err := reqvalidator.ReadRequest(c, &subscriptionRequest); err != nil {
+			tracing.EventErrorTracer(span, err, "[WebhookHandler][AddSubscription]")
+			return httpError.Write(c, err)
+		}
+
+		subscription, err := wh.service.AddSubscription(ctx, &subscriptionRequest)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[WebhookHandler][AddSubscription]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusCreated, subscription)
+	}
+}
+
+// ListSubscriptions handler is
+func (wh *WebhookHandler) ListSubscriptions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[WebhookHandler][ListSubscriptions]")
+		ctx, span := tracer.Start(c.Request().Context(), "[WebhookHandler][ListSubscriptions]")
+		defer span.End()
+
+		subscriptions, err := wh.service.ListSubscriptions(ctx)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[WebhookHandler][ListSubscriptions]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, subscriptions)
+	}
+}
+
+// DeleteSubscription handler is
+func (wh *WebhookHandler) DeleteSubscription() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[WebhookHandler][DeleteSubscription]")
+		ctx, span := tracer.Start(c.Request().Context(), "[WebhookHandler][DeleteSubscription]")
+		defer span.End()
+
+		subscriptionID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[WebhookHandler][DeleteSubscription]")
+			return httpError.Write(c, err)
+		}
+
+		if err := wh.service.DeleteSubscription(ctx, subscriptionID); err != nil {
+			tracing.EventErrorTracer(span, err, "[WebhookHandler][DeleteSubscription]")
+			return httpError.Write(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// ListDeliveries handler is, filtered by subscription_id and status query params
+func (wh *WebhookHandler) ListDeliveries() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[WebhookHandler][ListDeliveries]")
+		ctx, span := tracer.Start(c.Request().Context(), "[WebhookHandler][ListDeliveries]")
+		defer span.End()
+
+		paginationQuery, err := pagination.GetPaginationFromCtx(c)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[WebhookHandler][ListDeliveries]")
+			return httpError.Write(c, err)
+		}
+		if paginationQuery.IsCursorMode() {
+			return httpError.Write(c, httpError.NewBadQueryParamsError("deliveries are paginated by page"))
+		}
+
+		var deliveryFilter webhookModel.DeliveryFilterDAO
+		if raw := c.QueryParam("subscription_id"); raw != "" {
+			if deliveryFilter.SubscriptionID, err = strconv.Atoi(raw); err != nil {
+				tracing.EventErrorTracer(span, err, "[WebhookHandler][ListDeliveries]")
+				return httpError.Write(c, httpError.NewBadQueryParamsError("subscription_id must be a number"))
+			}
+		}
+
+		switch status := c.QueryParam("status"); status {
+		case "", webhookModel.DeliveryPending, webhookModel.DeliverySucceeded, webhookModel.DeliveryDead:
+			deliveryFilter.Status = status
+		default:
+			return httpError.Write(c, httpError.NewBadQueryParamsError("status must be one of pending, succeeded, dead"))
+		}
+
+		deliveries, err := wh.service.ListDeliveries(ctx, deliveryFilter, paginationQuery)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[WebhookHandler][ListDeliveries]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusOK, deliveries)
+	}
+}
+
+// ReplayDelivery handler is
+func (wh *WebhookHandler) ReplayDelivery() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[WebhookHandler][ReplayDelivery]")
+		ctx, span := tracer.Start(c.Request().Context(), "[WebhookHandler][ReplayDelivery]")
+		defer span.End()
+
+		deliveryID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[WebhookHandler][ReplayDelivery]")
+			return httpError.Write(c, err)
+		}
+
+		if err := wh.service.ReplayDelivery(ctx, deliveryID); err != nil {
+			tracing.EventErrorTracer(span, err, "[WebhookHandler][ReplayDelivery]")
+			return httpError.Write(c, err)
+		}
+
+		return c.NoContent(http.StatusAccepted)
+	}
+}
+
+// ReplayDeadDeliveries handler is, it responds with count of scheduled deliveries
+func (wh *WebhookHandler) ReplayDeadDeliveries() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tracer := otel.Tracer("[WebhookHandler][ReplayDeadDeliveries]")
+		ctx, span := tracer.Start(c.Request().Context(), "[WebhookHandler][ReplayDeadDeliveries]")
+		defer span.End()
+
+		subscriptionID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[WebhookHandler][ReplayDeadDeliveries]")
+			return httpError.Write(c, err)
+		}
+
+		replayed, err := wh.service.ReplayDeadDeliveries(ctx, subscriptionID)
+		if err != nil {
+			tracing.EventErrorTracer(span, err, "[WebhookHandler][ReplayDeadDeliveries]")
+			return httpError.Write(c, err)
+		}
+
+		return c.JSON(http.StatusAccepted, map[string]int64{"replayed": replayed})
+	}
+}
@@ -0,0 +1,26 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	webhookModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+)
+
+// write needed methods for repository layer
+
+// Repository is
+type Repository interface {
+	AddOutboxEvents(ctx context.Context, events []*webhookModel.OutboxEventDAO) error
+	FanOutEvents(ctx context.Context, limit int) (int64, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*webhookModel.DeliveryJobDAO, error)
+	SaveDeliveryResult(ctx context.Context, result *webhookModel.DeliveryResultDAO) error
+	AddSubscription(ctx context.Context, daoModel *webhookModel.WebhookSubscriptionDAO) (*webhookModel.WebhookSubscriptionDAO, error)
+	ListSubscriptions(ctx context.Context) ([]*webhookModel.WebhookSubscriptionDAO, error)
+	DeleteSubscription(ctx context.Context, subscriptionID int) error
+	CountDeliveries(ctx context.Context, deliveryFilter webhookModel.DeliveryFilterDAO) (int, error)
+	ListDeliveries(ctx context.Context, deliveryFilter webhookModel.DeliveryFilterDAO, paginationQuery *pagination.PaginationQuery) ([]*webhookModel.WebhookDeliveryDAO, error)
+	ReplayDelivery(ctx context.Context, deliveryID int64) error
+	ReplayDeadDeliveries(ctx context.Context, subscriptionID int) (int64, error)
+}
@@ -0,0 +1,116 @@
+package repository
+
+// SQL Queries for outbox events and webhooks
+const (
+	// addOutboxEventsQuery inserts events given as arrays of types, song ids and JSON payloads
+	addOutboxEventsQuery = `
+		INSERT INTO outbox_events (event_type, aggregate_id, payload)
+		SELECT e.event_type, e.aggregate_id, e.payload::jsonb
+		FROM unnest($1::text[], $2::int[], $3::text[]) AS e(event_type, aggregate_id, payload);
+	`
+
+	// fanOutEventsQuery creates delivery of every undispatched event for every
+	// active subscription interested in it and marks events as dispatched
+	fanOutEventsQuery = `
+		WITH events AS (
+			SELECT id, event_type
+			FROM outbox_events
+			WHERE dispatched_at IS NULL
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		), deliveries AS (
+			INSERT INTO webhook_deliveries (subscription_id, event_id)
+			SELECT s.id, events.id
+			FROM events
+			JOIN webhook_subscriptions AS s
+				ON s.active AND (cardinality(s.event_types) = 0 OR events.event_type = ANY(s.event_types))
+			ON CONFLICT (subscription_id, event_id) DO NOTHING
+		)
+		UPDATE outbox_events
+		SET dispatched_at = now()
+		WHERE id IN (SELECT id FROM events);
+	`
+
+	// claimDeliveriesQuery takes due deliveries and moves their next attempt by lease ($2 seconds),
+	// so other dispatchers skip them while they are sent
+	claimDeliveriesQuery = `
+		UPDATE webhook_deliveries AS d
+		SET attempts = d.attempts + 1, next_attempt_at = now() + make_interval(secs => $2), updated_at = now()
+		FROM webhook_subscriptions AS s, outbox_events AS e
+		WHERE d.id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) AND s.id = d.subscription_id AND e.id = d.event_id
+		RETURNING d.id, d.attempts, s.url, s.secret, e.id AS event_id, e.event_type, e.payload, e.created_at AS event_created_at;
+	`
+
+	// saveDeliveryResultQuery is
+	saveDeliveryResultQuery = `
+		UPDATE webhook_deliveries
+		SET status = $2, next_attempt_at = $3, last_status_code = $4, last_error = $5, updated_at = now()
+		WHERE id = $1;
+	`
+
+	// addSubscriptionQuery is
+	addSubscriptionQuery = `
+		INSERT INTO webhook_subscriptions (url, secret, event_types)
+		VALUES ($1, $2, $3)
+		RETURNING id, url, secret, event_types, active, created_at, updated_at;
+	`
+
+	// listSubscriptionsQuery is
+	listSubscriptionsQuery = `
+		SELECT id, url, secret, event_types, active, created_at, updated_at
+		FROM webhook_subscriptions
+		ORDER BY id;
+	`
+
+	// deleteSubscriptionQuery is, deliveries are removed by cascade
+	deleteSubscriptionQuery = `
+		DELETE FROM webhook_subscriptions
+		WHERE id = $1
+		RETURNING id;
+	`
+
+	// deliveriesCondition filters deliveries, $1 is subscription id and $2 is status, zero values match all
+	deliveriesCondition = `
+		WHERE ($1 = 0 OR d.subscription_id = $1) AND ($2 = '' OR d.status = $2)
+	`
+
+	// countDeliveriesQuery is
+	countDeliveriesQuery = `
+		SELECT COUNT(*)
+		FROM webhook_deliveries AS d
+	` + deliveriesCondition
+
+	// listDeliveriesQuery is
+	listDeliveriesQuery = `
+		SELECT d.id, d.subscription_id, d.event_id, e.event_type, d.status, d.attempts, d.next_attempt_at,
+			d.last_status_code, d.last_error, d.created_at, d.updated_at
+		FROM webhook_deliveries AS d
+		JOIN outbox_events AS e ON e.id = d.event_id
+	` + deliveriesCondition + `
+		ORDER BY d.id DESC
+		LIMIT $3 OFFSET $4;
+	`
+
+	// replayDeliveryQuery schedules delivery again with fresh attempts
+	replayDeliveryQuery = `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = now(), updated_at = now()
+		WHERE id = $1
+		RETURNING id;
+	`
+
+	// replayDeadDeliveriesQuery schedules all dead deliveries of subscription again
+	replayDeadDeliveriesQuery = `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = now(), updated_at = now()
+		WHERE subscription_id = $1 AND status = 'dead';
+	`
+)
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jumayevgadam/music-app/internal/connection"
+	webhookModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+)
+
+// WebhookRepository struct is
+type WebhookRepository struct {
+	psqlDB connection.DB
+}
+
+// NewWebhookRepository method is
+func NewWebhookRepository(psqlDB connection.DB) *WebhookRepository {
+	return &WebhookRepository{psqlDB: psqlDB}
+}
+
+// AddOutboxEvents repo writes events with one statement, it should run in
+// transaction of the change events describe
+func (wr *WebhookRepository) AddOutboxEvents(ctx context.Context, events []*webhookModel.OutboxEventDAO) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	eventTypes := make([]string, 0, len(events))
+	aggregateIDs := make([]int, 0, len(events))
+	payloads := make([]string, 0, len(events))
+	for _, event := range events {
+		eventTypes = append(eventTypes, event.EventType)
+		aggregateIDs = append(aggregateIDs, event.AggregateID)
+		payloads = append(payloads, string(event.Payload))
+	}
+
+	if _, err := wr.psqlDB.Exec(ctx, addOutboxEventsQuery, eventTypes, aggregateIDs, payloads); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// FanOutEvents repo turns up to limit undispatched events into deliveries,
+// returns count of dispatched events
+func (wr *WebhookRepository) FanOutEvents(ctx context.Context, limit int) (int64, error) {
+	tag, err := wr.psqlDB.Exec(ctx, fanOutEventsQuery, limit)
+	if err != nil {
+		return -1, errlst.FromPostgres(err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ClaimDeliveries repo takes due deliveries for lease, statement writes so it runs on primary
+func (wr *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*webhookModel.DeliveryJobDAO, error) {
+	var jobs []*webhookModel.DeliveryJobDAO
+
+	if err := wr.psqlDB.Select(ctx, wr.psqlDB.Primary(), &jobs, claimDeliveriesQuery, limit, lease.Seconds()); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return jobs, nil
+}
+
+// SaveDeliveryResult repo is
+func (wr *WebhookRepository) SaveDeliveryResult(ctx context.Context, result *webhookModel.DeliveryResultDAO) error {
+	if _, err := wr.psqlDB.Exec(
+		ctx,
+		saveDeliveryResultQuery,
+		result.ID,
+		result.Status,
+		result.NextAttemptAt,
+		result.StatusCode,
+		result.Error,
+	); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// AddSubscription repo is
+func (wr *WebhookRepository) AddSubscription(ctx context.Context, daoModel *webhookModel.WebhookSubscriptionDAO) (*webhookModel.WebhookSubscriptionDAO, error) {
+	var subscription webhookModel.WebhookSubscriptionDAO
+
+	if err := wr.psqlDB.QueryRow(
+		ctx,
+		addSubscriptionQuery,
+		daoModel.URL,
+		daoModel.Secret,
+		daoModel.EventTypes,
+	).Scan(
+		&subscription.ID,
+		&subscription.URL,
+		&subscription.Secret,
+		&subscription.EventTypes,
+		&subscription.Active,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return &subscription, nil
+}
+
+// ListSubscriptions repo is
+func (wr *WebhookRepository) ListSubscriptions(ctx context.Context) ([]*webhookModel.WebhookSubscriptionDAO, error) {
+	var subscriptions []*webhookModel.WebhookSubscriptionDAO
+
+	if err := wr.psqlDB.Select(ctx, wr.psqlDB, &subscriptions, listSubscriptionsQuery); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteSubscription repo is
+func (wr *WebhookRepository) DeleteSubscription(ctx context.Context, subscriptionID int) error {
+	if err := wr.psqlDB.QueryRow(ctx, deleteSubscriptionQuery, subscriptionID).Scan(&subscriptionID); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// CountDeliveries repo is
+func (wr *WebhookRepository) CountDeliveries(ctx context.Context, deliveryFilter webhookModel.DeliveryFilterDAO) (int, error) {
+	var totalCount int
+
+	if err := wr.psqlDB.Get(
+		ctx,
+		wr.psqlDB,
+		&totalCount,
+		countDeliveriesQuery,
+		deliveryFilter.SubscriptionID,
+		deliveryFilter.Status,
+	); err != nil {
+		return -1, errlst.FromPostgres(err)
+	}
+
+	return totalCount, nil
+}
+
+// ListDeliveries repo returns newest deliveries first
+func (wr *WebhookRepository) ListDeliveries(
+	ctx context.Context,
+	deliveryFilter webhookModel.DeliveryFilterDAO,
+	paginationQuery *pagination.PaginationQuery,
+) ([]*webhookModel.WebhookDeliveryDAO, error) {
+	var deliveries []*webhookModel.WebhookDeliveryDAO
+
+	if err := wr.psqlDB.Select(
+		ctx,
+		wr.psqlDB,
+		&deliveries,
+		listDeliveriesQuery,
+		deliveryFilter.SubscriptionID,
+		deliveryFilter.Status,
+		paginationQuery.GetLimit(),
+		paginationQuery.GetOffset(),
+	); err != nil {
+		return nil, errlst.FromPostgres(err)
+	}
+
+	return deliveries, nil
+}
+
+// ReplayDelivery repo is
+func (wr *WebhookRepository) ReplayDelivery(ctx context.Context, deliveryID int64) error {
+	if err := wr.psqlDB.QueryRow(ctx, replayDeliveryQuery, deliveryID).Scan(&deliveryID); err != nil {
+		return errlst.FromPostgres(err)
+	}
+
+	return nil
+}
+
+// ReplayDeadDeliveries repo returns count of scheduled deliveries
+func (wr *WebhookRepository) ReplayDeadDeliveries(ctx context.Context, subscriptionID int) (int64, error) {
+	tag, err := wr.psqlDB.Exec(ctx, replayDeadDeliveriesQuery, subscriptionID)
+	if err != nil {
+		return -1, errlst.FromPostgres(err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/jumayevgadam/music-app/internal/database"
+	"github.com/jumayevgadam/music-app/internal/webhook/handler"
+	"github.com/jumayevgadam/music-app/internal/webhook/service"
+	"github.com/labstack/echo/v4"
+)
+
+// We use in routes package needed http routes for webhook subscriptions and deliveries
+
+// Routes is, every webhook endpoint, reads included, goes through authorized middleware
+// because subscriptions and deliveries expose target URLs and payloads
+func Routes(e *echo.Group, dataStore database.DataStore, authorized echo.MiddlewareFunc) {
+	// init Service
+	Service := service.NewWebhookService(dataStore)
+	// init Handler
+	Handler := handler.NewWebhookHandler(Service)
+
+	// init main group for webhooks
+	webhookGroup := e.Group("/webhook", authorized)
+
+	// Endpoints are
+	{
+		webhookGroup.POST("/subscriptions", Handler.AddSubscription())
+		webhookGroup.GET("/subscriptions", Handler.ListSubscriptions())
+		webhookGroup.DELETE("/subscriptions/:id", Handler.DeleteSubscription())
+		webhookGroup.POST("/subscriptions/:id/replay", Handler.ReplayDeadDeliveries())
+		webhookGroup.GET("/deliveries", Handler.ListDeliveries())
+		webhookGroup.POST("/deliveries/:id/replay", Handler.ReplayDelivery())
+	}
+}
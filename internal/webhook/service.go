@@ -0,0 +1,20 @@
+package webhook
+
+import (
+	"context"
+
+	webhookModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+)
+
+// write needed methods for service layer
+
+// Service is
+type Service interface {
+	AddSubscription(ctx context.Context, dtoModel *webhookModel.WebhookSubscriptionDTO) (*webhookModel.WebhookSubscriptionDTO, error)
+	ListSubscriptions(ctx context.Context) ([]*webhookModel.WebhookSubscriptionDTO, error)
+	DeleteSubscription(ctx context.Context, subscriptionID int) error
+	ListDeliveries(ctx context.Context, deliveryFilter webhookModel.DeliveryFilterDAO, paginationQuery *pagination.PaginationQuery) (*webhookModel.WebhookDeliveryListDTO, error)
+	ReplayDelivery(ctx context.Context, deliveryID int64) error
+	ReplayDeadDeliveries(ctx context.Context, subscriptionID int) (int64, error)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/jumayevgadam/music-app/internal/database"
+	webhookModel "github.com/jumayevgadam/music-app/internal/models"
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/pagination"
+	"go.opentelemetry.io/otel"
+)
+
+// secretSize is count of random bytes in generated signing secret
+const secretSize = 32
+
+// WebhookService struct is, lookupHost resolves subscription hosts
+type WebhookService struct {
+	repo       database.DataStore
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+}
+
+// NewWebhookService method is
+func NewWebhookService(repo database.DataStore) *WebhookService {
+	return &WebhookService{repo: repo, lookupHost: net.DefaultResolver.LookupHost}
+}
+
+// AddSubscription service generates signing secret when it isn't given,
+// returned subscription is the only place secret is shown
+func (s *WebhookService) AddSubscription(ctx context.Context, dtoModel *webhookModel.WebhookSubscriptionDTO) (*webhookModel.WebhookSubscriptionDTO, error) {
+	tracer := otel.Tracer("[AddSubscription][Service]")
+	ctx, span := tracer.Start(ctx, "AddSubscription")
+	defer span.End()
+
+	if err := s.checkTarget(ctx, dtoModel.URL); err != nil {
+		return nil, err
+	}
+
+	if dtoModel.Secret == "" {
+		secret := make([]byte, secretSize)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, errlst.NewDomainError(errlst.KindInternal, "can't generate webhook secret", err)
+		}
+		dtoModel.Secret = hex.EncodeToString(secret)
+	}
+	if dtoModel.EventTypes == nil {
+		dtoModel.EventTypes = []string{}
+	}
+
+	subscription, err := s.repo.WebhookRepo().AddSubscription(ctx, dtoModel.ToStorage())
+	if err != nil {
+		return nil, err
+	}
+
+	result := subscription.ToServer()
+	result.Secret = subscription.Secret
+
+	return result, nil
+}
+
+// checkTarget rejects subscription URLs which aren't http(s) or point to
+// loopback, link-local, private or otherwise internal addresses, so webhooks
+// can't be used to reach services behind the server
+func (s *WebhookService) checkTarget(ctx context.Context, rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return errlst.Validation("webhook url must be absolute http(s) url", errlst.ErrBadRequest)
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(target.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errlst.Validation("webhook url must not point to internal address", errlst.ErrBadRequest)
+	}
+
+	addrs := []string{host}
+	if _, err := netip.ParseAddr(host); err != nil {
+		if addrs, err = s.lookupHost(ctx, host); err != nil {
+			return errlst.Validation("host of webhook url can't be resolved", err)
+		}
+	}
+
+	for _, raw := range addrs {
+		addr, err := netip.ParseAddr(raw)
+		if err != nil || isInternalAddr(addr.Unmap()) {
+			return errlst.Validation("webhook url must not point to internal address", errlst.ErrBadRequest)
+		}
+	}
+
+	return nil
+}
+
+// isInternalAddr reports whether addr isn't public unicast address
+func isInternalAddr(addr netip.Addr) bool {
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() ||
+		sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace is carrier-grade NAT range (RFC 6598), it isn't reported by IsPrivate
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// ListSubscriptions service is
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]*webhookModel.WebhookSubscriptionDTO, error) {
+	tracer := otel.Tracer("[ListSubscriptions][Service]")
+	ctx, span := tracer.Start(ctx, "ListSubscriptions")
+	defer span.End()
+
+	subscriptions, err := s.repo.WebhookRepo().ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*webhookModel.WebhookSubscriptionDTO, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		result = append(result, subscription.ToServer())
+	}
+
+	return result, nil
+}
+
+// DeleteSubscription service is
+func (s *WebhookService) DeleteSubscription(ctx context.Context, subscriptionID int) error {
+	tracer := otel.Tracer("[DeleteSubscription][Service]")
+	ctx, span := tracer.Start(ctx, "DeleteSubscription")
+	defer span.End()
+
+	return s.repo.WebhookRepo().DeleteSubscription(ctx, subscriptionID)
+}
+
+// ListDeliveries service is
+func (s *WebhookService) ListDeliveries(
+	ctx context.Context,
+	deliveryFilter webhookModel.DeliveryFilterDAO,
+	paginationQuery *pagination.PaginationQuery,
+) (*webhookModel.WebhookDeliveryListDTO, error) {
+	tracer := otel.Tracer("[ListDeliveries][Service]")
+	ctx, span := tracer.Start(ctx, "ListDeliveries")
+	defer span.End()
+
+	totalCount, err := s.repo.WebhookRepo().CountDeliveries(ctx, deliveryFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.WebhookRepo().ListDeliveries(ctx, deliveryFilter, paginationQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &webhookModel.WebhookDeliveryListDTO{
+		Deliveries: make([]*webhookModel.WebhookDeliveryDTO, 0, len(deliveries)),
+		Page:       max(paginationQuery.GetPage(), 1),
+		Size:       paginationQuery.GetSize(),
+		TotalCount: totalCount,
+		TotalPages: pagination.GetTotalPages(totalCount, paginationQuery.GetSize()),
+	}
+	for _, delivery := range deliveries {
+		list.Deliveries = append(list.Deliveries, delivery.ToServer())
+	}
+
+	return list, nil
+}
+
+// ReplayDelivery service schedules delivery again, whatever its status is
+func (s *WebhookService) ReplayDelivery(ctx context.Context, deliveryID int64) error {
+	tracer := otel.Tracer("[ReplayDelivery][Service]")
+	ctx, span := tracer.Start(ctx, "ReplayDelivery")
+	defer span.End()
+
+	return s.repo.WebhookRepo().ReplayDelivery(ctx, deliveryID)
+}
+
+// ReplayDeadDeliveries service schedules dead deliveries of subscription again
+func (s *WebhookService) ReplayDeadDeliveries(ctx context.Context, subscriptionID int) (int64, error) {
+	tracer := otel.Tracer("[ReplayDeadDeliveries][Service]")
+	ctx, span := tracer.Start(ctx, "ReplayDeadDeliveries")
+	defer span.End()
+
+	return s.repo.WebhookRepo().ReplayDeadDeliveries(ctx, subscriptionID)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+)
+
+// fakeHosts resolves names from map, unknown names aren't found
+type fakeHosts map[string][]string
+
+func (h fakeHosts) lookup(ctx context.Context, host string) ([]string, error) {
+	if addrs, ok := h[host]; ok {
+		return addrs, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func TestCheckTarget(t *testing.T) {
+	s := &WebhookService{lookupHost: fakeHosts{
+		"hooks.example.com":    {"93.184.216.34", "2606:2800:220:1::1"},
+		"intranet.example.com": {"93.184.216.34", "10.0.0.8"},
+		"metadata.example.com": {"169.254.169.254"},
+	}.lookup}
+
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "https://hooks.example.com/songs"},
+		{url: "http://hooks.example.com:8080/songs"},
+		{url: "https://93.184.216.34/songs"},
+		{url: "ftp://hooks.example.com/songs", wantErr: true},
+		{url: "file:///etc/passwd", wantErr: true},
+		{url: "hooks.example.com/songs", wantErr: true},
+		{url: "http://localhost:6000/api", wantErr: true},
+		{url: "http://api.localhost/", wantErr: true},
+		{url: "http://127.0.0.1:6000/api", wantErr: true},
+		{url: "http://[::1]/", wantErr: true},
+		{url: "http://[::ffff:127.0.0.1]/", wantErr: true},
+		{url: "http://0.0.0.0/", wantErr: true},
+		{url: "http://10.1.2.3/", wantErr: true},
+		{url: "http://172.16.0.1/", wantErr: true},
+		{url: "http://192.168.1.1/", wantErr: true},
+		{url: "http://100.64.0.1/", wantErr: true},
+		{url: "http://[fd00::1]/", wantErr: true},
+		{url: "http://169.254.169.254/latest/meta-data", wantErr: true},
+		{url: "http://[fe80::1]/", wantErr: true},
+		{url: "http://metadata.example.com/", wantErr: true},
+		{url: "http://intranet.example.com/", wantErr: true},
+		{url: "http://unknown.example.com/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := s.checkTarget(context.Background(), tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkTarget(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if err != nil && errlst.KindOf(err) != errlst.KindValidation {
+				t.Fatalf("checkTarget(%q) kind = %v, want validation", tt.url, errlst.KindOf(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package audiotag
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// Package audiotag reads song metadata embedded in audio files, ID3v2 tags of
+// MP3 (and FLAC written by some taggers) and Vorbis comments of FLAC, Ogg Vorbis
+// and Opus. Only fields song needs are read, pictures and other frames are skipped.
+
+// Format is
+type Format string
+
+const (
+	// FormatID3v2 is
+	FormatID3v2 Format = "id3v2"
+	// FormatVorbis is
+	FormatVorbis Format = "vorbis"
+)
+
+var (
+	// ErrNoTags is returned when file has no supported tags
+	ErrNoTags = errors.New("audio file has no ID3v2 or Vorbis tags")
+	// ErrMalformed is returned when tags are found but can't be read
+	ErrMalformed = errors.New("malformed audio tags")
+)
+
+// maxTagSize limits how much is read for tags, cover pictures can make them big
+const maxTagSize = 16 << 20
+
+// Tags is
+type Tags struct {
+	Format Format
+	Title  string
+	Artist string
+	Album  string
+	// Date is as written in file, usually YYYY or YYYY-MM-DD
+	Date string
+}
+
+// IsZero reports whether no field song needs was found
+func (t *Tags) IsZero() bool {
+	return t.Title == "" && t.Artist == "" && t.Album == "" && t.Date == ""
+}
+
+// Read reads tags from start of audio file, r is left at unspecified position
+func Read(r io.ReadSeeker) (*Tags, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 10)
+	if _, err := io.ReadFull(r, head); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrNoTags
+		}
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(head, []byte("ID3")):
+		tags, err := readID3v2(r, head)
+		if err != nil || !tags.IsZero() {
+			return tags, err
+		}
+
+		// FLAC files may have empty ID3 tag before their own comments
+		return readAfterID3(r)
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		return readFLAC(r, 4)
+	case bytes.HasPrefix(head, []byte("OggS")):
+		return readOgg(r)
+	default:
+		return nil, ErrNoTags
+	}
+}
+
+// readAfterID3 reads FLAC comments following ID3 tag, r is at end of ID3 tag
+func readAfterID3(r io.ReadSeeker) (*Tags, error) {
+	offset, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(r, magic); err != nil || !bytes.Equal(magic, []byte("fLaC")) {
+		return nil, ErrNoTags
+	}
+
+	return readFLAC(r, offset+4)
+}
@@ -0,0 +1,235 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// syncsafeBytes encodes size in 7 bits of 4 bytes
+func syncsafeBytes(size int) []byte {
+	return []byte{byte(size >> 21 & 0x7f), byte(size >> 14 & 0x7f), byte(size >> 7 & 0x7f), byte(size & 0x7f)}
+}
+
+// id3Frame builds frame of given ID3v2 major version
+func id3Frame(major byte, id string, data []byte) []byte {
+	var frame []byte
+	switch major {
+	case 2:
+		frame = append([]byte(id), byte(len(data)>>16), byte(len(data)>>8), byte(len(data)))
+	case 3:
+		frame = binary.BigEndian.AppendUint32([]byte(id), uint32(len(data)))
+		frame = append(frame, 0, 0)
+	default:
+		frame = append(append([]byte(id), syncsafeBytes(len(data))...), 0, 0)
+	}
+
+	return append(frame, data...)
+}
+
+// id3Text is text frame data in latin1 or UTF-8 encoding
+func id3Text(encoding byte, text string) []byte {
+	return append([]byte{encoding}, text...)
+}
+
+// id3Tag builds tag with frames and some padding
+func id3Tag(major, flags byte, frames ...[]byte) []byte {
+	body := append(bytes.Join(frames, nil), make([]byte, 16)...)
+	tag := append([]byte{'I', 'D', '3', major, 0, flags}, syncsafeBytes(len(body))...)
+
+	return append(tag, body...)
+}
+
+// vorbisComment builds comment block with vendor string
+func vorbisComment(comments ...string) []byte {
+	data := binary.LittleEndian.AppendUint32(nil, 6)
+	data = append(data, "vendor"...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(comments)))
+	for _, comment := range comments {
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(comment)))
+		data = append(data, comment...)
+	}
+
+	return data
+}
+
+// flacFile builds file with STREAMINFO followed by comment block
+func flacFile(comment []byte) []byte {
+	file := append([]byte("fLaC"), 0, 0, 0, 34)
+	file = append(file, make([]byte, 34)...)
+	file = append(file, 0x80|flacVorbisComment, byte(len(comment)>>16), byte(len(comment)>>8), byte(len(comment)))
+
+	return append(file, comment...)
+}
+
+// oggFile builds one stream pages of packets, every page keeps at most
+// segmentsPerPage lacing values, so long packets span pages
+func oggFile(segmentsPerPage int, packets ...[]byte) []byte {
+	var segments []byte
+	var data []byte
+	for _, packet := range packets {
+		size := len(packet)
+		for ; size >= 255; size -= 255 {
+			segments = append(segments, 255)
+		}
+		segments = append(segments, byte(size))
+		data = append(data, packet...)
+	}
+
+	var file []byte
+	for len(segments) > 0 {
+		count := min(segmentsPerPage, len(segments))
+		pageSize := 0
+		for _, segment := range segments[:count] {
+			pageSize += int(segment)
+		}
+
+		header := make([]byte, 27)
+		copy(header, "OggS")
+		binary.LittleEndian.PutUint32(header[14:18], 42)
+		header[26] = byte(count)
+
+		file = append(file, header...)
+		file = append(file, segments[:count]...)
+		file = append(file, data[:pageSize]...)
+		segments, data = segments[count:], data[pageSize:]
+	}
+
+	return file
+}
+
+func TestReadID3v2(t *testing.T) {
+	utf16Title := []byte{1, 0xff, 0xfe}
+	for _, r := range "Hysteria" {
+		utf16Title = binary.LittleEndian.AppendUint16(utf16Title, uint16(r))
+	}
+
+	tests := []struct {
+		name string
+		file []byte
+		want Tags
+	}{
+		{
+			name: "v2.3 with UTF-16 and TYER",
+			file: id3Tag(3, 0,
+				id3Frame(3, "TIT2", utf16Title),
+				id3Frame(3, "TPE1", id3Text(0, "Mus\xe9")),
+				id3Frame(3, "TALB", id3Text(0, "Absolution")),
+				id3Frame(3, "TYER", id3Text(0, "2003")),
+				id3Frame(3, "TDAT", id3Text(0, "0112")),
+			),
+			want: Tags{Format: FormatID3v2, Title: "Hysteria", Artist: "Musé", Album: "Absolution", Date: "2003-12-01"},
+		},
+		{
+			name: "v2.4 with UTF-8 and TDRC",
+			file: id3Tag(4, 0,
+				id3Frame(4, "TIT2", id3Text(3, "Ça plane pour moi\x00second value")),
+				id3Frame(4, "TPE2", id3Text(3, "Plastic Bertrand")),
+				id3Frame(4, "TDRC", id3Text(3, "1977-11")),
+				id3Frame(4, "APIC", make([]byte, 300)),
+			),
+			want: Tags{Format: FormatID3v2, Title: "Ça plane pour moi", Artist: "Plastic Bertrand", Date: "1977-11"},
+		},
+		{
+			name: "v2.2",
+			file: id3Tag(2, 0,
+				id3Frame(2, "TT2", id3Text(0, "Yesterday")),
+				id3Frame(2, "TP1", id3Text(0, "The Beatles")),
+				id3Frame(2, "TYE", id3Text(0, "1965")),
+			),
+			want: Tags{Format: FormatID3v2, Title: "Yesterday", Artist: "The Beatles", Date: "1965"},
+		},
+		{
+			name: "v2.3 unsynchronised",
+			file: id3Tag(3, id3Unsynchronisation,
+				bytes.ReplaceAll(id3Frame(3, "TIT2", id3Text(0, "\xffa")), []byte{0xff}, []byte{0xff, 0x00}),
+			),
+			want: Tags{Format: FormatID3v2, Title: "ÿa"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(bytes.NewReader(append(tt.file, "audio frames"...)))
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if *got != tt.want {
+				t.Fatalf("Read = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadVorbis(t *testing.T) {
+	comment := vorbisComment("title=Hysteria", "ARTIST=Muse", "Album=Absolution", "DATE=2003-12-01", "TITLE=ignored", "broken")
+	longComment := vorbisComment("TITLE="+strings.Repeat("x", 600), "ARTIST=Muse")
+
+	tests := []struct {
+		name string
+		file []byte
+		want Tags
+	}{
+		{
+			name: "FLAC",
+			file: flacFile(comment),
+			want: Tags{Format: FormatVorbis, Title: "Hysteria", Artist: "Muse", Album: "Absolution", Date: "2003-12-01"},
+		},
+		{
+			name: "FLAC after empty ID3 tag",
+			file: append(id3Tag(3, 0), flacFile(comment)...),
+			want: Tags{Format: FormatVorbis, Title: "Hysteria", Artist: "Muse", Album: "Absolution", Date: "2003-12-01"},
+		},
+		{
+			name: "Ogg Vorbis",
+			file: oggFile(255, []byte("\x01vorbis identification"), append([]byte("\x03vorbis"), comment...)),
+			want: Tags{Format: FormatVorbis, Title: "Hysteria", Artist: "Muse", Album: "Absolution", Date: "2003-12-01"},
+		},
+		{
+			name: "Opus comment spanning pages",
+			file: oggFile(1, []byte("OpusHead"), append([]byte("OpusTags"), longComment...)),
+			want: Tags{Format: FormatVorbis, Title: strings.Repeat("x", 600), Artist: "Muse"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(bytes.NewReader(tt.file))
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if *got != tt.want {
+				t.Fatalf("Read = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	brokenComment := vorbisComment("TITLE=Hysteria")
+	brokenComment = brokenComment[:len(brokenComment)-3]
+
+	tests := []struct {
+		name string
+		file []byte
+		want error
+	}{
+		{name: "short file", file: []byte("ID3"), want: ErrNoTags},
+		{name: "wav", file: []byte("RIFF\x00\x00\x00\x00WAVEfmt "), want: ErrNoTags},
+		{name: "FLAC without comments", file: append([]byte("fLaC"), 0x80, 0, 0, 0), want: ErrNoTags},
+		{name: "Ogg without comment packet", file: oggFile(255, []byte("\x01vorbis")), want: ErrMalformed},
+		{name: "unsupported ID3 version", file: id3Tag(5, 0), want: ErrMalformed},
+		{name: "truncated ID3 tag", file: id3Tag(3, 0, id3Frame(3, "TIT2", id3Text(0, "Hysteria")))[:20], want: ErrMalformed},
+		{name: "broken Vorbis comment", file: flacFile(brokenComment), want: ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(bytes.NewReader(tt.file)); !errors.Is(err, tt.want) {
+				t.Fatalf("Read error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// id3 header flags
+const (
+	id3Unsynchronisation = 0x80
+	id3ExtendedHeader    = 0x40
+)
+
+// id3Frames maps frame ids of v2.2 and v2.3/v2.4 to tag fields
+var id3Frames = map[string]string{
+	"TT2": "title", "TIT2": "title",
+	"TP1": "artist", "TPE1": "artist",
+	"TP2": "albumartist", "TPE2": "albumartist",
+	"TAL": "album", "TALB": "album",
+	"TDRC": "date", "TDRL": "released", "TYE": "year", "TYER": "year", "TDA": "daymonth", "TDAT": "daymonth",
+}
+
+// readID3v2 reads tag which 10 bytes header is head, r is left at end of tag
+func readID3v2(r io.Reader, head []byte) (*Tags, error) {
+	major, flags := head[3], head[5]
+	if major < 2 || major > 4 {
+		return nil, fmt.Errorf("%w: unsupported ID3v2.%d", ErrMalformed, major)
+	}
+
+	size := syncsafe(head[6:10])
+	if size > maxTagSize {
+		return nil, fmt.Errorf("%w: ID3v2 tag of %d bytes is too big", ErrMalformed, size)
+	}
+
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	// v2.4 marks unsynchronisation per frame, before it whole tag is unsynchronised
+	if flags&id3Unsynchronisation != 0 && major < 4 {
+		body = removeUnsynchronisation(body)
+	}
+
+	if flags&id3ExtendedHeader != 0 && major > 2 {
+		if len(body) < 4 {
+			return nil, fmt.Errorf("%w: short ID3v2 extended header", ErrMalformed)
+		}
+		// v2.3 size excludes itself, v2.4 size includes itself
+		extended := int(binary.BigEndian.Uint32(body)) + 4
+		if major == 4 {
+			extended = syncsafe(body[:4])
+		}
+		if extended > len(body) {
+			return nil, fmt.Errorf("%w: ID3v2 extended header is bigger than tag", ErrMalformed)
+		}
+		body = body[extended:]
+	}
+
+	fields := map[string]string{}
+	for len(body) > 0 {
+		id, data, rest, ok := nextID3Frame(body, major)
+		if !ok {
+			break
+		}
+		body = rest
+
+		field, known := id3Frames[id]
+		if !known || fields[field] != "" {
+			continue
+		}
+		fields[field] = decodeID3Text(data)
+	}
+
+	tags := &Tags{
+		Format: FormatID3v2,
+		Title:  fields["title"],
+		Artist: firstNonEmpty(fields["artist"], fields["albumartist"]),
+		Album:  fields["album"],
+		Date:   firstNonEmpty(fields["date"], fields["released"], id3Date(fields["year"], fields["daymonth"])),
+	}
+
+	return tags, nil
+}
+
+// nextID3Frame splits first frame off body, ok is false at padding or broken frame
+func nextID3Frame(body []byte, major byte) (id string, data, rest []byte, ok bool) {
+	headerSize, idSize := 10, 4
+	if major == 2 {
+		headerSize, idSize = 6, 3
+	}
+	if len(body) < headerSize || body[0] == 0 {
+		return "", nil, nil, false
+	}
+
+	var size int
+	switch major {
+	case 2:
+		size = int(body[3])<<16 | int(body[4])<<8 | int(body[5])
+	case 3:
+		size = int(binary.BigEndian.Uint32(body[4:8]))
+	default:
+		size = syncsafe(body[4:8])
+	}
+	if size < 0 || size > len(body)-headerSize {
+		return "", nil, nil, false
+	}
+
+	id = string(body[:idSize])
+	data = body[headerSize : headerSize+size]
+	rest = body[headerSize+size:]
+
+	if major == 4 {
+		formatFlags := body[9]
+		// compressed and encrypted frames are skipped
+		if formatFlags&0x0c != 0 {
+			return id, nil, rest, true
+		}
+		if formatFlags&0x02 != 0 {
+			data = removeUnsynchronisation(data)
+		}
+		// data length indicator
+		if formatFlags&0x01 != 0 && len(data) >= 4 {
+			data = data[4:]
+		}
+	}
+	if major == 3 && body[9]&0xc0 != 0 {
+		return id, nil, rest, true
+	}
+
+	return id, data, rest, true
+}
+
+// decodeID3Text decodes text frame, only first of many values is kept
+func decodeID3Text(data []byte) string {
+	if len(data) < 2 {
+		return ""
+	}
+
+	var text string
+	switch encoding, raw := data[0], data[1:]; encoding {
+	case 0:
+		text = latin1(raw)
+	case 1:
+		text = decodeUTF16(raw, nil)
+	case 2:
+		text = decodeUTF16(raw, binary.BigEndian)
+	default:
+		text = string(raw)
+	}
+
+	if index := strings.IndexByte(text, 0); index >= 0 {
+		text = text[:index]
+	}
+
+	return strings.TrimSpace(text)
+}
+
+// decodeUTF16 decodes UTF-16 text, byte order comes from BOM when order is nil
+func decodeUTF16(raw []byte, order binary.ByteOrder) string {
+	if order == nil {
+		order = binary.LittleEndian
+		switch {
+		case bytes.HasPrefix(raw, []byte{0xfe, 0xff}):
+			order, raw = binary.BigEndian, raw[2:]
+		case bytes.HasPrefix(raw, []byte{0xff, 0xfe}):
+			raw = raw[2:]
+		}
+	}
+
+	units := make([]uint16, 0, len(raw)/2)
+	for i := 0; i+1 < len(raw); i += 2 {
+		units = append(units, order.Uint16(raw[i:]))
+	}
+
+	return string(utf16.Decode(units))
+}
+
+// latin1 decodes ISO-8859-1 text
+func latin1(raw []byte) string {
+	runes := make([]rune, len(raw))
+	for i, b := range raw {
+		runes[i] = rune(b)
+	}
+
+	return string(runes)
+}
+
+// id3Date joins v2.3 year with DDMM of TDAT
+func id3Date(year, dayMonth string) string {
+	if year == "" || len(dayMonth) != 4 {
+		return year
+	}
+
+	return year + "-" + dayMonth[2:] + "-" + dayMonth[:2]
+}
+
+// syncsafe decodes 28 bit integer stored in 7 bits of 4 bytes
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7f)<<21 | int(b[1]&0x7f)<<14 | int(b[2]&0x7f)<<7 | int(b[3]&0x7f)
+}
+
+// removeUnsynchronisation drops zero bytes inserted after 0xFF
+func removeUnsynchronisation(data []byte) []byte {
+	return bytes.ReplaceAll(data, []byte{0xff, 0x00}, []byte{0xff})
+}
+
+// firstNonEmpty is
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
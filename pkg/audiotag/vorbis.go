@@ -0,0 +1,181 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// flacVorbisComment is type of FLAC metadata block keeping comments
+	flacVorbisComment = 4
+	// maxOggPages limits pages read looking for comment header
+	maxOggPages = 512
+)
+
+// readFLAC reads metadata blocks starting at offset till comment block
+func readFLAC(r io.ReadSeeker, offset int64) (*Tags, error) {
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+
+		last, blockType := header[0]&0x80 != 0, header[0]&0x7f
+		size := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+
+		if blockType == flacVorbisComment {
+			if size > maxTagSize {
+				return nil, fmt.Errorf("%w: FLAC comment block of %d bytes is too big", ErrMalformed, size)
+			}
+
+			block := make([]byte, size)
+			if _, err := io.ReadFull(r, block); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+			}
+			return parseVorbisComment(block)
+		}
+
+		if last {
+			return nil, ErrNoTags
+		}
+		if _, err := r.Seek(size, io.SeekCurrent); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readOgg reads second packet of first logical stream, it keeps comments of
+// Vorbis, Opus and Ogg FLAC streams
+func readOgg(r io.ReadSeeker) (*Tags, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var (
+		packets [][]byte
+		packet  []byte
+		serial  uint32
+		header  = make([]byte, 27)
+	)
+
+	for page := 0; page < maxOggPages; page++ {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		if !bytes.Equal(header[:4], []byte("OggS")) {
+			return nil, fmt.Errorf("%w: missing Ogg page", ErrMalformed)
+		}
+
+		segments := make([]byte, header[26])
+		if _, err := io.ReadFull(r, segments); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+
+		pageSize := 0
+		for _, segment := range segments {
+			pageSize += int(segment)
+		}
+		data := make([]byte, pageSize)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+
+		// pages of other multiplexed streams are skipped
+		pageSerial := binary.LittleEndian.Uint32(header[14:18])
+		if page == 0 {
+			serial = pageSerial
+		} else if pageSerial != serial {
+			continue
+		}
+
+		for _, segment := range segments {
+			packet = append(packet, data[:segment]...)
+			data = data[segment:]
+			if len(packet) > maxTagSize {
+				return nil, fmt.Errorf("%w: Ogg comment packet is too big", ErrMalformed)
+			}
+			// segment shorter than 255 ends packet
+			if segment < 255 {
+				packets = append(packets, packet)
+				packet = nil
+			}
+		}
+
+		if len(packets) >= 2 {
+			return parseOggComment(packets[1])
+		}
+	}
+
+	return nil, ErrNoTags
+}
+
+// parseOggComment strips codec specific prefix of comment packet
+func parseOggComment(packet []byte) (*Tags, error) {
+	switch {
+	case bytes.HasPrefix(packet, []byte("\x03vorbis")):
+		return parseVorbisComment(packet[7:])
+	case bytes.HasPrefix(packet, []byte("OpusTags")):
+		return parseVorbisComment(packet[8:])
+	case len(packet) >= 4 && packet[0]&0x7f == flacVorbisComment:
+		// Ogg FLAC packs metadata blocks with their 4 bytes headers
+		return parseVorbisComment(packet[4:])
+	default:
+		return nil, ErrNoTags
+	}
+}
+
+// parseVorbisComment parses vendor string and KEY=value comments, all little endian
+func parseVorbisComment(data []byte) (*Tags, error) {
+	next := func() (string, bool) {
+		if len(data) < 4 {
+			return "", false
+		}
+		size := binary.LittleEndian.Uint32(data)
+		if uint64(size) > uint64(len(data)-4) {
+			return "", false
+		}
+		value := string(data[4 : 4+size])
+		data = data[4+size:]
+		return value, true
+	}
+
+	if _, ok := next(); !ok {
+		return nil, fmt.Errorf("%w: broken Vorbis vendor string", ErrMalformed)
+	}
+	if len(data) < 4 {
+		return nil, fmt.Errorf("%w: missing Vorbis comment count", ErrMalformed)
+	}
+	count := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+
+	fields := map[string]string{}
+	for i := uint32(0); i < count; i++ {
+		comment, ok := next()
+		if !ok {
+			return nil, fmt.Errorf("%w: broken Vorbis comment %d", ErrMalformed, i)
+		}
+
+		key, value, ok := strings.Cut(comment, "=")
+		key, value = strings.ToUpper(key), strings.TrimSpace(value)
+		if ok && fields[key] == "" {
+			fields[key] = value
+		}
+	}
+
+	tags := &Tags{
+		Format: FormatVorbis,
+		Title:  fields["TITLE"],
+		Artist: firstNonEmpty(fields["ARTIST"], fields["ALBUMARTIST"]),
+		Album:  fields["ALBUM"],
+		Date:   firstNonEmpty(fields["DATE"], fields["YEAR"]),
+	}
+
+	return tags, nil
+}
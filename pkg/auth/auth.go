@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Clients authenticate with static bearer tokens from config, each token
+// belongs to a named principal. Principal name is kept in context as actor
+// of the request.
+
+// ErrInvalidToken is returned when token is missing or unknown
+var ErrInvalidToken = errors.New("invalid or missing token")
+
+// TokenStore keeps tokens of principals
+type TokenStore struct {
+	tokens map[string]string // token -> principal name
+}
+
+// NewTokenStore parses "name:token" pairs
+func NewTokenStore(pairs []string) (*TokenStore, error) {
+	store := &TokenStore{tokens: make(map[string]string, len(pairs))}
+
+	for _, pair := range pairs {
+		name, token, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || name == "" || token == "" {
+			return nil, fmt.Errorf("auth.NewTokenStore: token must be given as name:token")
+		}
+		store.tokens[token] = name
+	}
+
+	return store, nil
+}
+
+// Enabled reports if any token is configured, without tokens auth is off
+func (s *TokenStore) Enabled() bool {
+	return s != nil && len(s.tokens) > 0
+}
+
+// Authenticate returns principal name of token
+func (s *TokenStore) Authenticate(token string) (string, error) {
+	for known, name := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+			return name, nil
+		}
+	}
+
+	return "", ErrInvalidToken
+}
+
+// BearerToken extracts token from "Bearer <token>" authorization value
+func BearerToken(authorization string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	return strings.TrimSpace(token), true
+}
+
+type actorKey struct{}
+
+// WithActor stores name of authenticated principal in context
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns principal name, empty when request is anonymous
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
@@ -0,0 +1,61 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// Package blobstore keeps uploaded files (audio, images) out of database.
+// Blobs are addressed by slash separated keys, e.g. "audio/ab/ab12...", and
+// are immutable, new content is written under new key.
+
+var (
+	// ErrNotFound is returned when blob with key doesn't exist
+	ErrNotFound = errors.New("blob not found")
+	// ErrInvalidKey is returned for empty keys and keys leaving root, e.g. "../x"
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Info is
+type Info struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Object is opened blob, it is seekable so it can be served by ranges
+type Object interface {
+	io.ReadSeekCloser
+	Info() Info
+}
+
+// BlobStore is
+type BlobStore interface {
+	// Put stores content of r under key, replacing existing blob
+	Put(ctx context.Context, key string, r io.Reader) (Info, error)
+	// Open returns blob for reading, ErrNotFound when it doesn't exist
+	Open(ctx context.Context, key string) (Object, error)
+	// Stat returns info of blob, ErrNotFound when it doesn't exist
+	Stat(ctx context.Context, key string) (Info, error)
+	// Delete removes blob, deleting missing blob is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// ValidKey reports whether key is relative slash separated path without
+// empty, "." and ".." segments
+func ValidKey(key string) bool {
+	if key == "" || strings.ContainsAny(key, `\`+"\x00") {
+		return false
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,157 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Local is BlobStore keeping blobs as files under root directory
+type Local struct {
+	root string
+}
+
+// NewLocal creates root directory when it doesn't exist
+func NewLocal(root string) (*Local, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("blobstore.NewLocal.Abs: %w", err)
+	}
+
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("blobstore.NewLocal.MkdirAll: %w", err)
+	}
+
+	return &Local{root: root}, nil
+}
+
+// Put writes to temporary file first and renames it, so readers never see half written blob
+func (l *Local) Put(ctx context.Context, key string, r io.Reader) (Info, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return Info{}, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return Info{}, fmt.Errorf("blobstore.Local.Put.MkdirAll: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return Info{}, fmt.Errorf("blobstore.Local.Put.CreateTemp: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, contextReader{ctx: ctx, r: r}); err != nil {
+		tmp.Close()
+		return Info{}, fmt.Errorf("blobstore.Local.Put.Copy: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return Info{}, fmt.Errorf("blobstore.Local.Put.Close: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return Info{}, fmt.Errorf("blobstore.Local.Put.Rename: %w", err)
+	}
+
+	return l.Stat(ctx, key)
+}
+
+// Open is
+func (l *Local) Open(_ context.Context, key string) (Object, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("blobstore.Local.Open.Stat: %w", err)
+	}
+
+	return &localObject{File: file, info: Info{Key: key, Size: stat.Size(), ModTime: stat.ModTime()}}, nil
+}
+
+// Stat is
+func (l *Local) Stat(_ context.Context, key string) (Info, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return Info{}, err
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return Info{}, notFound(err)
+	}
+
+	return Info{Key: key, Size: stat.Size(), ModTime: stat.ModTime()}, nil
+}
+
+// Delete is
+func (l *Local) Delete(_ context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("blobstore.Local.Delete.Remove: %w", err)
+	}
+
+	return nil
+}
+
+// path maps key to file under root
+func (l *Local) path(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+// notFound turns missing file to ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+// localObject is
+type localObject struct {
+	*os.File
+	info Info
+}
+
+// Info is
+func (o *localObject) Info() Info {
+	return o.info
+}
+
+// contextReader stops copying when context is canceled, e.g. client went away
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read is
+func (cr contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return cr.r.Read(p)
+}
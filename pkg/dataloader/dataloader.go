@@ -0,0 +1,123 @@
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Loader collects keys requested by concurrent resolvers during short wait window
+// and fetches them with one call, so nested queries don't hit repository N+1 times.
+// Results are cached, loader must live only as long as one request and batches
+// are fetched with context of that request, not with context of resolver which
+// happened to start the batch.
+
+const (
+	// DefaultWait is window in which keys are collected into one batch
+	DefaultWait = 2 * time.Millisecond
+	// DefaultMaxBatch is max count of keys in one fetch call
+	DefaultMaxBatch = 100
+)
+
+// FetchFunc loads values of keys, keys missing in returned map resolve to zero value
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader is
+type Loader[K comparable, V any] struct {
+	ctx      context.Context
+	fetch    FetchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]*result[V]
+	batch *batch[K, V]
+}
+
+// result is value of one key, done is closed when it is fetched
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// batch is keys waiting for one fetch call
+type batch[K comparable, V any] struct {
+	keys    []K
+	results map[K]*result[V]
+	once    sync.Once
+}
+
+// New creates loader of request with default wait and batch size,
+// ctx is passed to every fetch
+func New[K comparable, V any](ctx context.Context, fetch FetchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		ctx:      ctx,
+		fetch:    fetch,
+		wait:     DefaultWait,
+		maxBatch: DefaultMaxBatch,
+		cache:    make(map[K]*result[V]),
+	}
+}
+
+// Load returns value of key, waiting till batch containing it is fetched
+// or ctx of caller is done
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+
+	res, ok := l.cache[key]
+	if !ok {
+		res = &result[V]{done: make(chan struct{})}
+		l.cache[key] = res
+		l.enqueue(key, res)
+	}
+
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// enqueue adds key to current batch, l.mu must be held
+func (l *Loader[K, V]) enqueue(key K, res *result[V]) {
+	if l.batch == nil {
+		b := &batch[K, V]{results: make(map[K]*result[V])}
+		l.batch = b
+		time.AfterFunc(l.wait, func() { l.dispatch(b) })
+	}
+
+	b := l.batch
+	b.keys = append(b.keys, key)
+	b.results[key] = res
+
+	if len(b.keys) >= l.maxBatch {
+		l.batch = nil
+		go l.dispatch(b)
+	}
+}
+
+// dispatch fetches batch once, either by timer or when batch is full
+func (l *Loader[K, V]) dispatch(b *batch[K, V]) {
+	b.once.Do(func() {
+		l.mu.Lock()
+		if l.batch == b {
+			l.batch = nil
+		}
+		l.mu.Unlock()
+
+		values, err := l.fetch(l.ctx, b.keys)
+		for key, res := range b.results {
+			if err != nil {
+				res.err = err
+			} else {
+				res.value = values[key]
+			}
+			close(res.done)
+		}
+	})
+}
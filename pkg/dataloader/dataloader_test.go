@@ -0,0 +1,205 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is fetch function remembering keys of every call
+type recorder struct {
+	mu    sync.Mutex
+	calls [][]int
+	err   error
+}
+
+func (r *recorder) fetch(ctx context.Context, keys []int) (map[int]string, error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, slices.Sorted(slices.Values(keys)))
+	r.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	values := make(map[int]string, len(keys))
+	for _, key := range keys {
+		// odd keys are missing
+		if key%2 == 0 {
+			values[key] = "song " + strconv.Itoa(key)
+		}
+	}
+
+	return values, nil
+}
+
+func (r *recorder) fetched() [][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.calls)
+}
+
+// newTestLoader returns loader with wait long enough to batch goroutines of test
+func newTestLoader(r *recorder, maxBatch int) *Loader[int, string] {
+	l := New(context.Background(), r.fetch)
+	l.wait = 50 * time.Millisecond
+	l.maxBatch = maxBatch
+
+	return l
+}
+
+// loadAll loads keys concurrently and returns values in order of keys
+func loadAll(t *testing.T, ctx context.Context, l *Loader[int, string], keys ...int) ([]string, []error) {
+	t.Helper()
+
+	var (
+		wg     sync.WaitGroup
+		values = make([]string, len(keys))
+		errs   = make([]error, len(keys))
+	)
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = l.Load(ctx, key)
+		}()
+	}
+	wg.Wait()
+
+	return values, errs
+}
+
+func TestLoadBatchesConcurrentKeys(t *testing.T) {
+	r := &recorder{}
+	l := newTestLoader(r, DefaultMaxBatch)
+
+	values, errs := loadAll(t, context.Background(), l, 2, 3, 4, 2)
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Load #%d: %v", i, err)
+		}
+	}
+	// missing key resolves to zero value
+	if want := []string{"song 2", "", "song 4", "song 2"}; !slices.Equal(values, want) {
+		t.Fatalf("values = %q, want %q", values, want)
+	}
+
+	calls := r.fetched()
+	if len(calls) != 1 || !slices.Equal(calls[0], []int{2, 3, 4}) {
+		t.Fatalf("fetch calls = %v, want one call with [2 3 4]", calls)
+	}
+}
+
+func TestLoadCachesKeys(t *testing.T) {
+	r := &recorder{}
+	l := newTestLoader(r, DefaultMaxBatch)
+
+	loadAll(t, context.Background(), l, 2, 4)
+	values, _ := loadAll(t, context.Background(), l, 4, 6)
+
+	if want := []string{"song 4", "song 6"}; !slices.Equal(values, want) {
+		t.Fatalf("values = %q, want %q", values, want)
+	}
+
+	calls := r.fetched()
+	if len(calls) != 2 || !slices.Equal(calls[1], []int{6}) {
+		t.Fatalf("fetch calls = %v, want cached key 4 not to be fetched again", calls)
+	}
+}
+
+func TestLoadSplitsFullBatches(t *testing.T) {
+	r := &recorder{}
+	l := newTestLoader(r, 2)
+
+	loadAll(t, context.Background(), l, 1, 2, 3, 4, 5)
+
+	calls := r.fetched()
+	if len(calls) != 3 {
+		t.Fatalf("fetch calls = %v, want 3 calls of at most 2 keys", calls)
+	}
+
+	var keys []int
+	for _, call := range calls {
+		if len(call) > 2 {
+			t.Fatalf("fetch call %v has more than 2 keys", call)
+		}
+		keys = append(keys, call...)
+	}
+	if slices.Sort(keys); !slices.Equal(keys, []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("fetched keys = %v", keys)
+	}
+}
+
+func TestLoadFetchError(t *testing.T) {
+	r := &recorder{err: errors.New("database is down")}
+	l := newTestLoader(r, DefaultMaxBatch)
+
+	_, errs := loadAll(t, context.Background(), l, 1, 2)
+
+	for i, err := range errs {
+		if !errors.Is(err, r.err) {
+			t.Fatalf("Load #%d error = %v, want %v", i, err, r.err)
+		}
+	}
+}
+
+func TestLoadCancelled(t *testing.T) {
+	r := &recorder{}
+	l := newTestLoader(r, DefaultMaxBatch)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := l.Load(ctx, 2); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Load error = %v, want context.Canceled", err)
+	}
+}
+
+func TestLoadFetchesWithLoaderContext(t *testing.T) {
+	r := &recorder{}
+	l := New(context.Background(), r.fetch)
+	l.wait = 50 * time.Millisecond
+
+	// first resolver starts batch and gives up before it is fetched
+	first, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	var (
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, firstErr = l.Load(first, 2)
+	}()
+
+	// let first resolver open the batch
+	time.Sleep(time.Millisecond)
+
+	value, err := l.Load(context.Background(), 4)
+	wg.Wait()
+
+	if !errors.Is(firstErr, context.Canceled) {
+		t.Fatalf("first Load error = %v, want context.Canceled", firstErr)
+	}
+	if err != nil || value != "song 4" {
+		t.Fatalf("second Load = %q, %v, want song 4 fetched with loader context", value, err)
+	}
+	if calls := r.fetched(); len(calls) != 1 {
+		t.Fatalf("fetch calls = %v, want one batch", calls)
+	}
+}
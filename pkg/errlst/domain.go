@@ -0,0 +1,189 @@
+package errlst
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Repositories and services don't know about HTTP, they return typed domain errors
+// built with NotFound, Conflict, Validation and Unavailable. Every transport maps
+// them to its own status codes once, HTTP layer does it in ParseErrors.
+
+// Kind classifies domain errors.
+type Kind uint8
+
+const (
+	// KindInternal is unexpected failure, it is zero value of Kind.
+	KindInternal Kind = iota
+	// KindNotFound means requested entity doesn't exist.
+	KindNotFound
+	// KindConflict means request conflicts with current state of entity.
+	KindConflict
+	// KindValidation means input is not acceptable.
+	KindValidation
+	// KindUnavailable means dependency (database, upstream api) can't serve now.
+	KindUnavailable
+	// KindPrecondition means entity was changed since client read it.
+	KindPrecondition
+)
+
+// String returns name of kind.
+func (k Kind) String() string {
+	switch k {
+	case KindNotFound:
+		return "not found"
+	case KindConflict:
+		return "conflict"
+	case KindValidation:
+		return "validation"
+	case KindUnavailable:
+		return "unavailable"
+	case KindPrecondition:
+		return "precondition"
+	default:
+		return "internal"
+	}
+}
+
+// sentinel returns common application error matching kind, so
+// errors.Is(err, errlst.ErrNotFound) works for typed errors too.
+func (k Kind) sentinel() error {
+	switch k {
+	case KindNotFound:
+		return ErrNotFound
+	case KindConflict:
+		return ErrConflict
+	case KindValidation:
+		return ErrBadRequest
+	case KindUnavailable:
+		return ErrServiceUnavailable
+	case KindPrecondition:
+		return ErrPreconditionFailed
+	default:
+		return ErrInternalServer
+	}
+}
+
+// HTTPStatus returns HTTP status code for kind.
+func (k Kind) HTTPStatus() int {
+	switch k {
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	case KindPrecondition:
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// DomainError is typed error returned by repositories and services.
+type DomainError struct {
+	Kind    Kind
+	Message string
+	// Code keeps SQLSTATE when error comes from Postgres.
+	Code string
+	// Fields keeps failed fields of Validation errors.
+	Fields []FieldError
+	Err    error
+}
+
+// Error returns message with wrapped cause.
+func (e *DomainError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Kind.String()
+	}
+
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+// Unwrap returns wrapped cause.
+func (e *DomainError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is sentinel error of same kind.
+func (e *DomainError) Is(target error) bool {
+	return target == e.Kind.sentinel()
+}
+
+// NewDomainError creates typed error of given kind wrapping cause.
+func NewDomainError(kind Kind, msg string, cause error) error {
+	return &DomainError{Kind: kind, Message: msg, Err: cause}
+}
+
+// NotFound creates KindNotFound error.
+func NotFound(msg string, cause error) error {
+	return NewDomainError(KindNotFound, msg, cause)
+}
+
+// Conflict creates KindConflict error.
+func Conflict(msg string, cause error) error {
+	return NewDomainError(KindConflict, msg, cause)
+}
+
+// Validation creates KindValidation error.
+func Validation(msg string, cause error) error {
+	return NewDomainError(KindValidation, msg, cause)
+}
+
+// Unavailable creates KindUnavailable error.
+func Unavailable(msg string, cause error) error {
+	return NewDomainError(KindUnavailable, msg, cause)
+}
+
+// PreconditionFailed creates KindPrecondition error.
+func PreconditionFailed(msg string, cause error) error {
+	return NewDomainError(KindPrecondition, msg, cause)
+}
+
+// KindOf returns kind of first DomainError in chain, KindInternal otherwise.
+func KindOf(err error) Kind {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind
+	}
+
+	return KindInternal
+}
+
+// fromDomainError maps typed error to RestErr. Clients get message only,
+// wrapped causes may keep driver errors, constraint names or connection
+// details, so the full chain is logged instead.
+func fromDomainError(e *DomainError) RestErr {
+	if e.Kind == KindInternal {
+		logrus.Errorf("[errlst][fromDomainError]: %v", e)
+	} else {
+		logrus.Debugf("[errlst][fromDomainError]: %v", e)
+	}
+
+	msg := e.Message
+	if msg == "" {
+		msg = e.Kind.String()
+	}
+
+	restErr := &RestError{
+		ErrStatus:  e.Kind.HTTPStatus(),
+		ErrMessage: e.Kind.sentinel().Error(),
+		ErrCauses:  msg,
+		ErrFields:  e.Fields,
+	}
+
+	if len(e.Fields) > 0 {
+		restErr.ErrType = ErrFieldValidation.Error()
+	}
+
+	return restErr
+}
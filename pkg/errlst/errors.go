@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/go-playground/validator/v10"
 	"net/http"
+	"strconv"
 	"strings"
 
-	"github.com/jackc/pgconn"
+	"github.com/go-playground/validator/v10"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 )
 
 // Package errlst provides custom error handling for HTTP errors.
@@ -43,6 +46,12 @@ var (
 	ErrConflict = errors.New("conflict")
 	// ErrGone represents an error for a 410 Gone response.
 	ErrGone = errors.New("gone")
+	// ErrPreconditionFailed represents an error for a 412 Precondition Failed response.
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrPreconditionRequired represents an error for a 428 Precondition Required response.
+	ErrPreconditionRequired = errors.New("precondition required")
+	// ErrUnprocessableEntity represents an error for a 422 Unprocessable Entity response.
+	ErrUnprocessableEntity = errors.New("unprocessable entity")
 	// ErrLengthRequired represents an error for missing content length.
 	ErrLengthRequired = errors.New("length required")
 	// ErrTooManyRequests represents an error for a 429 Too Many Requests response.
@@ -77,6 +86,17 @@ type RestError struct {
 	ErrStatus  int         `json:"err_status,omitempty"`
 	ErrMessage string      `json:"err_msg,omitempty"`
 	ErrCauses  interface{} `json:"err_cause,omitempty"`
+	// ErrType and ErrFields are used only by problem+json responses,
+	// legacy format keeps old {err_status, err_msg, err_cause} shape.
+	ErrType   string       `json:"-"`
+	ErrFields []FieldError `json:"-"`
+}
+
+// FieldError describes one failed validation rule of request field.
+type FieldError struct {
+	Field string `json:"field"`
+	Tag   string `json:"tag"`
+	Param string `json:"param,omitempty"`
 }
 
 // Status returns the HTTP status code associated with the error.
@@ -175,6 +195,24 @@ func NewTooManyRequestError(causes interface{}) RestErr {
 	}
 }
 
+// NewPreconditionRequiredError creates a new 428 Precondition Required error with the provided cause.
+func NewPreconditionRequiredError(causes interface{}) RestErr {
+	return &RestError{
+		ErrStatus:  http.StatusPreconditionRequired,
+		ErrMessage: ErrPreconditionRequired.Error(),
+		ErrCauses:  causes,
+	}
+}
+
+// NewUnprocessableEntityError creates a new 422 Unprocessable Entity error with the provided cause.
+func NewUnprocessableEntityError(causes interface{}) RestErr {
+	return &RestError{
+		ErrStatus:  http.StatusUnprocessableEntity,
+		ErrMessage: ErrUnprocessableEntity.Error(),
+		ErrCauses:  causes,
+	}
+}
+
 // NewBadQueryParamsError creates a new 400 Bad Request error for invalid query parameters.
 func NewBadQueryParamsError(causes interface{}) RestErr {
 	return &RestError{
@@ -185,95 +223,87 @@ func NewBadQueryParamsError(causes interface{}) RestErr {
 }
 
 // ParseErrors attempts to map a given error to a RestErr.
-// It handles various error types such as SQL errors, validation errors, and Go-specific errors.
+// It is the single place where typed domain errors, validation errors and
+// driver errors are turned to HTTP statuses, everything is matched with errors.Is/As.
 // If no specific error is matched, it returns a generic Internal Server Error.
 func ParseErrors(err error) RestErr {
+	var (
+		restErr       RestErr
+		domainErr     *DomainError
+		validationErr validator.ValidationErrors
+		httpErr       *echo.HTTPError
+		numErr        *strconv.NumError
+		pgErr         *pgconn.PgError
+	)
+
 	switch {
+	// Already parsed errors are returned as they are
+	case errors.As(err, &restErr):
+		return restErr
+	case errors.As(err, &domainErr):
+		return fromDomainError(domainErr)
+	case errors.As(err, &validationErr):
+		return ParseValidatorError(validationErr)
+	case errors.As(err, &httpErr):
+		return NewRestError(httpErr.Code, strings.ToLower(http.StatusText(httpErr.Code)), fmt.Sprint(httpErr.Message))
+
+	// Handle driver errors which were not classified by repository
+	case errors.Is(err, pgx.ErrNoRows), errors.Is(err, pgx.ErrTooManyRows), errors.As(err, &pgErr):
+		return ParseErrors(FromPostgres(err))
+
 	// Handle Go-specific errors
-	case errors.Is(err, pgx.ErrNoRows):
-		return NewNotFoundError(ErrNoRecord.Error() + err.Error())
-	case errors.Is(err, pgx.ErrTooManyRows):
-		return NewConflictError(ErrTooManyRequests.Error() + err.Error())
 	case errors.Is(err, context.DeadlineExceeded):
-		return NewRequestTimedOutError(err.Error())
-
-	// Handle SQLSTATE errors
-	case strings.Contains(err.Error(), "SQLSTATE"):
-		return ParseSqlErrors(err)
+		logrus.Debugf("[errlst][ParseErrors]: %v", err)
+		return NewRequestTimedOutError(strings.ToLower(http.StatusText(http.StatusRequestTimeout)))
 
 	// Handle strconv.Atoi errors
-	case strings.Contains(err.Error(), "invalid syntax"):
+	case errors.As(err, &numErr):
 		return NewBadRequestError(ErrSyntax.Error())
 
 	// Handle validation errors
-	case strings.Contains(err.Error(), ErrFieldValidation.Error()):
+	case errors.Is(err, ErrFieldValidation):
 		return NewBadRequestError(err.Error())
 
 	// Default case: Return Internal Server Error
 	default:
-		return NewInternalServerError(err.Error())
+		logrus.Errorf("[errlst][ParseErrors]: %v", err)
+		return NewInternalServerError(strings.ToLower(http.StatusText(http.StatusInternalServerError)))
 	}
 }
 
-// parseSQLErrors parses SQL errors and returns corresponding RestErr
-func ParseSqlErrors(err error) RestErr {
-	var pgErr *pgconn.PgError
-	if errors.As(err, &pgErr) {
-		switch pgErr.Code {
-		// CLASS 22
-		case "22001": // Numeric value out of range
-			return NewBadRequestError("Numeric" + ErrRange.Error() + pgErr.Message)
-
-		// CLASS 23
-		case "23505": // Unique violation
-			return NewConflictError("Unique constraint violation: " + pgErr.Message)
-		case "23503": // Foreign key violation\
-			return NewBadRequestError("Foreign key violation: " + pgErr.Message)
-		case "23502": // Not-null violation
-			return NewBadRequestError("Not-null constraint violation: " + pgErr.Message)
-		case "23514": // CHECK violation, a product price must be greater than 0
-			return NewBadRequestError("Check violation: " + pgErr.Message)
-
-		// CLASS 40
-		case "40001": // Serialization failure
-			return NewConflictError("Serialization error: " + pgErr.Message)
-
-		// CLASS 42
-		case "42601": // Syntax error
-			return NewBadRequestError("Syntax error in SQL statement: " + pgErr.Message)
-		}
-	}
-
-	if strings.Contains(err.Error(), "scany") {
-		return NewBadRequestError(err.Error())
-	}
-
-	if strings.Contains(err.Error(), "no corresponding field found") {
-		return NewBadRequestError(err.Error())
-	}
-
-	return NewBadRequestError(err.Error())
-}
-
 // ParseValidatorError parses validation errors and returns corresponding RestErr
 func ParseValidatorError(err error) RestErr {
-	validationErrs, ok := err.(validator.ValidationErrors)
-	if !ok {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
 		return NewBadRequestError(err.Error()) // If not a validation error, fallback to generic error
 	}
 
-	// Collect detailed validation error messages
-	var errorMessages []string
+	// Collect detailed validation error messages, old clients still read them from err_cause
+	var (
+		errorMessages []string
+		fieldErrors   []FieldError
+	)
 	for _, fieldErr := range validationErrs {
 		// For each validation error, create a message
-		errorMessage := fmt.Sprintf("Field Validation for %s failed on the %s tag", fieldErr.Field(), fieldErr.Tag())
+		errorMessage := fmt.Sprintf("Field Validation for %s failed on the %s tag", fieldErr.StructField(), fieldErr.Tag())
 
 		// Append the message to the error list
 		errorMessages = append(errorMessages, errorMessage)
+		fieldErrors = append(fieldErrors, FieldError{
+			Field: fieldErr.Field(),
+			Tag:   fieldErr.Tag(),
+			Param: fieldErr.Param(),
+		})
 	}
 
 	// Combine all messages into one string and return a Bad Request Error
-	return NewBadRequestError(strings.Join(errorMessages, ", "))
+	return &RestError{
+		ErrStatus:  http.StatusBadRequest,
+		ErrMessage: ErrBadRequest.Error(),
+		ErrCauses:  strings.Join(errorMessages, ", "),
+		ErrType:    ErrFieldValidation.Error(),
+		ErrFields:  fieldErrors,
+	}
 }
 
 // Response returns is ErrorResponse, for clean syntax I took function name Response
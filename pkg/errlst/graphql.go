@@ -0,0 +1,45 @@
+package errlst
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// GraphQLErr is error returned from GraphQL resolvers, its extensions
+// carry same status and problem type HTTP layer would respond with.
+type GraphQLErr struct {
+	restErr RestErr
+}
+
+// Error returns causes of error, they are shown to clients as message.
+func (e *GraphQLErr) Error() string {
+	if causes := e.restErr.Causes(); causes != nil {
+		return fmt.Sprint(causes)
+	}
+
+	return http.StatusText(e.restErr.Status())
+}
+
+// Extensions are added to "extensions" of GraphQL error.
+func (e *GraphQLErr) Extensions() map[string]interface{} {
+	problem := NewProblem(e.restErr, "")
+
+	extensions := map[string]interface{}{
+		"status": problem.Status,
+		"type":   problem.Type,
+	}
+	if len(problem.Errors) > 0 {
+		extensions["errors"] = problem.Errors
+	}
+
+	return extensions
+}
+
+// GraphQLError parses err same way as HTTP layer for GraphQL response.
+func GraphQLError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &GraphQLErr{restErr: ParseErrors(err)}
+}
@@ -0,0 +1,61 @@
+package errlst
+
+import (
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// httpToGRPCCodes maps RestErr statuses to gRPC codes
+var httpToGRPCCodes = map[int]codes.Code{
+	http.StatusBadRequest:           codes.InvalidArgument,
+	http.StatusUnauthorized:         codes.Unauthenticated,
+	http.StatusForbidden:            codes.PermissionDenied,
+	http.StatusNotFound:             codes.NotFound,
+	http.StatusMethodNotAllowed:     codes.Unimplemented,
+	http.StatusRequestTimeout:       codes.DeadlineExceeded,
+	http.StatusConflict:             codes.Aborted,
+	http.StatusGone:                 codes.NotFound,
+	http.StatusPreconditionFailed:   codes.FailedPrecondition,
+	http.StatusPreconditionRequired: codes.FailedPrecondition,
+	http.StatusUnprocessableEntity:  codes.InvalidArgument,
+	http.StatusTooManyRequests:      codes.ResourceExhausted,
+	http.StatusNotImplemented:       codes.Unimplemented,
+	http.StatusServiceUnavailable:   codes.Unavailable,
+	http.StatusGatewayTimeout:       codes.DeadlineExceeded,
+}
+
+// GRPCCode returns gRPC code of RestErr status
+func GRPCCode(restErr RestErr) codes.Code {
+	if code, ok := httpToGRPCCodes[restErr.Status()]; ok {
+		return code
+	}
+
+	if restErr.Status() >= 500 {
+		return codes.Internal
+	}
+
+	return codes.Unknown
+}
+
+// GRPCError parses err same way as HTTP layer and returns gRPC status error.
+// Errors which are already gRPC statuses are returned as they are.
+func GRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	restErr := ParseErrors(err)
+	message := http.StatusText(restErr.Status())
+	if causes := restErr.Causes(); causes != nil {
+		message = fmt.Sprint(causes)
+	}
+
+	return status.Error(GRPCCode(restErr), message)
+}
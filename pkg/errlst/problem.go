@@ -0,0 +1,97 @@
+package errlst
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Format tells in which shape errors are written to clients.
+type Format string
+
+const (
+	// FormatProblem writes RFC 7807 application/problem+json responses.
+	FormatProblem Format = "problem"
+	// FormatLegacy writes old {err_status, err_msg, err_cause} responses.
+	FormatLegacy Format = "legacy"
+
+	// MIMEApplicationProblemJSON is content type of problem responses.
+	MIMEApplicationProblemJSON = "application/problem+json"
+
+	// problemTypePrefix is prefix of relative URIs used as problem types.
+	problemTypePrefix = "/problems/"
+)
+
+var responseFormat atomic.Value
+
+func init() {
+	responseFormat.Store(FormatProblem)
+}
+
+// SetFormat sets error response format, unknown values fall back to problem.
+func SetFormat(f Format) {
+	if f != FormatLegacy {
+		f = FormatProblem
+	}
+	responseFormat.Store(f)
+}
+
+// GetFormat returns current error response format.
+func GetFormat() Format {
+	return responseFormat.Load().(Format)
+}
+
+// Problem is RFC 7807 problem details object.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem converts RestErr to problem details for given request instance.
+func NewProblem(restErr RestErr, instance string) *Problem {
+	problem := &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(restErr.Status()),
+		Status:   restErr.Status(),
+		Instance: instance,
+	}
+
+	if causes := restErr.Causes(); causes != nil {
+		problem.Detail = fmt.Sprint(causes)
+	}
+
+	if e, ok := restErr.(*RestError); ok {
+		kind := e.ErrType
+		if kind == "" {
+			kind = e.ErrMessage
+		}
+
+		if kind != "" {
+			problem.Type = problemTypePrefix + strings.ReplaceAll(strings.ToLower(kind), " ", "-")
+			problem.Title = kind
+		}
+		problem.Errors = e.ErrFields
+	}
+
+	return problem
+}
+
+// Write parses err and writes it to client in configured format.
+// Handlers call it as httpError.Write(c, err).
+func Write(c echo.Context, err error) error {
+	restErr := ParseErrors(err)
+
+	if GetFormat() == FormatLegacy {
+		return c.JSON(restErr.Status(), restErr)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+	return c.JSON(restErr.Status(), NewProblem(restErr, c.Request().URL.RequestURI()))
+}
@@ -0,0 +1,125 @@
+package errlst
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// writeError writes err with Write and returns recorded response
+func writeError(t *testing.T, err error) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/song/create?x=1", nil), rec)
+
+	if writeErr := Write(c, err); writeErr != nil {
+		t.Fatalf("Write: %v", writeErr)
+	}
+
+	return rec
+}
+
+func validationError(t *testing.T) error {
+	t.Helper()
+
+	request := struct {
+		Group string `json:"group" validate:"required"`
+		Title string `json:"title" validate:"max=3"`
+	}{Title: "Yesterday"}
+
+	err := validator.New().Struct(request)
+	if err == nil {
+		t.Fatal("validator accepted invalid request")
+	}
+
+	return err
+}
+
+func TestWriteProblem(t *testing.T) {
+	SetFormat(FormatProblem)
+
+	rec := writeError(t, validationError(t))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if got := rec.Header().Get(echo.HeaderContentType); got != MIMEApplicationProblemJSON {
+		t.Fatalf("Content-Type = %q, want %q", got, MIMEApplicationProblemJSON)
+	}
+
+	var problem Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+
+	if problem.Type != "/problems/field-validation-error" || problem.Title != ErrFieldValidation.Error() ||
+		problem.Status != http.StatusBadRequest || problem.Instance != "/song/create?x=1" || problem.Detail == "" {
+		t.Fatalf("problem = %+v", problem)
+	}
+
+	want := []FieldError{{Field: "Group", Tag: "required"}, {Field: "Title", Tag: "max", Param: "3"}}
+	if len(problem.Errors) != len(want) {
+		t.Fatalf("errors = %+v, want %+v", problem.Errors, want)
+	}
+	for i := range want {
+		if problem.Errors[i] != want[i] {
+			t.Fatalf("errors[%d] = %+v, want %+v", i, problem.Errors[i], want[i])
+		}
+	}
+}
+
+func TestWriteLegacy(t *testing.T) {
+	SetFormat(FormatLegacy)
+	defer SetFormat(FormatProblem)
+
+	rec := writeError(t, NewNotFoundError("song 7 not found"))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if got := rec.Header().Get(echo.HeaderContentType); got != echo.MIMEApplicationJSON {
+		t.Fatalf("Content-Type = %q, want %q", got, echo.MIMEApplicationJSON)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"err_status": float64(http.StatusNotFound),
+		"err_msg":    ErrNotFound.Error(),
+		"err_cause":  "song 7 not found",
+	}
+	if len(body) != len(want) {
+		t.Fatalf("body = %v, want %v", body, want)
+	}
+	for key, value := range want {
+		if body[key] != value {
+			t.Fatalf("body[%q] = %v, want %v", key, body[key], value)
+		}
+	}
+}
+
+func TestSetFormatFallsBackToProblem(t *testing.T) {
+	SetFormat(FormatLegacy)
+	SetFormat("xml")
+
+	if got := GetFormat(); got != FormatProblem {
+		t.Fatalf("GetFormat() = %q, want %q", got, FormatProblem)
+	}
+}
+
+func TestNewProblemWithoutMessage(t *testing.T) {
+	problem := NewProblem(NewRestError(http.StatusTeapot, "", nil), "")
+
+	if problem.Type != "about:blank" || problem.Title != http.StatusText(http.StatusTeapot) ||
+		problem.Status != http.StatusTeapot || problem.Detail != "" {
+		t.Fatalf("problem = %+v", problem)
+	}
+}
@@ -0,0 +1,135 @@
+package errlst
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// sqlStateClasses maps SQLSTATE classes (first two chars of code) to kinds.
+// See https://www.postgresql.org/docs/current/errcodes-appendix.html
+var sqlStateClasses = map[string]Kind{
+	"00": KindInternal,    // Successful Completion
+	"01": KindInternal,    // Warning
+	"02": KindNotFound,    // No Data
+	"03": KindInternal,    // SQL Statement Not Yet Complete
+	"08": KindUnavailable, // Connection Exception
+	"09": KindInternal,    // Triggered Action Exception
+	"0A": KindInternal,    // Feature Not Supported
+	"0B": KindInternal,    // Invalid Transaction Initiation
+	"0F": KindInternal,    // Locator Exception
+	"0L": KindInternal,    // Invalid Grantor
+	"0P": KindInternal,    // Invalid Role Specification
+	"0Z": KindInternal,    // Diagnostics Exception
+	"20": KindInternal,    // Case Not Found
+	"21": KindConflict,    // Cardinality Violation
+	"22": KindValidation,  // Data Exception
+	"23": KindValidation,  // Integrity Constraint Violation
+	"24": KindInternal,    // Invalid Cursor State
+	"25": KindInternal,    // Invalid Transaction State
+	"26": KindInternal,    // Invalid SQL Statement Name
+	"27": KindConflict,    // Triggered Data Change Violation
+	"28": KindUnavailable, // Invalid Authorization Specification
+	"2B": KindConflict,    // Dependent Privilege Descriptors Still Exist
+	"2D": KindInternal,    // Invalid Transaction Termination
+	"2F": KindInternal,    // SQL Routine Exception
+	"34": KindInternal,    // Invalid Cursor Name
+	"38": KindInternal,    // External Routine Exception
+	"39": KindInternal,    // External Routine Invocation Exception
+	"3B": KindInternal,    // Savepoint Exception
+	"3D": KindUnavailable, // Invalid Catalog Name
+	"3F": KindInternal,    // Invalid Schema Name
+	"40": KindConflict,    // Transaction Rollback
+	"42": KindInternal,    // Syntax Error or Access Rule Violation
+	"44": KindValidation,  // WITH CHECK OPTION Violation
+	"53": KindUnavailable, // Insufficient Resources
+	"54": KindValidation,  // Program Limit Exceeded
+	"55": KindConflict,    // Object Not In Prerequisite State
+	"57": KindUnavailable, // Operator Intervention
+	"58": KindUnavailable, // System Error (errors external to PostgreSQL itself)
+	"72": KindConflict,    // Snapshot Failure
+	"F0": KindInternal,    // Configuration File Error
+	"HV": KindUnavailable, // Foreign Data Wrapper Error (SQL/MED)
+	"P0": KindInternal,    // PL/pgSQL Error
+	"XX": KindInternal,    // Internal Error
+}
+
+// sqlStateCodes overrides class mapping for specific codes and gives them messages.
+var sqlStateCodes = map[string]struct {
+	kind Kind
+	msg  string
+}{
+	"22001": {KindValidation, "value too long"},
+	"22003": {KindValidation, "numeric value out of range"},
+	"22007": {KindValidation, "invalid datetime format"},
+	"22008": {KindValidation, "datetime field overflow"},
+	"22P02": {KindValidation, "invalid text representation"},
+	"23000": {KindConflict, "integrity constraint violation"},
+	"23001": {KindConflict, "restrict violation"},
+	"23502": {KindValidation, "not-null constraint violation"},
+	"23503": {KindValidation, "foreign key violation"},
+	"23505": {KindConflict, "unique constraint violation"},
+	"23514": {KindValidation, "check constraint violation"},
+	"23P01": {KindConflict, "exclusion constraint violation"},
+	"25006": {KindUnavailable, "read only sql transaction"},
+	"40001": {KindConflict, "serialization failure"},
+	"40P01": {KindConflict, "deadlock detected"},
+	"42501": {KindInternal, "insufficient privilege"},
+	"42601": {KindInternal, "syntax error in sql statement"},
+	"55P03": {KindUnavailable, "lock not available"},
+	"57014": {KindUnavailable, "query canceled"},
+	"57P01": {KindUnavailable, "admin shutdown"},
+	"57P03": {KindUnavailable, "cannot connect now"},
+	"P0002": {KindNotFound, "no data found"},
+	"P0003": {KindConflict, "too many rows"},
+}
+
+// FromPostgres converts pgx errors to typed domain errors, repositories
+// call it on every error coming from database. Nil stays nil.
+func FromPostgres(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// already classified, e.g. error returned from nested transaction callback
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return err
+	}
+
+	var (
+		pgErr      *pgconn.PgError
+		connectErr *pgconn.ConnectError
+	)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return NotFound(ErrNoRecord.Error(), err)
+	case errors.Is(err, pgx.ErrTooManyRows):
+		return Conflict("too many rows", err)
+	case errors.As(err, &pgErr):
+		return fromPgError(pgErr, err)
+	case errors.As(err, &connectErr), pgconn.Timeout(err), errors.Is(err, context.DeadlineExceeded):
+		return Unavailable("database is unavailable", err)
+	default:
+		return NewDomainError(KindInternal, "database error", err)
+	}
+}
+
+// fromPgError classifies PgError by its SQLSTATE code then by class,
+// constraint name and detail stay only in wrapped cause.
+func fromPgError(pgErr *pgconn.PgError, cause error) error {
+	domainErr := &DomainError{Kind: KindInternal, Code: pgErr.Code, Message: "database error", Err: cause}
+
+	if code, ok := sqlStateCodes[pgErr.Code]; ok {
+		domainErr.Kind, domainErr.Message = code.kind, code.msg
+	} else if len(pgErr.Code) == 5 {
+		if kind, ok := sqlStateClasses[pgErr.Code[:2]]; ok {
+			domainErr.Kind = kind
+		}
+	}
+
+	return domainErr
+}
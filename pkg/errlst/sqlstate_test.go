@@ -0,0 +1,145 @@
+package errlst
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestFromPostgresSQLState(t *testing.T) {
+	tests := []struct {
+		code     string
+		kind     Kind
+		status   int
+		sentinel error
+	}{
+		// codes with their own entry
+		{code: "23505", kind: KindConflict, status: http.StatusConflict, sentinel: ErrConflict},
+		{code: "23503", kind: KindValidation, status: http.StatusBadRequest, sentinel: ErrBadRequest},
+		{code: "22P02", kind: KindValidation, status: http.StatusBadRequest, sentinel: ErrBadRequest},
+		{code: "40001", kind: KindConflict, status: http.StatusConflict, sentinel: ErrConflict},
+		{code: "57014", kind: KindUnavailable, status: http.StatusServiceUnavailable, sentinel: ErrServiceUnavailable},
+		{code: "42601", kind: KindInternal, status: http.StatusInternalServerError, sentinel: ErrInternalServer},
+		{code: "P0002", kind: KindNotFound, status: http.StatusNotFound, sentinel: ErrNotFound},
+		// codes mapped by their class
+		{code: "23P99", kind: KindValidation, status: http.StatusBadRequest, sentinel: ErrBadRequest},
+		{code: "08006", kind: KindUnavailable, status: http.StatusServiceUnavailable, sentinel: ErrServiceUnavailable},
+		{code: "40P99", kind: KindConflict, status: http.StatusConflict, sentinel: ErrConflict},
+		// unknown class and malformed code
+		{code: "ZZ000", kind: KindInternal, status: http.StatusInternalServerError, sentinel: ErrInternalServer},
+		{code: "235", kind: KindInternal, status: http.StatusInternalServerError, sentinel: ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			pgErr := &pgconn.PgError{Code: tt.code, Message: "secret detail of " + tt.code}
+			err := FromPostgres(fmt.Errorf("repo: %w", pgErr))
+
+			var domainErr *DomainError
+			if !errors.As(err, &domainErr) {
+				t.Fatalf("FromPostgres returned %T, want *DomainError", err)
+			}
+			if domainErr.Kind != tt.kind || domainErr.Code != tt.code {
+				t.Fatalf("kind, code = %v, %q, want %v, %q", domainErr.Kind, domainErr.Code, tt.kind, tt.code)
+			}
+			if !errors.Is(err, tt.sentinel) {
+				t.Fatalf("errors.Is(err, %v) = false", tt.sentinel)
+			}
+
+			var cause *pgconn.PgError
+			if !errors.As(err, &cause) || cause != pgErr {
+				t.Fatal("PgError is not kept as cause")
+			}
+
+			if status := ParseErrors(err).Status(); status != tt.status {
+				t.Fatalf("status = %d, want %d", status, tt.status)
+			}
+		})
+	}
+}
+
+func TestFromPostgresMessage(t *testing.T) {
+	err := FromPostgres(&pgconn.PgError{
+		Code:           "23505",
+		Message:        "duplicate key value violates unique constraint",
+		ConstraintName: "songs_title_key",
+	})
+
+	var domainErr *DomainError
+	if !errors.As(err, &domainErr) || domainErr.Message != "unique constraint violation" {
+		t.Fatalf("FromPostgres = %v, want message of SQLSTATE 23505", err)
+	}
+
+	var cause *pgconn.PgError
+	if !errors.As(err, &cause) || cause.ConstraintName != "songs_title_key" {
+		t.Fatal("constraint name is not kept in cause")
+	}
+}
+
+func TestParseErrorsHidesCause(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{name: "deadline", err: fmt.Errorf("dial 10.0.0.5:5432: %w", context.DeadlineExceeded), status: http.StatusRequestTimeout},
+		{name: "unknown", err: errors.New("open /etc/music/secret.key: permission denied"), status: http.StatusInternalServerError},
+		{name: "unique", err: FromPostgres(&pgconn.PgError{Code: "23505", ConstraintName: "songs_title_key"}), status: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restErr := ParseErrors(tt.err)
+			if restErr.Status() != tt.status {
+				t.Fatalf("status = %d, want %d", restErr.Status(), tt.status)
+			}
+
+			if causes := fmt.Sprint(restErr.Causes()); strings.Contains(causes, "10.0.0.5") ||
+				strings.Contains(causes, "secret.key") || strings.Contains(causes, "songs_title_key") {
+				t.Fatalf("causes = %q, leaks wrapped error", causes)
+			}
+		})
+	}
+}
+
+func TestFromPostgresDriverErrors(t *testing.T) {
+	classified := NotFound("song 1 not found", ErrNotFound)
+
+	tests := []struct {
+		name string
+		err  error
+		kind Kind
+	}{
+		{name: "no rows", err: pgx.ErrNoRows, kind: KindNotFound},
+		{name: "too many rows", err: pgx.ErrTooManyRows, kind: KindConflict},
+		{name: "deadline", err: fmt.Errorf("query: %w", context.DeadlineExceeded), kind: KindUnavailable},
+		{name: "connect", err: &pgconn.ConnectError{}, kind: KindUnavailable},
+		{name: "unknown", err: errors.New("conn busy"), kind: KindInternal},
+		{name: "already classified", err: classified, kind: KindNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromPostgres(tt.err)
+			if got := KindOf(err); got != tt.kind {
+				t.Fatalf("KindOf(FromPostgres(%v)) = %v, want %v", tt.err, got, tt.kind)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("FromPostgres lost cause %v", tt.err)
+			}
+		})
+	}
+
+	if FromPostgres(classified) != classified {
+		t.Fatal("FromPostgres wrapped already classified error")
+	}
+	if FromPostgres(nil) != nil {
+		t.Fatal("FromPostgres(nil) != nil")
+	}
+}
@@ -0,0 +1,88 @@
+package filter
+
+import "strings"
+
+// Operator is comparison operator of filter expression.
+type Operator string
+
+const (
+	OpEq     Operator = "eq"
+	OpNe     Operator = "ne"
+	OpLt     Operator = "lt"
+	OpLe     Operator = "le"
+	OpGt     Operator = "gt"
+	OpGe     Operator = "ge"
+	OpIn     Operator = "in"
+	OpLike   Operator = "like"
+	OpIsNull Operator = "isnull"
+)
+
+// symbolOperators are short forms of operators, longest first
+var symbolOperators = []struct {
+	symbol string
+	op     Operator
+}{
+	{"==", OpEq},
+	{"!=", OpNe},
+	{"<=", OpLe},
+	{">=", OpGe},
+	{"=", OpEq},
+	{"<", OpLt},
+	{">", OpGt},
+	{"~", OpLike},
+}
+
+// namedOperators are FIQL style operators written as =name=
+var namedOperators = map[string]Operator{
+	"eq":     OpEq,
+	"ne":     OpNe,
+	"lt":     OpLt,
+	"le":     OpLe,
+	"gt":     OpGt,
+	"ge":     OpGe,
+	"in":     OpIn,
+	"like":   OpLike,
+	"isnull": OpIsNull,
+}
+
+// Node is node of filter AST, one of *Logical or *Comparison.
+type Node interface {
+	String() string
+}
+
+// Logical joins child nodes with AND or OR.
+type Logical struct {
+	Or    bool
+	Nodes []Node
+}
+
+// String returns expression back in filter syntax.
+func (l *Logical) String() string {
+	sep := ";"
+	if l.Or {
+		sep = ","
+	}
+
+	parts := make([]string, 0, len(l.Nodes))
+	for _, n := range l.Nodes {
+		parts = append(parts, n.String())
+	}
+
+	return "(" + strings.Join(parts, sep) + ")"
+}
+
+// Comparison is single field condition, e.g. release_date>=2000-01-01.
+type Comparison struct {
+	Field  string
+	Op     Operator
+	Values []string
+}
+
+// String returns comparison back in filter syntax.
+func (c *Comparison) String() string {
+	if c.Op == OpIn {
+		return c.Field + "=in=(" + strings.Join(c.Values, ",") + ")"
+	}
+
+	return c.Field + "=" + string(c.Op) + "=" + strings.Join(c.Values, ",")
+}
@@ -0,0 +1,336 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+	"github.com/jumayevgadam/music-app/pkg/releasedate"
+)
+
+// Type is type of filterable field, it decides allowed operators and value parsing.
+type Type int
+
+const (
+	TypeString Type = iota
+	TypeInt
+	TypeDate
+	TypeTime
+	TypeBool
+)
+
+// String is
+func (t Type) String() string {
+	switch t {
+	case TypeInt:
+		return "int"
+	case TypeDate:
+		return "date"
+	case TypeTime:
+		return "time"
+	case TypeBool:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// Field is filterable field of resource.
+type Field struct {
+	// Column is SQL expression of field, it is written to query as it is.
+	Column string
+	Type   Type
+	// Exists is optional subquery without WHERE for fields having many values,
+	// comparison matches when any row of it matches and Column refers to its columns.
+	Exists string
+}
+
+// Schema maps field names clients can filter by to fields, per resource.
+type Schema map[string]Field
+
+// Names returns sorted field names of schema.
+func (s Schema) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// Filter is parsed and validated filter expression ready for SQL.
+// nil *Filter means no filter.
+type Filter struct {
+	root   Node
+	schema Schema
+}
+
+// Parse parses raw filter and validates it against schema.
+// Empty filter gives nil.
+func Parse(raw string, schema Schema) (*Filter, error) {
+	root, err := ParseExpr(raw)
+	if err != nil {
+		return nil, errlst.NewBadQueryParamsError(err.Error())
+	}
+
+	if root == nil {
+		return nil, nil
+	}
+
+	f := &Filter{root: root, schema: schema}
+	if _, _, err := f.compile(root, 1); err != nil {
+		return nil, errlst.NewBadQueryParamsError(err.Error())
+	}
+
+	return f, nil
+}
+
+// And returns filter matching all given filters, nil filters are skipped.
+// Filters must be parsed with the same schema.
+func And(filters ...*Filter) *Filter {
+	var (
+		nodes  []Node
+		schema Schema
+	)
+
+	for _, f := range filters {
+		if f == nil {
+			continue
+		}
+		nodes = append(nodes, f.root)
+		schema = f.schema
+	}
+
+	switch len(nodes) {
+	case 0:
+		return nil
+	case 1:
+		return &Filter{root: nodes[0], schema: schema}
+	default:
+		return &Filter{root: &Logical{Nodes: nodes}, schema: schema}
+	}
+}
+
+// Root returns AST of filter.
+func (f *Filter) Root() Node {
+	if f == nil {
+		return nil
+	}
+
+	return f.root
+}
+
+// String is
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+
+	return f.root.String()
+}
+
+// SQL returns WHERE condition (without keyword) with placeholders starting
+// from argPos, and its args. nil filter gives "TRUE".
+func (f *Filter) SQL(argPos int) (string, []interface{}) {
+	if f == nil {
+		return "TRUE", nil
+	}
+
+	// filter is validated in Parse, so compile can't fail here
+	condition, args, _ := f.compile(f.root, argPos)
+
+	return condition, args
+}
+
+// compile turns node to SQL condition
+func (f *Filter) compile(node Node, argPos int) (string, []interface{}, error) {
+	switch n := node.(type) {
+	case *Logical:
+		var (
+			parts []string
+			args  []interface{}
+		)
+
+		for _, child := range n.Nodes {
+			condition, childArgs, err := f.compile(child, argPos+len(args))
+			if err != nil {
+				return "", nil, err
+			}
+			parts = append(parts, condition)
+			args = append(args, childArgs...)
+		}
+
+		sep := " AND "
+		if n.Or {
+			sep = " OR "
+		}
+
+		return "(" + strings.Join(parts, sep) + ")", args, nil
+	case *Comparison:
+		return f.compileComparison(n, argPos)
+	default:
+		return "", nil, fmt.Errorf("unknown filter node %T", node)
+	}
+}
+
+// sqlOperators are SQL forms of simple comparisons
+var sqlOperators = map[Operator]string{
+	OpEq: "=",
+	OpNe: "<>",
+	OpLt: "<",
+	OpLe: "<=",
+	OpGt: ">",
+	OpGe: ">=",
+}
+
+// compileComparison validates comparison against schema and turns it to SQL
+func (f *Filter) compileComparison(c *Comparison, argPos int) (string, []interface{}, error) {
+	field, ok := f.schema[c.Field]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown filter field %q, allowed values: %s", c.Field, strings.Join(f.schema.Names(), ", "))
+	}
+
+	sql, args, err := compileFieldComparison(c, field, argPos)
+	if err != nil || field.Exists == "" {
+		return sql, args, err
+	}
+
+	return "(EXISTS (" + field.Exists + " WHERE " + sql + "))", args, nil
+}
+
+// compileFieldComparison turns comparison of single valued field to SQL
+func compileFieldComparison(c *Comparison, field Field, argPos int) (string, []interface{}, error) {
+	placeholder := "$" + strconv.Itoa(argPos)
+
+	switch c.Op {
+	case OpIsNull:
+		isNull, err := strconv.ParseBool(c.Values[0])
+		if err != nil {
+			return "", nil, fmt.Errorf("%s=isnull= expects true or false", c.Field)
+		}
+
+		if isNull {
+			return "(" + field.Column + " IS NULL)", nil, nil
+		}
+		return "(" + field.Column + " IS NOT NULL)", nil, nil
+
+	case OpLike:
+		if field.Type != TypeString {
+			return "", nil, fmt.Errorf("like is only allowed on string fields, %s is %s", c.Field, field.Type)
+		}
+
+		return "(" + field.Column + " ILIKE " + placeholder + ")", []interface{}{likePattern(c.Values[0])}, nil
+
+	case OpIn:
+		values, err := parseValues(c.Field, field.Type, c.Values)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return "(" + field.Column + " = ANY(" + placeholder + "))", []interface{}{values}, nil
+
+	case OpLt, OpLe, OpGt, OpGe:
+		if field.Type == TypeBool {
+			return "", nil, fmt.Errorf("%s is not allowed on bool field %s", c.Op, c.Field)
+		}
+		fallthrough
+
+	default:
+		value, err := parseValue(c.Field, field.Type, c.Values[0])
+		if err != nil {
+			return "", nil, err
+		}
+
+		return "(" + field.Column + " " + sqlOperators[c.Op] + " " + placeholder + ")", []interface{}{value}, nil
+	}
+}
+
+// likePattern escapes LIKE wildcards of value, '*' becomes '%'.
+// Value without '*' matches as substring.
+func likePattern(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+
+	if !strings.Contains(escaped, "*") {
+		return "%" + escaped + "%"
+	}
+
+	return strings.ReplaceAll(escaped, "*", "%")
+}
+
+// parseValues parses list of =in= values to typed slice
+func parseValues(name string, typ Type, raw []string) (interface{}, error) {
+	switch typ {
+	case TypeInt:
+		values := make([]int, 0, len(raw))
+		for _, r := range raw {
+			v, err := parseValue(name, typ, r)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v.(int))
+		}
+		return values, nil
+	case TypeDate, TypeTime:
+		values := make([]time.Time, 0, len(raw))
+		for _, r := range raw {
+			v, err := parseValue(name, typ, r)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v.(time.Time))
+		}
+		return values, nil
+	case TypeBool:
+		values := make([]bool, 0, len(raw))
+		for _, r := range raw {
+			v, err := parseValue(name, typ, r)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v.(bool))
+		}
+		return values, nil
+	default:
+		return raw, nil
+	}
+}
+
+// parseValue parses value by field type
+func parseValue(name string, typ Type, raw string) (interface{}, error) {
+	switch typ {
+	case TypeInt:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects integer, got %q", name, raw)
+		}
+		return v, nil
+	case TypeDate:
+		// release dates and filters accept same formats, year-only value is its first day
+		v, err := releasedate.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects date as YYYY-MM-DD, DD.MM.YYYY, YYYY-MM or YYYY, got %q", name, raw)
+		}
+		return v.Time, nil
+	case TypeTime:
+		if v, err := time.Parse(time.RFC3339, raw); err == nil {
+			return v, nil
+		}
+		v, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects RFC 3339 time or YYYY-MM-DD, got %q", name, raw)
+		}
+		return v, nil
+	case TypeBool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s expects true or false, got %q", name, raw)
+		}
+		return v, nil
+	default:
+		return raw, nil
+	}
+}
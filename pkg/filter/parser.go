@@ -0,0 +1,299 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Filter syntax is FIQL-like:
+//
+//	filter=release_date>=2000-01-01;group~muse,title=Hysteria
+//
+// ';' is AND, ',' is OR, AND binds tighter than OR and parentheses group.
+// Operators are ==, =, !=, <, <=, >, >=, ~ (like) and their named forms
+// =eq=, =ne=, =lt=, =le=, =gt=, =ge=, =like=, plus =in=(a,b) and =isnull=true|false.
+// Values containing reserved characters are quoted with ' or ".
+
+const (
+	// maxComparisons limits size of expression
+	maxComparisons = 20
+	// maxDepth limits nesting of parentheses
+	maxDepth = 5
+)
+
+// parser is recursive descent parser of filter expression
+type parser struct {
+	input       []rune
+	pos         int
+	depth       int
+	comparisons int
+}
+
+// ParseExpr parses raw filter into AST without schema validation.
+// Empty input gives nil node.
+func ParseExpr(raw string) (Node, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	p := &parser{input: []rune(raw)}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q", p.peek())
+	}
+
+	return node, nil
+}
+
+func (p *parser) parseOr() (Node, error) {
+	return p.parseLogical(true)
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	return p.parseLogical(false)
+}
+
+// parseLogical parses operands joined by ',' (or) or ';' (and)
+func (p *parser) parseLogical(or bool) (Node, error) {
+	sep, next := ';', p.parseTerm
+	if or {
+		sep, next = ',', p.parseAnd
+	}
+
+	first, err := next()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{first}
+	for {
+		p.skipSpaces()
+		if p.eof() || p.peek() != sep {
+			break
+		}
+		p.pos++
+
+		node, err := next()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return first, nil
+	}
+
+	return &Logical{Or: or, Nodes: nodes}, nil
+}
+
+// parseTerm parses parenthesized expression or comparison
+func (p *parser) parseTerm() (Node, error) {
+	p.skipSpaces()
+	if p.eof() {
+		return nil, p.errorf("unexpected end of filter")
+	}
+
+	if p.peek() != '(' {
+		return p.parseComparison()
+	}
+
+	p.pos++
+	if p.depth++; p.depth > maxDepth {
+		return nil, p.errorf("filter is nested too deep, max depth is %d", maxDepth)
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if p.eof() || p.peek() != ')' {
+		return nil, p.errorf("missing closing parenthesis")
+	}
+	p.pos++
+	p.depth--
+
+	return node, nil
+}
+
+// parseComparison parses field, operator and value(s)
+func (p *parser) parseComparison() (Node, error) {
+	if p.comparisons++; p.comparisons > maxComparisons {
+		return nil, p.errorf("filter has too many conditions, max is %d", maxComparisons)
+	}
+
+	field := p.parseField()
+	if field == "" {
+		return nil, p.errorf("field name is expected")
+	}
+
+	op, err := p.parseOperator()
+	if err != nil {
+		return nil, err
+	}
+
+	cmp := &Comparison{Field: field, Op: op}
+
+	p.skipSpaces()
+	if op == OpIn {
+		if p.eof() || p.peek() != '(' {
+			return nil, p.errorf("=in= expects list of values in parentheses")
+		}
+		p.pos++
+
+		for {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			cmp.Values = append(cmp.Values, value)
+
+			p.skipSpaces()
+			if p.eof() {
+				return nil, p.errorf("missing closing parenthesis of =in= list")
+			}
+			if p.peek() == ')' {
+				p.pos++
+				break
+			}
+			if p.peek() != ',' {
+				return nil, p.errorf("unexpected %q in =in= list", p.peek())
+			}
+			p.pos++
+		}
+
+		return cmp, nil
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	cmp.Values = []string{value}
+
+	return cmp, nil
+}
+
+// parseField reads field name, letters, digits, '_' and '.'
+func (p *parser) parseField() string {
+	p.skipSpaces()
+
+	start := p.pos
+	for !p.eof() {
+		r := p.peek()
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' {
+			break
+		}
+		p.pos++
+	}
+
+	return string(p.input[start:p.pos])
+}
+
+// parseOperator reads symbol operator or FIQL =name= operator
+func (p *parser) parseOperator() (Operator, error) {
+	p.skipSpaces()
+	rest := string(p.input[p.pos:])
+
+	// =name= is only operator when name is known, so title=abc=def stays eq
+	if strings.HasPrefix(rest, "=") {
+		if name, _, ok := strings.Cut(rest[1:], "="); ok {
+			if op, known := namedOperators[strings.ToLower(name)]; known {
+				p.pos += len([]rune(name)) + 2
+				return op, nil
+			}
+		}
+	}
+
+	for _, s := range symbolOperators {
+		if strings.HasPrefix(rest, s.symbol) {
+			p.pos += len(s.symbol)
+			return s.op, nil
+		}
+	}
+
+	return "", p.errorf("operator is expected")
+}
+
+// parseValue reads quoted or bare value, bare value ends on ; , ( )
+func (p *parser) parseValue() (string, error) {
+	p.skipSpaces()
+	if p.eof() {
+		return "", p.errorf("value is expected")
+	}
+
+	if quote := p.peek(); quote == '\'' || quote == '"' {
+		p.pos++
+
+		var b strings.Builder
+		for {
+			if p.eof() {
+				return "", p.errorf("unterminated quoted value")
+			}
+
+			r := p.peek()
+			p.pos++
+
+			switch {
+			case r == '\\' && !p.eof():
+				b.WriteRune(p.peek())
+				p.pos++
+			case r == quote:
+				return b.String(), nil
+			default:
+				b.WriteRune(r)
+			}
+		}
+	}
+
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(";,()", p.peek()) {
+		p.pos++
+	}
+
+	value := strings.TrimSpace(string(p.input[start:p.pos]))
+	if value == "" {
+		return "", p.errorf("value is expected")
+	}
+
+	return value, nil
+}
+
+func (p *parser) skipSpaces() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() rune {
+	return p.input[p.pos]
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+// errorf returns syntax error pointing to position in filter
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// SyntaxError is returned when filter can't be parsed.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+// Error is
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("filter syntax error at position %d: %s", e.Pos, e.Msg)
+}
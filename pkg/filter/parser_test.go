@@ -0,0 +1,307 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSchema = Schema{
+	"id":           {Column: "songs.id", Type: TypeInt},
+	"group":        {Column: `songs."group"`, Type: TypeString},
+	"title":        {Column: "songs.title", Type: TypeString},
+	"release_date": {Column: "songs.release_date", Type: TypeDate},
+	"text":         {Column: "lyrics.text", Type: TypeString, Exists: "SELECT 1 FROM lyrics"},
+	"created_at":   {Column: "songs.created_at", Type: TypeTime},
+	"explicit":     {Column: "songs.explicit", Type: TypeBool},
+}
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "single", raw: "title==Hysteria", want: "title=eq=Hysteria"},
+		{name: "and binds tighter than or", raw: "id==1,id==2;id==3", want: "(id=eq=1,(id=eq=2;id=eq=3))"},
+		{name: "and before or", raw: "id==1;id==2,id==3", want: "((id=eq=1;id=eq=2),id=eq=3)"},
+		{name: "parentheses group", raw: "(id==1,id==2);id==3", want: "((id=eq=1,id=eq=2);id=eq=3)"},
+		{name: "nested parentheses", raw: "((id==1))", want: "id=eq=1"},
+		{name: "spaces", raw: "  title == Hysteria ;  id > 2 ", want: "(title=eq=Hysteria;id=gt=2)"},
+		{name: "symbol operators", raw: "id!=1;id<2;id<=3;id>4;id>=5;title~x;title=y", want: "(id=ne=1;id=lt=2;id=le=3;id=gt=4;id=ge=5;title=like=x;title=eq=y)"},
+		{name: "named operators", raw: "id=GE=1;title=like=x;group=isnull=true", want: "(id=ge=1;title=like=x;group=isnull=true)"},
+		{name: "in list", raw: "id=in=( 1, 2 ,3)", want: "id=in=(1,2,3)"},
+		{name: "unknown named operator is value", raw: "title=abc=def", want: "title=eq=abc=def"},
+		{name: "single quoted reserved characters", raw: "title=='a;b,(c)'", want: "title=eq=a;b,(c)"},
+		{name: "double quoted", raw: `title=="Don't stop"`, want: "title=eq=Don't stop"},
+		{name: "escaped quote", raw: `title=='O\'Neil'`, want: "title=eq=O'Neil"},
+		{name: "escaped backslash", raw: `title=='a\\b'`, want: `title=eq=a\b`},
+		{name: "quoted spaces are kept", raw: "title==' x '", want: "title=eq= x "},
+		{name: "quoted in list", raw: "group=in=('a,b',c)", want: "group=in=(a,b,c)"},
+		{name: "unicode", raw: "group==Мукам", want: "group=eq=Мукам"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := ParseExpr(tt.raw)
+			if err != nil {
+				t.Fatalf("ParseExpr(%q): %v", tt.raw, err)
+			}
+
+			if got := node.String(); got != tt.want {
+				t.Fatalf("ParseExpr(%q) = %s, want %s", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExprEmpty(t *testing.T) {
+	for _, raw := range []string{"", "   "} {
+		node, err := ParseExpr(raw)
+		if err != nil || node != nil {
+			t.Fatalf("ParseExpr(%q) = %v, %v, want nil, nil", raw, node, err)
+		}
+	}
+}
+
+func TestParseExprSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		msg  string
+	}{
+		{name: "missing operator", raw: "title", msg: "operator is expected"},
+		{name: "missing field", raw: "==x", msg: "field name is expected"},
+		{name: "missing value", raw: "title==", msg: "value is expected"},
+		{name: "empty value before separator", raw: "title==;id==1", msg: "value is expected"},
+		{name: "trailing separator", raw: "title==x;", msg: "unexpected end of filter"},
+		{name: "missing closing parenthesis", raw: "(title==x", msg: "missing closing parenthesis"},
+		{name: "extra closing parenthesis", raw: "title==x)", msg: `unexpected ')'`},
+		{name: "unterminated quote", raw: "title=='x", msg: "unterminated quoted value"},
+		{name: "in without list", raw: "id=in=1", msg: "=in= expects list"},
+		{name: "unterminated in list", raw: "id=in=(1,2", msg: "missing closing parenthesis of =in= list"},
+		{name: "garbage after quoted value in list", raw: "id=in=('1' 2)", msg: "unexpected '2' in =in= list"},
+		{name: "too deep", raw: "((((((id==1))))))", msg: "nested too deep"},
+		{name: "too many conditions", raw: strings.Repeat("id==1;", maxComparisons) + "id==1", msg: "too many conditions"},
+		{name: "sql in field name", raw: "title;DROP TABLE songs==1", msg: "operator is expected"},
+		{name: "comment in field name", raw: "title--==1", msg: "operator is expected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseExpr(tt.raw)
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("ParseExpr(%q) error = %v, want SyntaxError", tt.raw, err)
+			}
+			if !strings.Contains(syntaxErr.Msg, tt.msg) {
+				t.Fatalf("ParseExpr(%q) error = %q, want it to contain %q", tt.raw, syntaxErr.Msg, tt.msg)
+			}
+		})
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		msg  string
+	}{
+		{name: "unknown field", raw: "password==x", msg: `unknown filter field "password"`},
+		{name: "column name isn't field", raw: "songs.title==x", msg: `unknown filter field "songs.title"`},
+		{name: "unknown field inside or", raw: "title==x,secret==y", msg: `unknown filter field "secret"`},
+		{name: "like on int", raw: "id~1", msg: "like is only allowed on string fields"},
+		{name: "like on date", raw: "release_date=like=2000", msg: "like is only allowed on string fields"},
+		{name: "order on bool", raw: "explicit>true", msg: "gt is not allowed on bool field"},
+		{name: "isnull needs bool", raw: "group=isnull=maybe", msg: "expects true or false"},
+		{name: "int value", raw: "id==one", msg: "id expects integer"},
+		{name: "int injection", raw: "id=='1 OR 1=1'", msg: "id expects integer"},
+		{name: "int in list", raw: "id=in=(1,x)", msg: "id expects integer"},
+		{name: "date value", raw: "release_date>=31.02.2020", msg: "release_date expects date"},
+		{name: "time value", raw: "created_at>=yesterday", msg: "created_at expects RFC 3339 time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(tt.raw, testSchema)
+			if err == nil {
+				t.Fatalf("Parse(%q) = %v, want error", tt.raw, f)
+			}
+			if !strings.Contains(err.Error(), tt.msg) {
+				t.Fatalf("Parse(%q) error = %v, want it to contain %q", tt.raw, err, tt.msg)
+			}
+		})
+	}
+}
+
+func TestFilterSQL(t *testing.T) {
+	year2000 := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		raw      string
+		argPos   int
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "eq",
+			raw:      "title==Hysteria",
+			argPos:   1,
+			wantSQL:  "(songs.title = $1)",
+			wantArgs: []interface{}{"Hysteria"},
+		},
+		{
+			name:     "placeholders start from argPos",
+			raw:      "id>=10;id<20",
+			argPos:   3,
+			wantSQL:  "((songs.id >= $3) AND (songs.id < $4))",
+			wantArgs: []interface{}{10, 20},
+		},
+		{
+			name:     "precedence",
+			raw:      "release_date>=2000;group~muse,title==Hysteria",
+			argPos:   1,
+			wantSQL:  `(((songs.release_date >= $1) AND (songs."group" ILIKE $2)) OR (songs.title = $3))`,
+			wantArgs: []interface{}{year2000, "%muse%", "Hysteria"},
+		},
+		{
+			name:     "ne",
+			raw:      "group!=Muse",
+			argPos:   1,
+			wantSQL:  `(songs."group" <> $1)`,
+			wantArgs: []interface{}{"Muse"},
+		},
+		{
+			name:     "in",
+			raw:      "id=in=(1,2,3)",
+			argPos:   1,
+			wantSQL:  "(songs.id = ANY($1))",
+			wantArgs: []interface{}{[]int{1, 2, 3}},
+		},
+		{
+			name:     "string in",
+			raw:      "group=in=(Muse,'AC/DC')",
+			argPos:   2,
+			wantSQL:  `(songs."group" = ANY($2))`,
+			wantArgs: []interface{}{[]string{"Muse", "AC/DC"}},
+		},
+		{
+			name:    "isnull",
+			raw:     "group=isnull=true;title=isnull=false",
+			argPos:  1,
+			wantSQL: `((songs."group" IS NULL) AND (songs.title IS NOT NULL))`,
+		},
+		{
+			name:     "like wildcard",
+			raw:      "title~hyst*",
+			argPos:   1,
+			wantSQL:  "(songs.title ILIKE $1)",
+			wantArgs: []interface{}{"hyst%"},
+		},
+		{
+			name:     "like escapes sql wildcards",
+			raw:      `title~'100%_\\'`,
+			argPos:   1,
+			wantSQL:  "(songs.title ILIKE $1)",
+			wantArgs: []interface{}{`%100\%\_\\%`},
+		},
+		{
+			name:     "bool",
+			raw:      "explicit==true",
+			argPos:   1,
+			wantSQL:  "(songs.explicit = $1)",
+			wantArgs: []interface{}{true},
+		},
+		{
+			name:     "date",
+			raw:      "release_date<2006-07-16",
+			argPos:   1,
+			wantSQL:  "(songs.release_date < $1)",
+			wantArgs: []interface{}{time.Date(2006, time.July, 16, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "date precision",
+			raw:      "release_date<16.07.2006",
+			argPos:   1,
+			wantSQL:  "(songs.release_date < $1)",
+			wantArgs: []interface{}{time.Date(2006, time.July, 16, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "exists field",
+			raw:      "text~love",
+			argPos:   1,
+			wantSQL:  "(EXISTS (SELECT 1 FROM lyrics WHERE (lyrics.text ILIKE $1)))",
+			wantArgs: []interface{}{"%love%"},
+		},
+		{
+			name:     "injection through quoted value stays argument",
+			raw:      `title=='x\'); DROP TABLE songs; --'`,
+			argPos:   1,
+			wantSQL:  "(songs.title = $1)",
+			wantArgs: []interface{}{"x'); DROP TABLE songs; --"},
+		},
+		{
+			name:     "injection through bare value stays argument",
+			raw:      "title==x' OR '1'='1",
+			argPos:   1,
+			wantSQL:  "(songs.title = $1)",
+			wantArgs: []interface{}{"x' OR '1'='1"},
+		},
+		{
+			name:     "injection through in list stays argument",
+			raw:      `group=in=('a); DELETE FROM songs; --',b)`,
+			argPos:   1,
+			wantSQL:  `(songs."group" = ANY($1))`,
+			wantArgs: []interface{}{[]string{"a); DELETE FROM songs; --", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(tt.raw, testSchema)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.raw, err)
+			}
+
+			gotSQL, gotArgs := f.SQL(tt.argPos)
+			if gotSQL != tt.wantSQL {
+				t.Fatalf("SQL = %s, want %s", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Fatalf("args = %#v, want %#v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestNilFilterSQL(t *testing.T) {
+	f, err := Parse("", testSchema)
+	if err != nil || f != nil {
+		t.Fatalf("Parse(\"\") = %v, %v, want nil, nil", f, err)
+	}
+
+	if sql, args := f.SQL(1); sql != "TRUE" || args != nil {
+		t.Fatalf("nil filter SQL = %s, %v, want TRUE, nil", sql, args)
+	}
+}
+
+func TestAnd(t *testing.T) {
+	first, _ := Parse("id==1,id==2", testSchema)
+	second, _ := Parse("title==x", testSchema)
+
+	sql, args := And(first, nil, second).SQL(1)
+	if want := "(((songs.id = $1) OR (songs.id = $2)) AND (songs.title = $3))"; sql != want {
+		t.Fatalf("And SQL = %s, want %s", sql, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{1, 2, "x"}) {
+		t.Fatalf("And args = %#v", args)
+	}
+
+	if And(nil, nil) != nil {
+		t.Fatal("And of nil filters must be nil")
+	}
+}
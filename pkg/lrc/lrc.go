@@ -0,0 +1,241 @@
+package lrc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Package lrc parses and formats LRC lyrics, every line is prefixed with one or
+// more [mm:ss.xx] timestamps. Metadata tags like [ar:Muse] are kept, [offset:+500]
+// is applied to timestamps while parsing. Lines are sorted by time, lines sharing
+// several timestamps (choruses) are repeated for each of them.
+
+// maxLineSize limits one line of LRC file
+const maxLineSize = 64 << 10
+
+var (
+	// ErrNoLines is returned for files without timed lines
+	ErrNoLines = errors.New("lyrics have no timed lines")
+
+	timestampPattern = regexp.MustCompile(`^(\d+):(\d{2})(?:[.:](\d{1,3}))?$`)
+	tagPattern       = regexp.MustCompile(`^([A-Za-z#]+):(.*)$`)
+	// wordTimestamps are per word timings of enhanced LRC, e.g. <00:12.30>
+	wordTimestamps = regexp.MustCompile(`<\d+:\d{2}(?:[.:]\d{1,3})?>`)
+)
+
+// Line is
+type Line struct {
+	Time time.Duration
+	Text string
+}
+
+// Tag is metadata tag, e.g. ar for artist and ti for title
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Lyrics is
+type Lyrics struct {
+	Tags  []Tag
+	Lines []Line
+}
+
+// ParseError is
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+// Error is
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("lrc line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap is
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Parse reads LRC file, it fails on malformed timestamps and on text lines
+// without timestamp
+func Parse(r io.Reader) (*Lyrics, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineSize)
+
+	var (
+		lyrics Lyrics
+		offset time.Duration
+		row    int
+	)
+
+	for scanner.Scan() {
+		row++
+
+		line := strings.TrimSpace(scanner.Text())
+		if row == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" {
+			continue
+		}
+
+		times, rest, tag, err := parsePrefix(line)
+		if err != nil {
+			return nil, &ParseError{Line: row, Err: err}
+		}
+
+		if tag != nil {
+			if tag.Key == "offset" {
+				ms, err := strconv.Atoi(strings.TrimPrefix(tag.Value, "+"))
+				if err != nil {
+					return nil, &ParseError{Line: row, Err: fmt.Errorf("offset must be milliseconds, got %q", tag.Value)}
+				}
+				offset = time.Duration(ms) * time.Millisecond
+				continue
+			}
+			lyrics.Tags = append(lyrics.Tags, *tag)
+			continue
+		}
+
+		if len(times) == 0 {
+			return nil, &ParseError{Line: row, Err: errors.New("line has no timestamp")}
+		}
+
+		for _, t := range times {
+			lyrics.Lines = append(lyrics.Lines, Line{Time: t, Text: rest})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, &ParseError{Line: row + 1, Err: err}
+	}
+
+	if len(lyrics.Lines) == 0 {
+		return nil, ErrNoLines
+	}
+
+	// positive offset shows lyrics sooner
+	for i := range lyrics.Lines {
+		lyrics.Lines[i].Time = max(lyrics.Lines[i].Time-offset, 0)
+	}
+
+	sort.SliceStable(lyrics.Lines, func(i, j int) bool {
+		return lyrics.Lines[i].Time < lyrics.Lines[j].Time
+	})
+
+	return &lyrics, nil
+}
+
+// parsePrefix splits leading timestamps of line from its text, line which is
+// single metadata tag is returned as tag
+func parsePrefix(line string) ([]time.Duration, string, *Tag, error) {
+	var times []time.Duration
+	rest := line
+
+	for strings.HasPrefix(rest, "[") {
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return nil, "", nil, fmt.Errorf("unclosed bracket in %q", line)
+		}
+
+		content := strings.TrimSpace(rest[1:end])
+		if timestampPattern.MatchString(content) {
+			t, err := ParseTimestamp(content)
+			if err != nil {
+				return nil, "", nil, err
+			}
+			times = append(times, t)
+			rest = rest[end+1:]
+			continue
+		}
+
+		if match := tagPattern.FindStringSubmatch(content); match != nil && len(times) == 0 {
+			if strings.TrimSpace(rest[end+1:]) != "" {
+				return nil, "", nil, fmt.Errorf("text after tag [%s]", content)
+			}
+			return nil, "", &Tag{Key: strings.ToLower(match[1]), Value: strings.TrimSpace(match[2])}, nil
+		}
+
+		return nil, "", nil, fmt.Errorf("invalid timestamp [%s], expected [mm:ss.xx]", content)
+	}
+
+	return times, strings.TrimSpace(rest), nil, nil
+}
+
+// ParseTimestamp parses mm:ss, mm:ss.x, mm:ss.xx or mm:ss.xxx
+func ParseTimestamp(raw string) (time.Duration, error) {
+	match := timestampPattern.FindStringSubmatch(raw)
+	if match == nil {
+		return 0, fmt.Errorf("invalid timestamp %q", raw)
+	}
+
+	minutes, _ := strconv.Atoi(match[1])
+	seconds, _ := strconv.Atoi(match[2])
+	if seconds > 59 {
+		return 0, fmt.Errorf("seconds of timestamp %q are out of range", raw)
+	}
+
+	// one digit is tenths, two are hundredths and three are milliseconds
+	var fraction time.Duration
+	if match[3] != "" {
+		ms, _ := strconv.Atoi((match[3] + "00")[:3])
+		fraction = time.Duration(ms) * time.Millisecond
+	}
+
+	return time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second + fraction, nil
+}
+
+// FormatTimestamp returns mm:ss.xx, milliseconds are kept when hundredths lose them
+func FormatTimestamp(t time.Duration) string {
+	ms := t.Milliseconds()
+	minutes, seconds, millis := ms/60000, ms/1000%60, ms%1000
+
+	if millis%10 != 0 {
+		return fmt.Sprintf("%02d:%02d.%03d", minutes, seconds, millis)
+	}
+
+	return fmt.Sprintf("%02d:%02d.%02d", minutes, seconds, millis/10)
+}
+
+// String formats lyrics as LRC, tags first
+func (l *Lyrics) String() string {
+	var b strings.Builder
+
+	for _, tag := range l.Tags {
+		fmt.Fprintf(&b, "[%s:%s]\n", tag.Key, tag.Value)
+	}
+	for _, line := range l.Lines {
+		fmt.Fprintf(&b, "[%s]%s\n", FormatTimestamp(line.Time), line.Text)
+	}
+
+	return b.String()
+}
+
+// Plain returns text of lyrics without timings, runs of empty lines (instrumental
+// breaks) become one empty line
+func (l *Lyrics) Plain() string {
+	lines := make([]string, 0, len(l.Lines))
+
+	for _, line := range l.Lines {
+		text := strings.Join(strings.Fields(wordTimestamps.ReplaceAllString(line.Text, "")), " ")
+		if text == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+			continue
+		}
+		lines = append(lines, text)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// LineAt returns index of line sung at t, -1 before first line
+func (l *Lyrics) LineAt(t time.Duration) int {
+	return sort.Search(len(l.Lines), func(i int) bool { return l.Lines[i].Time > t }) - 1
+}
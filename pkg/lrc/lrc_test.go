@@ -0,0 +1,170 @@
+package lrc
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ms(n int) time.Duration {
+	return time.Duration(n) * time.Millisecond
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		tags  []Tag
+		lines []Line
+	}{
+		{
+			name:  "fractions",
+			input: "[00:01.5]tenths\n[00:02.25]hundredths\n[00:03.125]millis\n[00:04:50]colon",
+			lines: []Line{{ms(1500), "tenths"}, {ms(2250), "hundredths"}, {ms(3125), "millis"}, {ms(4500), "colon"}},
+		},
+		{
+			name:  "without fractions",
+			input: "[01:02]minute\n[00:00]start\n[123:00]long",
+			lines: []Line{{0, "start"}, {ms(62000), "minute"}, {123 * time.Minute, "long"}},
+		},
+		{
+			name:  "multi timestamp line",
+			input: "[00:10.00]verse\n[00:05.00][00:20.00] chorus \n",
+			lines: []Line{{ms(5000), "chorus"}, {ms(10000), "verse"}, {ms(20000), "chorus"}},
+		},
+		{
+			name:  "out of order",
+			input: "[00:30.00]third\n[00:10.00]first\n[00:20.00]second\n[00:10.00]first again",
+			lines: []Line{{ms(10000), "first"}, {ms(10000), "first again"}, {ms(20000), "second"}, {ms(30000), "third"}},
+		},
+		{
+			name:  "metadata tags",
+			input: "\ufeff[ti: Hysteria ]\n[AR:Muse]\n[#:comment]\n\n[00:01.00]line",
+			tags:  []Tag{{"ti", "Hysteria"}, {"ar", "Muse"}, {"#", "comment"}},
+			lines: []Line{{ms(1000), "line"}},
+		},
+		{
+			name:  "positive offset shows lines sooner",
+			input: "[offset:+500]\n[00:00.20]clamped\n[00:01.00]line",
+			lines: []Line{{0, "clamped"}, {ms(500), "line"}},
+		},
+		{
+			name:  "negative offset shows lines later",
+			input: "[00:01.00]line\n[offset:-250]",
+			lines: []Line{{ms(1250), "line"}},
+		},
+		{
+			name:  "empty lines are instrumental breaks",
+			input: "[00:01.00]line\n[00:02.00]\n[00:03.00]<00:03.00>word <00:03.50>timings",
+			lines: []Line{{ms(1000), "line"}, {ms(2000), ""}, {ms(3000), "<00:03.00>word <00:03.50>timings"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(got.Tags, tt.tags) {
+				t.Fatalf("tags = %+v, want %+v", got.Tags, tt.tags)
+			}
+			if !reflect.DeepEqual(got.Lines, tt.lines) {
+				t.Fatalf("lines = %+v, want %+v", got.Lines, tt.lines)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  int
+		msg   string
+	}{
+		{name: "text without timestamp", input: "[00:01.00]ok\nplain text", line: 2, msg: "line has no timestamp"},
+		{name: "unclosed bracket", input: "[00:01.00", line: 1, msg: "unclosed bracket"},
+		{name: "seconds out of range", input: "\n[00:60.00]x", line: 2, msg: "out of range"},
+		{name: "letters in timestamp", input: "[0a:12.00]x", line: 1, msg: "invalid timestamp"},
+		{name: "too long fraction", input: "[00:01.1234]x", line: 1, msg: "invalid timestamp"},
+		{name: "tag after timestamp", input: "[00:01.00][ar:Muse]", line: 1, msg: "invalid timestamp"},
+		{name: "text after tag", input: "[ar:Muse] Hysteria", line: 1, msg: "text after tag"},
+		{name: "bad offset", input: "[offset:soon]\n[00:01.00]x", line: 1, msg: "offset must be milliseconds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse error = %v, want ParseError", err)
+			}
+			if parseErr.Line != tt.line || !strings.Contains(parseErr.Error(), tt.msg) {
+				t.Fatalf("Parse error = %v, want line %d with %q", err, tt.line, tt.msg)
+			}
+		})
+	}
+}
+
+func TestParseNoLines(t *testing.T) {
+	for _, input := range []string{"", "\n\n", "[ar:Muse]\n[ti:Hysteria]"} {
+		if _, err := Parse(strings.NewReader(input)); !errors.Is(err, ErrNoLines) {
+			t.Errorf("Parse(%q) error = %v, want ErrNoLines", input, err)
+		}
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	input := "[ar:Muse]\n[00:01.00]one\n[00:02.005]two\n[01:30.50]three\n"
+
+	lyrics, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := lyrics.String(); got != input {
+		t.Fatalf("String() = %q, want %q", got, input)
+	}
+}
+
+func TestPlain(t *testing.T) {
+	lyrics := &Lyrics{Lines: []Line{
+		{0, ""},
+		{ms(1000), "first  line"},
+		{ms(2000), ""},
+		{ms(3000), ""},
+		{ms(4000), "<00:04.00>second <00:04.50>line"},
+		{ms(5000), ""},
+	}}
+
+	if got, want := lyrics.Plain(), "first line\n\nsecond line"; got != want {
+		t.Fatalf("Plain() = %q, want %q", got, want)
+	}
+}
+
+func TestLineAt(t *testing.T) {
+	lyrics := &Lyrics{Lines: []Line{{ms(1000), "one"}, {ms(2000), "two"}, {ms(2000), "two again"}, {ms(5000), "three"}}}
+
+	tests := []struct {
+		at   time.Duration
+		want int
+	}{
+		{at: 0, want: -1},
+		{at: ms(999), want: -1},
+		{at: ms(1000), want: 0},
+		{at: ms(1999), want: 0},
+		{at: ms(2000), want: 2},
+		{at: ms(4999), want: 2},
+		{at: ms(5000), want: 3},
+		{at: time.Hour, want: 3},
+	}
+
+	for _, tt := range tests {
+		if got := lyrics.LineAt(tt.at); got != tt.want {
+			t.Errorf("LineAt(%v) = %d, want %d", tt.at, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+)
+
+// Keyset (cursor) pagination works alongside page/size. Cursor keeps sort key
+// values and id of the row at the edge of a page, so the next query continues
+// with WHERE (sort_key, id) < (...) instead of OFFSET. Cursors are opaque to
+// clients and signed, so they can't be forged to scan arbitrary positions.
+
+var cursorSecret atomic.Value
+
+func init() {
+	cursorSecret.Store([]byte{})
+}
+
+// SetCursorSecret sets key used to sign cursors, call it once on startup.
+func SetCursorSecret(secret []byte) {
+	cursorSecret.Store(secret)
+}
+
+// ErrInvalidCursor is returned when cursor can't be decoded or its signature is wrong.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is position of a row at the edge of a page.
+type Cursor struct {
+	// Keys are values of sort columns of the row, in sort order.
+	Keys []string `json:"k,omitempty"`
+	// ID is tie breaker, rows with equal sort keys are ordered by id.
+	ID int `json:"id"`
+	// Prev is true when cursor asks for rows before the position.
+	Prev bool `json:"p,omitempty"`
+	// Sort is the sort specification cursor was made for.
+	Sort string `json:"s"`
+}
+
+// EncodeCursor returns opaque signed string of cursor.
+func EncodeCursor(c Cursor) string {
+	payload, _ := json.Marshal(c)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + sign(encoded)
+}
+
+// DecodeCursor verifies signature and decodes cursor.
+func DecodeCursor(s string) (*Cursor, error) {
+	encoded, signature, ok := strings.Cut(s, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(sign(encoded))) {
+		return nil, errlst.Validation("cursor signature doesn't match", ErrInvalidCursor)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errlst.Validation(ErrInvalidCursor.Error(), fmt.Errorf("decode cursor: %w", err))
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, errlst.Validation(ErrInvalidCursor.Error(), fmt.Errorf("unmarshal cursor: %w", err))
+	}
+
+	return &c, nil
+}
+
+// sign returns base64 HMAC-SHA256 of encoded payload.
+func sign(encoded string) string {
+	mac := hmac.New(sha256.New, cursorSecret.Load().([]byte))
+	mac.Write([]byte(encoded))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,121 @@
+package pagination
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var testSortFields = SortWhitelist{
+	"id":    "songs.id",
+	"title": "songs.title",
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	SetCursorSecret([]byte("test-secret"))
+
+	want := Cursor{Keys: []string{"Yesterday"}, ID: 42, Prev: true, Sort: "-title"}
+
+	got, err := DecodeCursor(EncodeCursor(want))
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+
+	if got.ID != want.ID || got.Prev != want.Prev || got.Sort != want.Sort ||
+		len(got.Keys) != 1 || got.Keys[0] != want.Keys[0] {
+		t.Fatalf("DecodeCursor = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeCursorRejectsTampering(t *testing.T) {
+	SetCursorSecret([]byte("test-secret"))
+
+	encoded, signature, _ := strings.Cut(EncodeCursor(Cursor{ID: 1, Sort: "title"}), ".")
+	forged, _, _ := strings.Cut(EncodeCursor(Cursor{ID: 1000, Sort: "title"}), ".")
+
+	tests := map[string]string{
+		"no signature":        encoded,
+		"wrong signature":     encoded + "." + strings.Repeat("A", len(signature)),
+		"swapped payload":     forged + "." + signature,
+		"empty":               "",
+		"garbage":             "not-a-cursor",
+		"truncated signature": encoded + "." + signature[:len(signature)-1],
+	}
+
+	for name, cursor := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := DecodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", cursor, err)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsOtherSecret(t *testing.T) {
+	SetCursorSecret([]byte("old-secret"))
+	cursor := EncodeCursor(Cursor{ID: 1, Sort: "title"})
+
+	SetCursorSecret([]byte("new-secret"))
+	if _, err := DecodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("DecodeCursor error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestSetSortRejectsCursorOfOtherSort(t *testing.T) {
+	SetCursorSecret([]byte("test-secret"))
+
+	tests := []struct {
+		name    string
+		cursor  Cursor
+		orderBy string
+		wantErr bool
+	}{
+		{name: "same sort", cursor: Cursor{Keys: []string{"a"}, ID: 1, Sort: "title"}, orderBy: "title"},
+		{name: "default sort", cursor: Cursor{Keys: []string{"1"}, ID: 1, Sort: "id"}, orderBy: ""},
+		{name: "other field", cursor: Cursor{Keys: []string{"a"}, ID: 1, Sort: "title"}, orderBy: "id", wantErr: true},
+		{name: "other direction", cursor: Cursor{Keys: []string{"a"}, ID: 1, Sort: "title"}, orderBy: "-title", wantErr: true},
+		{name: "keys don't match sort", cursor: Cursor{ID: 1, Sort: "title"}, orderBy: "title", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &PaginationQuery{OrderBy: tt.orderBy}
+			if err := q.SetCursor(EncodeCursor(tt.cursor)); err != nil {
+				t.Fatalf("SetCursor: %v", err)
+			}
+
+			err := q.SetSort(testSortFields, "id")
+			if gotErr := errors.Is(err, ErrInvalidCursor); gotErr != tt.wantErr {
+				t.Fatalf("SetSort error = %v, want invalid cursor %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetSize(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    int
+		wantErr bool
+	}{
+		{size: "", want: defaultSize},
+		{size: "25", want: 25},
+		{size: "100", want: maxSize},
+		{size: "101", wantErr: true},
+		{size: "500", wantErr: true},
+		{size: "0", wantErr: true},
+		{size: "-1", wantErr: true},
+		{size: "ten", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		q := &PaginationQuery{}
+		err := q.SetSize(tt.size)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("SetSize(%q) error = %v, wantErr %v", tt.size, err, tt.wantErr)
+		}
+		if err == nil && q.Size != tt.want {
+			t.Fatalf("SetSize(%q) = %d, want %d", tt.size, q.Size, tt.want)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 const (
 	defaultSize = 10
+	maxSize     = 100
 )
 
 // Compile time check to ensure PaginationQuery implements Pagination interface
@@ -21,19 +22,29 @@ type PaginationOps interface {
 	SetSize(sizeQuery string) error
 	SetPage(pageQuery string) error
 	SetOrderBy(orderByQuery string)
+	SetCursor(cursorQuery string) error
+	SetSort(whitelist SortWhitelist, defaultSort string) error
 	GetOffset() int
 	GetLimit() int
 	GetOrderBy() string
 	GetPage() int
 	GetSize() int
 	GetQueryString() string
+	GetCursor() *Cursor
+	GetSort() SortSpec
+	IsCursorMode() bool
 }
 
-// PaginationQuery params
+// PaginationQuery params, Page and Cursor are mutually exclusive.
+// When Cursor is set, list is paginated by keyset instead of OFFSET.
 type PaginationQuery struct {
 	Size    int    `json:"size,omitempty"`
 	Page    int    `json:"page,omitempty"`
 	OrderBy string `json:"orderBy,omitempty"`
+	Cursor  string `json:"cursor,omitempty"`
+
+	cursor *Cursor
+	sort   SortSpec
 }
 
 // Set Page size, SetSize
@@ -45,7 +56,11 @@ func (q *PaginationQuery) SetSize(sizeQuery string) error {
 
 	n, err := strconv.Atoi(sizeQuery)
 	if err != nil {
-		return errlst.ParseErrors(err)
+		return errlst.Validation("size must be a number", err)
+	}
+
+	if n < 1 || n > maxSize {
+		return errlst.Validation(fmt.Sprintf("size must be between 1 and %d", maxSize), errlst.ErrRange)
 	}
 	q.Size = n
 
@@ -55,13 +70,17 @@ func (q *PaginationQuery) SetSize(sizeQuery string) error {
 // Set Page number, SetPage
 func (q *PaginationQuery) SetPage(pageQuery string) error {
 	if pageQuery == "" {
-		q.Size = 0
+		q.Page = 0
 		return nil
 	}
 
 	n, err := strconv.Atoi(pageQuery)
 	if err != nil {
-		return errlst.ParseErrors(err)
+		return errlst.Validation("page must be a number", err)
+	}
+
+	if n < 0 {
+		return errlst.Validation("page can't be negative", errlst.ErrRange)
 	}
 	q.Page = n
 
@@ -73,6 +92,38 @@ func (q *PaginationQuery) SetOrderBy(orderByQuery string) {
 	q.OrderBy = orderByQuery
 }
 
+// Set cursor, SetCursor
+func (q *PaginationQuery) SetCursor(cursorQuery string) error {
+	if cursorQuery == "" {
+		return nil
+	}
+
+	c, err := DecodeCursor(cursorQuery)
+	if err != nil {
+		return err
+	}
+	q.Cursor, q.cursor = cursorQuery, c
+
+	return nil
+}
+
+// SetSort parses OrderBy against whitelist of resource, it must be called
+// by handlers of list endpoints after GetPaginationFromCtx
+func (q *PaginationQuery) SetSort(whitelist SortWhitelist, defaultSort string) error {
+	spec, err := ParseSort(q.OrderBy, whitelist, defaultSort)
+	if err != nil {
+		return err
+	}
+
+	// cursor is only valid for the order it was made for
+	if q.cursor != nil && (q.cursor.Sort != spec.String() || len(q.cursor.Keys) != len(spec)) {
+		return errlst.Validation("cursor was made for another orderBy", ErrInvalidCursor)
+	}
+	q.sort = spec
+
+	return nil
+}
+
 // GetOffset is
 func (q *PaginationQuery) GetOffset() int {
 	if q.Page == 0 {
@@ -104,24 +155,58 @@ func (q *PaginationQuery) GetSize() int {
 
 // GetQueryString is
 func (q *PaginationQuery) GetQueryString() string {
+	if q.IsCursorMode() {
+		return fmt.Sprintf("cursor=%s&size=%v&orderBy=%s", q.Cursor, q.Size, q.OrderBy)
+	}
+
 	return fmt.Sprintf("page=%v&size=%v&orderBy=%s", q.Page, q.Size, q.OrderBy)
 }
 
+// GetCursor returns decoded cursor, nil in page mode
+func (q *PaginationQuery) GetCursor() *Cursor {
+	return q.cursor
+}
+
+// GetSort returns parsed sort spec, nil before SetSort
+func (q *PaginationQuery) GetSort() SortSpec {
+	return q.sort
+}
+
+// IsCursorMode is
+func (q *PaginationQuery) IsCursorMode() bool {
+	return q.cursor != nil
+}
+
 // GetPaginationFromCtx is
 func GetPaginationFromCtx(c echo.Context) (*PaginationQuery, error) {
+	return GetPagination(c.QueryParam("page"), c.QueryParam("size"), c.QueryParam("orderBy"), c.QueryParam("cursor"))
+}
+
+// GetPagination builds pagination query from raw values, transports
+// without echo context (gRPC, GraphQL) use it directly
+func GetPagination(page, size, orderBy, cursor string) (*PaginationQuery, error) {
 	q := &PaginationQuery{}
 
 	// set page
-	if err := q.SetPage(c.QueryParam("page")); err != nil {
-		return nil, errlst.ParseErrors(err)
+	if err := q.SetPage(page); err != nil {
+		return nil, err
 	}
 
 	// set size
-	if err := q.SetSize(c.QueryParam("size")); err != nil {
-		return nil, errlst.ParseErrors(err)
+	if err := q.SetSize(size); err != nil {
+		return nil, err
 	}
 	// set orderby
-	q.SetOrderBy(c.QueryParam("orderBy"))
+	q.SetOrderBy(orderBy)
+
+	// set cursor
+	if err := q.SetCursor(cursor); err != nil {
+		return nil, err
+	}
+
+	if q.IsCursorMode() && q.Page != 0 {
+		return nil, errlst.Validation("page and cursor can't be used together", errlst.ErrBadQueryParams)
+	}
 
 	return q, nil
 }
@@ -0,0 +1,148 @@
+package pagination
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+)
+
+// Sorting is given as orderBy=-release_date,title, minus means descending.
+// Field names are checked against per-resource whitelist and only whitelisted
+// SQL columns ever reach the query, raw orderBy is never interpolated.
+
+// SortWhitelist maps field names clients can sort by to SQL columns.
+type SortWhitelist map[string]string
+
+// Names returns sorted field names of whitelist.
+func (w SortWhitelist) Names() []string {
+	names := make([]string, 0, len(w))
+	for name := range w {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// SortField is one field of sort specification.
+type SortField struct {
+	Name   string
+	Column string
+	Desc   bool
+}
+
+// SortSpec is parsed and validated sort specification.
+type SortSpec []SortField
+
+// ParseSort parses orderBy value and validates it against whitelist.
+// Empty orderBy gives defaultSort, which must be valid itself.
+func ParseSort(orderBy string, whitelist SortWhitelist, defaultSort string) (SortSpec, error) {
+	if strings.TrimSpace(orderBy) == "" {
+		orderBy = defaultSort
+	}
+
+	var (
+		spec SortSpec
+		seen = make(map[string]bool)
+	)
+
+	for _, part := range strings.Split(orderBy, ",") {
+		name := strings.TrimSpace(part)
+
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(strings.TrimPrefix(name, "-"), "+")
+
+		column, ok := whitelist[name]
+		if !ok {
+			return nil, errlst.NewBadQueryParamsError(fmt.Sprintf(
+				"invalid sort field %q, allowed values: %s", name, strings.Join(whitelist.Names(), ", "),
+			))
+		}
+
+		if seen[name] {
+			return nil, errlst.NewBadQueryParamsError(fmt.Sprintf("sort field %q is given more than once", name))
+		}
+		seen[name] = true
+
+		spec = append(spec, SortField{Name: name, Column: column, Desc: desc})
+	}
+
+	return spec, nil
+}
+
+// String returns canonical form of spec, e.g. -release_date,title
+func (s SortSpec) String() string {
+	parts := make([]string, 0, len(s))
+	for _, f := range s {
+		if f.Desc {
+			parts = append(parts, "-"+f.Name)
+		} else {
+			parts = append(parts, f.Name)
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// tieBreaker returns id column in direction of last sort field,
+// so rows with equal sort keys keep stable order.
+func (s SortSpec) tieBreaker(idColumn string) SortField {
+	f := SortField{Name: "id", Column: idColumn}
+	if len(s) > 0 {
+		f.Desc = s[len(s)-1].Desc
+	}
+
+	return f
+}
+
+// OrderBy returns ORDER BY expression (without keyword) with id as tie breaker.
+// reverse flips every direction, it's used to read rows before cursor.
+func (s SortSpec) OrderBy(idColumn string, reverse bool) string {
+	fields := append(slices.Clone(s), s.tieBreaker(idColumn))
+
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f.Desc != reverse {
+			parts = append(parts, f.Column+" DESC")
+		} else {
+			parts = append(parts, f.Column+" ASC")
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// KeysetCondition returns WHERE condition (without keyword) selecting rows after
+// cursor (before it if cursor.Prev) and its args, placeholders start from argPos.
+// For mixed directions it expands to (a > $1) OR (a = $1 AND b < $2) OR ...
+func (s SortSpec) KeysetCondition(cursor *Cursor, idColumn string, argPos int) (string, []interface{}) {
+	fields := append(slices.Clone(s), s.tieBreaker(idColumn))
+
+	args := make([]interface{}, 0, len(fields))
+	for _, key := range cursor.Keys {
+		args = append(args, key)
+	}
+	args = append(args, cursor.ID)
+
+	var (
+		ors    []string
+		equals []string
+	)
+	for i, f := range fields {
+		placeholder := "$" + strconv.Itoa(argPos+i)
+
+		op := ">"
+		if f.Desc != cursor.Prev {
+			op = "<"
+		}
+
+		ands := append(slices.Clone(equals), f.Column+" "+op+" "+placeholder)
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+		equals = append(equals, f.Column+" = "+placeholder)
+	}
+
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
@@ -0,0 +1,138 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jumayevgadam/music-app/pkg/errlst"
+)
+
+var testWhitelist = SortWhitelist{
+	"id":           "songs.id",
+	"title":        "songs.title",
+	"release_date": "songs.release_date",
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		orderBy string
+		want    SortSpec
+	}{
+		{
+			orderBy: "-release_date,title",
+			want: SortSpec{
+				{Name: "release_date", Column: "songs.release_date", Desc: true},
+				{Name: "title", Column: "songs.title"},
+			},
+		},
+		{
+			orderBy: " +title , -id ",
+			want: SortSpec{
+				{Name: "title", Column: "songs.title"},
+				{Name: "id", Column: "songs.id", Desc: true},
+			},
+		},
+		{
+			// empty orderBy gives default sort
+			orderBy: "",
+			want:    SortSpec{{Name: "id", Column: "songs.id"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.orderBy, func(t *testing.T) {
+			got, err := ParseSort(tt.orderBy, testWhitelist, "id")
+			if err != nil {
+				t.Fatalf("ParseSort: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseSort = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSortRejects(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":     "title,views",
+		"sql injection":     "title; DROP TABLE songs",
+		"raw column":        "songs.title",
+		"repeated field":    "title,-title",
+		"empty field":       "title,,id",
+		"double minus sign": "--title",
+	}
+
+	for name, orderBy := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSort(orderBy, testWhitelist, "id")
+			if err == nil {
+				t.Fatalf("ParseSort(%q) accepted invalid sort", orderBy)
+			}
+
+			var restErr errlst.RestErr
+			if !errors.As(err, &restErr) || restErr.Status() != 400 {
+				t.Fatalf("ParseSort(%q) error = %v, want 400", orderBy, err)
+			}
+		})
+	}
+}
+
+func TestSortSpecString(t *testing.T) {
+	spec, err := ParseSort("+title,-release_date", testWhitelist, "id")
+	if err != nil {
+		t.Fatalf("ParseSort: %v", err)
+	}
+
+	if got := spec.String(); got != "title,-release_date" {
+		t.Fatalf("String() = %q, want %q", got, "title,-release_date")
+	}
+}
+
+func TestSortSpecOrderBy(t *testing.T) {
+	spec, err := ParseSort("title,-release_date", testWhitelist, "id")
+	if err != nil {
+		t.Fatalf("ParseSort: %v", err)
+	}
+
+	// id follows direction of last field
+	want := "songs.title ASC, songs.release_date DESC, songs.id DESC"
+	if got := spec.OrderBy("songs.id", false); got != want {
+		t.Fatalf("OrderBy = %q, want %q", got, want)
+	}
+
+	reversed := "songs.title DESC, songs.release_date ASC, songs.id ASC"
+	if got := spec.OrderBy("songs.id", true); got != reversed {
+		t.Fatalf("OrderBy reversed = %q, want %q", got, reversed)
+	}
+}
+
+func TestSortSpecKeysetCondition(t *testing.T) {
+	spec, err := ParseSort("title,-release_date", testWhitelist, "id")
+	if err != nil {
+		t.Fatalf("ParseSort: %v", err)
+	}
+
+	cursor := &Cursor{Keys: []string{"Yesterday", "1965-08-06"}, ID: 7}
+
+	condition, args := spec.KeysetCondition(cursor, "songs.id", 3)
+	want := "((songs.title > $3) OR " +
+		"(songs.title = $3 AND songs.release_date < $4) OR " +
+		"(songs.title = $3 AND songs.release_date = $4 AND songs.id < $5))"
+	if condition != want {
+		t.Fatalf("KeysetCondition = %q, want %q", condition, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"Yesterday", "1965-08-06", 7}) {
+		t.Fatalf("args = %v", args)
+	}
+
+	// previous page flips every comparison
+	cursor.Prev = true
+	condition, _ = spec.KeysetCondition(cursor, "songs.id", 1)
+	want = "((songs.title < $1) OR " +
+		"(songs.title = $1 AND songs.release_date > $2) OR " +
+		"(songs.title = $1 AND songs.release_date = $2 AND songs.id > $3))"
+	if condition != want {
+		t.Fatalf("KeysetCondition prev = %q, want %q", condition, want)
+	}
+}
@@ -0,0 +1,124 @@
+package releasedate
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Package releasedate parses release dates given with different precision.
+// Upstream info API sends "16.07.2006", clients send ISO dates and often know
+// only year or month of release. Date keeps first day of known period together
+// with precision, so it sorts as DATE and is printed back as it was known.
+
+// Precision is
+type Precision string
+
+const (
+	// PrecisionDay is
+	PrecisionDay Precision = "day"
+	// PrecisionMonth is
+	PrecisionMonth Precision = "month"
+	// PrecisionYear is
+	PrecisionYear Precision = "year"
+)
+
+// Precisions are
+var Precisions = []Precision{PrecisionDay, PrecisionMonth, PrecisionYear}
+
+// ErrInvalidDate is returned for values of unsupported format
+var ErrInvalidDate = errors.New("release date must be DD.MM.YYYY, YYYY-MM-DD, MM.YYYY, YYYY-MM or YYYY")
+
+// layouts are accepted formats, in order they are tried
+var layouts = []struct {
+	layout    string
+	precision Precision
+}{
+	{time.DateOnly, PrecisionDay},
+	{"02.01.2006", PrecisionDay},
+	{"2006-01", PrecisionMonth},
+	{"01.2006", PrecisionMonth},
+	{"2006", PrecisionYear},
+}
+
+// Date is release date, zero Date means date isn't given
+type Date struct {
+	// Time is first day of known period, in UTC
+	Time      time.Time
+	Precision Precision
+}
+
+// New returns date of time truncated to precision, unknown precision is day
+func New(t time.Time, precision Precision) Date {
+	year, month, day := t.Date()
+
+	switch precision {
+	case PrecisionYear:
+		month, day = time.January, 1
+	case PrecisionMonth:
+		day = 1
+	default:
+		precision = PrecisionDay
+	}
+
+	return Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC), Precision: precision}
+}
+
+// Parse parses date in one of accepted formats, RFC 3339 timestamps are taken by their date
+func Parse(raw string) (Date, error) {
+	raw = strings.TrimSpace(raw)
+
+	for _, l := range layouts {
+		if t, err := time.Parse(l.layout, raw); err == nil && t.Year() > 0 {
+			return New(t, l.precision), nil
+		}
+	}
+
+	if t, err := time.Parse(time.RFC3339, raw); err == nil && t.Year() > 0 {
+		return New(t, PrecisionDay), nil
+	}
+
+	return Date{}, ErrInvalidDate
+}
+
+// IsZero is
+func (d Date) IsZero() bool {
+	return d.Time.IsZero()
+}
+
+// String returns date in ISO form of its precision: 2006-07-16, 2006-07 or 2006
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+
+	switch d.Precision {
+	case PrecisionYear:
+		return d.Time.Format("2006")
+	case PrecisionMonth:
+		return d.Time.Format("2006-01")
+	default:
+		return d.Time.Format(time.DateOnly)
+	}
+}
+
+// MarshalText is, JSON and CSV use it as well
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText is, empty text gives zero Date so "required" reports it
+func (d *Date) UnmarshalText(text []byte) error {
+	if len(strings.TrimSpace(string(text))) == 0 {
+		*d = Date{}
+		return nil
+	}
+
+	date, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*d = date
+
+	return nil
+}
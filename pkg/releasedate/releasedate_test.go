@@ -0,0 +1,132 @@
+package releasedate
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw       string
+		want      time.Time
+		precision Precision
+		str       string
+	}{
+		{raw: "2006-07-16", want: time.Date(2006, time.July, 16, 0, 0, 0, 0, time.UTC), precision: PrecisionDay, str: "2006-07-16"},
+		{raw: "16.07.2006", want: time.Date(2006, time.July, 16, 0, 0, 0, 0, time.UTC), precision: PrecisionDay, str: "2006-07-16"},
+		{raw: " 29.02.2020 ", want: time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC), precision: PrecisionDay, str: "2020-02-29"},
+		{raw: "2006-07-16T23:30:00+05:00", want: time.Date(2006, time.July, 16, 0, 0, 0, 0, time.UTC), precision: PrecisionDay, str: "2006-07-16"},
+		{raw: "2006-07", want: time.Date(2006, time.July, 1, 0, 0, 0, 0, time.UTC), precision: PrecisionMonth, str: "2006-07"},
+		{raw: "07.2006", want: time.Date(2006, time.July, 1, 0, 0, 0, 0, time.UTC), precision: PrecisionMonth, str: "2006-07"},
+		{raw: "2006", want: time.Date(2006, time.January, 1, 0, 0, 0, 0, time.UTC), precision: PrecisionYear, str: "2006"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := Parse(tt.raw)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.raw, err)
+			}
+			if !got.Time.Equal(tt.want) || got.Time.Location() != time.UTC || got.Precision != tt.precision {
+				t.Fatalf("Parse(%q) = %v %s, want %v %s", tt.raw, got.Time, got.Precision, tt.want, tt.precision)
+			}
+			if got.String() != tt.str {
+				t.Fatalf("Parse(%q).String() = %s, want %s", tt.raw, got.String(), tt.str)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"31.02.2020",
+		"29.02.2021",
+		"2020-02-30",
+		"2020-13",
+		"13.2020",
+		"32.01.2020",
+		"0000",
+		"06",
+		"16/07/2006",
+		"July 2006",
+		"2006-7-16",
+	} {
+		if got, err := Parse(raw); !errors.Is(err, ErrInvalidDate) {
+			t.Errorf("Parse(%q) = %v, %v, want ErrInvalidDate", raw, got, err)
+		}
+	}
+}
+
+func TestNewTruncatesToPrecision(t *testing.T) {
+	at := time.Date(2006, time.July, 16, 13, 45, 0, 0, time.FixedZone("TMT", 5*60*60))
+
+	tests := []struct {
+		precision Precision
+		want      Date
+	}{
+		{precision: PrecisionDay, want: Date{Time: time.Date(2006, time.July, 16, 0, 0, 0, 0, time.UTC), Precision: PrecisionDay}},
+		{precision: PrecisionMonth, want: Date{Time: time.Date(2006, time.July, 1, 0, 0, 0, 0, time.UTC), Precision: PrecisionMonth}},
+		{precision: PrecisionYear, want: Date{Time: time.Date(2006, time.January, 1, 0, 0, 0, 0, time.UTC), Precision: PrecisionYear}},
+		{precision: "decade", want: Date{Time: time.Date(2006, time.July, 16, 0, 0, 0, 0, time.UTC), Precision: PrecisionDay}},
+	}
+
+	for _, tt := range tests {
+		if got := New(at, tt.precision); got != tt.want {
+			t.Errorf("New(%v, %s) = %+v, want %+v", at, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	type song struct {
+		ReleaseDate Date `json:"release_date"`
+	}
+
+	tests := []struct {
+		raw  string
+		json string
+	}{
+		{raw: "16.07.2006", json: `{"release_date":"2006-07-16"}`},
+		{raw: "2006-07", json: `{"release_date":"2006-07"}`},
+		{raw: "2006", json: `{"release_date":"2006"}`},
+		{raw: "", json: `{"release_date":""}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			var date Date
+			if tt.raw != "" {
+				var err error
+				if date, err = Parse(tt.raw); err != nil {
+					t.Fatalf("Parse(%q): %v", tt.raw, err)
+				}
+			}
+
+			encoded, err := json.Marshal(song{ReleaseDate: date})
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(encoded) != tt.json {
+				t.Fatalf("Marshal = %s, want %s", encoded, tt.json)
+			}
+
+			var decoded song
+			if err := json.Unmarshal(encoded, &decoded); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", encoded, err)
+			}
+			if decoded.ReleaseDate != date {
+				t.Fatalf("Unmarshal(%s) = %+v, want %+v", encoded, decoded.ReleaseDate, date)
+			}
+		})
+	}
+}
+
+func TestUnmarshalRejectsInvalidDate(t *testing.T) {
+	var date Date
+	if err := json.Unmarshal([]byte(`"31.02.2020"`), &date); !errors.Is(err, ErrInvalidDate) {
+		t.Fatalf("Unmarshal error = %v, want ErrInvalidDate", err)
+	}
+}
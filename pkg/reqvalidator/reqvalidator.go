@@ -2,7 +2,12 @@ package reqvalidator
 
 import (
 	"context"
+	"reflect"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
+	"github.com/jumayevgadam/music-app/pkg/releasedate"
+	"github.com/jumayevgadam/music-app/pkg/songlink"
 )
 
 // Use a single instance of Validate, it caches struct info
@@ -10,6 +15,31 @@ var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+
+	// report json names of fields, so clients see same names they sent
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || name == "" {
+			return field.Name
+		}
+
+		return name
+	})
+
+	// songlink accepts links songlink.Parse can classify
+	_ = validate.RegisterValidation("songlink", func(fl validator.FieldLevel) bool {
+		_, err := songlink.Parse(fl.Field().String())
+		return err == nil
+	})
+
+	// release dates are validated as their string form, zero date is missing one
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if date, ok := field.Interface().(releasedate.Date); ok && !date.IsZero() {
+			return date.String()
+		}
+
+		return nil
+	}, releasedate.Date{})
 }
 
 // ValidateStruct fields for models
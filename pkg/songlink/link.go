@@ -0,0 +1,150 @@
+package songlink
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Package songlink classifies song links by provider and extracts canonical ids,
+// so player knows which embed widget to render. Links of unknown hosts are generic.
+
+// Provider is
+type Provider string
+
+const (
+	// ProviderYouTube is
+	ProviderYouTube Provider = "youtube"
+	// ProviderSoundCloud is
+	ProviderSoundCloud Provider = "soundcloud"
+	// ProviderSpotify is
+	ProviderSpotify Provider = "spotify"
+	// ProviderBandcamp is
+	ProviderBandcamp Provider = "bandcamp"
+	// ProviderGeneric is any other http(s) link, it has no canonical id
+	ProviderGeneric Provider = "generic"
+)
+
+// Providers are
+var Providers = []Provider{ProviderYouTube, ProviderSoundCloud, ProviderSpotify, ProviderBandcamp, ProviderGeneric}
+
+// ErrInvalidLink is returned for links which aren't absolute http(s) URLs
+var ErrInvalidLink = errors.New("link must be absolute http or https URL")
+
+// Link is parsed song link
+type Link struct {
+	Provider Provider
+	// ID is canonical id of song at provider, empty for generic links
+	ID string
+	// URL is canonical URL of song, it is original link for generic links
+	URL string
+}
+
+var (
+	youTubeID    = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	spotifyID    = regexp.MustCompile(`^[A-Za-z0-9]{22}$`)
+	pathSegment  = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	spotifyLocal = regexp.MustCompile(`^intl-[a-z]{2}(-[a-z]{2})?$`)
+)
+
+// Parse classifies link, it fails only when link isn't http(s) URL
+func Parse(raw string) (*Link, error) {
+	raw = strings.TrimSpace(raw)
+
+	// spotify:track:<id> URIs are what Spotify apps copy
+	if id, ok := strings.CutPrefix(raw, "spotify:track:"); ok && spotifyID.MatchString(id) {
+		return spotifyLink(id), nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidLink
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+
+	var link *Link
+	switch {
+	case host == "youtube.com" || host == "m.youtube.com" || host == "music.youtube.com" || host == "youtu.be":
+		link = parseYouTube(host, u.Query(), segments)
+	case host == "open.spotify.com":
+		link = parseSpotify(segments)
+	case host == "soundcloud.com" || host == "m.soundcloud.com":
+		link = parseSoundCloud(segments)
+	case strings.HasSuffix(host, ".bandcamp.com"):
+		link = parseBandcamp(strings.TrimSuffix(host, ".bandcamp.com"), segments)
+	}
+
+	if link == nil {
+		return &Link{Provider: ProviderGeneric, URL: raw}, nil
+	}
+
+	return link, nil
+}
+
+// parseYouTube handles watch?v=, youtu.be/<id>, /embed/<id> and /shorts/<id>
+func parseYouTube(host string, query url.Values, segments []string) *Link {
+	var id string
+
+	switch {
+	case host == "youtu.be" && len(segments) == 1:
+		id = segments[0]
+	case len(segments) == 1 && segments[0] == "watch":
+		id = query.Get("v")
+	case len(segments) == 2 && (segments[0] == "embed" || segments[0] == "shorts" || segments[0] == "live"):
+		id = segments[1]
+	}
+
+	if !youTubeID.MatchString(id) {
+		return nil
+	}
+
+	return &Link{Provider: ProviderYouTube, ID: id, URL: "https://www.youtube.com/watch?v=" + id}
+}
+
+// parseSpotify handles /track/<id>, optionally after /intl-<lang>/, albums and playlists aren't songs
+func parseSpotify(segments []string) *Link {
+	if len(segments) > 0 && spotifyLocal.MatchString(segments[0]) {
+		segments = segments[1:]
+	}
+
+	if len(segments) != 2 || segments[0] != "track" || !spotifyID.MatchString(segments[1]) {
+		return nil
+	}
+
+	return spotifyLink(segments[1])
+}
+
+func spotifyLink(id string) *Link {
+	return &Link{Provider: ProviderSpotify, ID: id, URL: "https://open.spotify.com/track/" + id}
+}
+
+// parseSoundCloud handles /<user>/<track>, id is "<user>/<track>" because numeric
+// id is only known to SoundCloud API
+func parseSoundCloud(segments []string) *Link {
+	if len(segments) != 2 || segments[1] == "sets" || segments[1] == "tracks" ||
+		!pathSegment.MatchString(segments[0]) || !pathSegment.MatchString(segments[1]) {
+		return nil
+	}
+
+	id := strings.ToLower(segments[0] + "/" + segments[1])
+
+	return &Link{Provider: ProviderSoundCloud, ID: id, URL: "https://soundcloud.com/" + id}
+}
+
+// parseBandcamp handles <artist>.bandcamp.com/track/<slug>, id is "<artist>/<slug>"
+func parseBandcamp(artist string, segments []string) *Link {
+	if !pathSegment.MatchString(artist) || len(segments) != 2 || segments[0] != "track" || !pathSegment.MatchString(segments[1]) {
+		return nil
+	}
+
+	slug := strings.ToLower(segments[1])
+
+	return &Link{
+		Provider: ProviderBandcamp,
+		ID:       artist + "/" + slug,
+		URL:      "https://" + artist + ".bandcamp.com/track/" + slug,
+	}
+}
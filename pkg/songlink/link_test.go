@@ -0,0 +1,135 @@
+package songlink
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want Link
+	}{
+		{
+			name: "youtu.be",
+			raw:  "https://youtu.be/dQw4w9WgXcQ",
+			want: Link{Provider: ProviderYouTube, ID: "dQw4w9WgXcQ", URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		},
+		{
+			name: "youtu.be with share params",
+			raw:  "https://youtu.be/dQw4w9WgXcQ?si=abc&t=42",
+			want: Link{Provider: ProviderYouTube, ID: "dQw4w9WgXcQ", URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		},
+		{
+			name: "youtube watch",
+			raw:  "https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=PL1",
+			want: Link{Provider: ProviderYouTube, ID: "dQw4w9WgXcQ", URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		},
+		{
+			name: "youtube watch over http without www",
+			raw:  "  http://YouTube.com/watch?v=dQw4w9WgXcQ  ",
+			want: Link{Provider: ProviderYouTube, ID: "dQw4w9WgXcQ", URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		},
+		{
+			name: "mobile youtube",
+			raw:  "https://m.youtube.com/watch?v=dQw4w9WgXcQ",
+			want: Link{Provider: ProviderYouTube, ID: "dQw4w9WgXcQ", URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		},
+		{
+			name: "music youtube",
+			raw:  "https://music.youtube.com/watch?v=dQw4w9WgXcQ&feature=share",
+			want: Link{Provider: ProviderYouTube, ID: "dQw4w9WgXcQ", URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		},
+		{
+			name: "youtube embed",
+			raw:  "https://www.youtube.com/embed/dQw4w9WgXcQ",
+			want: Link{Provider: ProviderYouTube, ID: "dQw4w9WgXcQ", URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		},
+		{
+			name: "youtube shorts",
+			raw:  "https://youtube.com/shorts/dQw4w9WgXcQ",
+			want: Link{Provider: ProviderYouTube, ID: "dQw4w9WgXcQ", URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		},
+		{
+			name: "youtube with bad id is generic",
+			raw:  "https://www.youtube.com/watch?v=short",
+			want: Link{Provider: ProviderGeneric, URL: "https://www.youtube.com/watch?v=short"},
+		},
+		{
+			name: "youtube channel is generic",
+			raw:  "https://www.youtube.com/@muse",
+			want: Link{Provider: ProviderGeneric, URL: "https://www.youtube.com/@muse"},
+		},
+		{
+			name: "spotify track",
+			raw:  "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC?si=xyz",
+			want: Link{Provider: ProviderSpotify, ID: "4uLU6hMCjMI75M1A2tKUQC", URL: "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC"},
+		},
+		{
+			name: "localized spotify track",
+			raw:  "https://open.spotify.com/intl-de/track/4uLU6hMCjMI75M1A2tKUQC",
+			want: Link{Provider: ProviderSpotify, ID: "4uLU6hMCjMI75M1A2tKUQC", URL: "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC"},
+		},
+		{
+			name: "spotify uri",
+			raw:  "spotify:track:4uLU6hMCjMI75M1A2tKUQC",
+			want: Link{Provider: ProviderSpotify, ID: "4uLU6hMCjMI75M1A2tKUQC", URL: "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC"},
+		},
+		{
+			name: "spotify album is generic",
+			raw:  "https://open.spotify.com/album/4uLU6hMCjMI75M1A2tKUQC",
+			want: Link{Provider: ProviderGeneric, URL: "https://open.spotify.com/album/4uLU6hMCjMI75M1A2tKUQC"},
+		},
+		{
+			name: "soundcloud",
+			raw:  "https://soundcloud.com/Muse/Hysteria",
+			want: Link{Provider: ProviderSoundCloud, ID: "muse/hysteria", URL: "https://soundcloud.com/muse/hysteria"},
+		},
+		{
+			name: "bandcamp",
+			raw:  "https://artist.bandcamp.com/track/Some-Song",
+			want: Link{Provider: ProviderBandcamp, ID: "artist/some-song", URL: "https://artist.bandcamp.com/track/some-song"},
+		},
+		{
+			name: "unknown host",
+			raw:  "https://example.com/songs/1",
+			want: Link{Provider: ProviderGeneric, URL: "https://example.com/songs/1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.raw)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.raw, err)
+			}
+			if *got != tt.want {
+				t.Fatalf("Parse(%q) = %+v, want %+v", tt.raw, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := map[string]string{
+		"empty":                   "",
+		"spaces":                  "   ",
+		"relative":                "/watch?v=dQw4w9WgXcQ",
+		"no scheme":               "youtu.be/dQw4w9WgXcQ",
+		"ftp":                     "ftp://example.com/song.mp3",
+		"javascript":              "javascript:alert(1)",
+		"no host":                 "https:///watch?v=dQw4w9WgXcQ",
+		"spotify uri with bad id": "spotify:track:short",
+		"spotify album uri":       "spotify:album:4uLU6hMCjMI75M1A2tKUQC",
+		"broken escape":           "https://example.com/%zz",
+	}
+
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			if link, err := Parse(raw); !errors.Is(err, ErrInvalidLink) {
+				t.Fatalf("Parse(%q) = %+v, %v, want ErrInvalidLink", raw, link, err)
+			}
+		})
+	}
+}
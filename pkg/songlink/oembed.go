@@ -0,0 +1,95 @@
+package songlink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+)
+
+// ErrNoEndpoint is returned by fetcher for providers it has no endpoint for
+var ErrNoEndpoint = errors.New("provider has no metadata endpoint")
+
+// maxOEmbedBody limits read oEmbed response
+const maxOEmbedBody = 1 << 20
+
+// Metadata is what enrichment knows about linked song
+type Metadata struct {
+	Title        string
+	ThumbnailURL string
+	// Duration is in seconds, 0 when provider doesn't tell it
+	Duration int
+}
+
+// Fetcher loads metadata of parsed link, OEmbedFetcher is default implementation
+type Fetcher interface {
+	Fetch(ctx context.Context, link *Link) (*Metadata, error)
+}
+
+// OEmbedFetcher asks oEmbed endpoints of providers, endpoints are configurable,
+// so they can point to local stub server
+type OEmbedFetcher struct {
+	client    *http.Client
+	endpoints map[Provider]string
+}
+
+// NewOEmbedFetcher method is, providers missing in endpoints return ErrNoEndpoint
+func NewOEmbedFetcher(client *http.Client, endpoints map[Provider]string) *OEmbedFetcher {
+	return &OEmbedFetcher{client: client, endpoints: endpoints}
+}
+
+// oEmbedResponse keeps used fields of oEmbed response, duration isn't part of
+// spec but several providers send it
+type oEmbedResponse struct {
+	Title        string  `json:"title"`
+	ThumbnailURL string  `json:"thumbnail_url"`
+	Duration     float64 `json:"duration"`
+}
+
+// Fetch is
+func (f *OEmbedFetcher) Fetch(ctx context.Context, link *Link) (*Metadata, error) {
+	endpoint := f.endpoints[link.Provider]
+	if endpoint == "" {
+		return nil, ErrNoEndpoint
+	}
+
+	requestURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("oembed endpoint of %s: %w", link.Provider, err)
+	}
+	query := requestURL.Query()
+	query.Set("format", "json")
+	query.Set("url", link.URL)
+	requestURL.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := f.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oembed of %s answered %d", link.Provider, response.StatusCode)
+	}
+
+	var body oEmbedResponse
+	if err := json.NewDecoder(io.LimitReader(response.Body, maxOEmbedBody)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("can't decode oembed of %s: %w", link.Provider, err)
+	}
+
+	return &Metadata{
+		Title:        body.Title,
+		ThumbnailURL: body.ThumbnailURL,
+		Duration:     int(math.Round(body.Duration)),
+	}, nil
+}
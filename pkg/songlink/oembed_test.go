@@ -0,0 +1,161 @@
+package songlink
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubOEmbed serves oEmbed answers of handler on /youtube and /spotify paths
+func stubOEmbed(t *testing.T, handler http.HandlerFunc) *OEmbedFetcher {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewOEmbedFetcher(server.Client(), map[Provider]string{
+		ProviderYouTube: server.URL + "/youtube?maxwidth=480",
+		ProviderSpotify: server.URL + "/spotify",
+	})
+}
+
+func TestOEmbedFetcherFetch(t *testing.T) {
+	youTube, _ := Parse("https://youtu.be/dQw4w9WgXcQ")
+	spotify, _ := Parse("spotify:track:4uLU6hMCjMI75M1A2tKUQC")
+
+	tests := []struct {
+		name string
+		link *Link
+		path string
+		body string
+		want Metadata
+	}{
+		{
+			name: "youtube",
+			link: youTube,
+			path: "/youtube",
+			body: `{"type":"video","version":"1.0","title":"Never Gonna Give You Up","thumbnail_url":"https://i.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg","html":"<iframe></iframe>"}`,
+			want: Metadata{Title: "Never Gonna Give You Up", ThumbnailURL: "https://i.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg"},
+		},
+		{
+			name: "spotify with duration",
+			link: spotify,
+			path: "/spotify",
+			body: `{"type":"rich","title":"Hysteria","thumbnail_url":"https://i.scdn.co/image/ab67","duration":227.6}`,
+			want: Metadata{Title: "Hysteria", ThumbnailURL: "https://i.scdn.co/image/ab67", Duration: 228},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := stubOEmbed(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.path {
+					t.Errorf("path = %s, want %s", r.URL.Path, tt.path)
+				}
+				if got := r.URL.Query().Get("url"); got != tt.link.URL {
+					t.Errorf("url = %s, want %s", got, tt.link.URL)
+				}
+				if got := r.URL.Query().Get("format"); got != "json" {
+					t.Errorf("format = %s, want json", got)
+				}
+				if tt.path == "/youtube" && r.URL.Query().Get("maxwidth") != "480" {
+					t.Errorf("query of endpoint is lost: %s", r.URL.RawQuery)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			got, err := fetcher.Fetch(context.Background(), tt.link)
+			if err != nil {
+				t.Fatalf("Fetch: %v", err)
+			}
+			if *got != tt.want {
+				t.Fatalf("Fetch = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOEmbedFetcherErrors(t *testing.T) {
+	link, _ := Parse("https://youtu.be/dQw4w9WgXcQ")
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    string
+	}{
+		{
+			name:    "not found",
+			handler: func(w http.ResponseWriter, _ *http.Request) { http.NotFound(w, nil) },
+			want:    "answered 404",
+		},
+		{
+			name:    "unauthorized",
+			handler: func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusUnauthorized) },
+			want:    "answered 401",
+		},
+		{
+			name:    "server error",
+			handler: func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusBadGateway) },
+			want:    "answered 502",
+		},
+		{
+			name:    "malformed json",
+			handler: func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte(`{"title": "Hyst`)) },
+			want:    "can't decode oembed",
+		},
+		{
+			name:    "html instead of json",
+			handler: func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte(`<html>consent</html>`)) },
+			want:    "can't decode oembed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := stubOEmbed(t, tt.handler).Fetch(context.Background(), link)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Fetch error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOEmbedFetcherNoEndpoint(t *testing.T) {
+	link, _ := Parse("https://soundcloud.com/muse/hysteria")
+
+	fetcher := NewOEmbedFetcher(http.DefaultClient, map[Provider]string{})
+	if _, err := fetcher.Fetch(context.Background(), link); !errors.Is(err, ErrNoEndpoint) {
+		t.Fatalf("Fetch error = %v, want ErrNoEndpoint", err)
+	}
+}
+
+func TestOEmbedFetcherContextCanceled(t *testing.T) {
+	link, _ := Parse("https://youtu.be/dQw4w9WgXcQ")
+
+	release := make(chan struct{})
+	fetcher := stubOEmbed(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := fetcher.Fetch(ctx, link)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Fetch error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Fetch returned after %s, it must stop with context", elapsed)
+	}
+}
@@ -0,0 +1,76 @@
+package thumbnail
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	// decoders of supported formats register themselves in image package
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Package thumbnail decodes JPEG, PNG and WebP images and makes square
+// thumbnails of them in pure Go. Thumbnails are JPEG, transparent parts are
+// painted white as JPEG has no alpha.
+
+// Quality is JPEG quality of thumbnails
+const Quality = 85
+
+var (
+	// ErrUnsupportedImage is returned when image isn't JPEG, PNG or WebP or can't be decoded
+	ErrUnsupportedImage = errors.New("image must be JPEG, PNG or WebP")
+	// ErrTooManyPixels is returned before decoding images bigger than allowed
+	ErrTooManyPixels = errors.New("image has too many pixels")
+)
+
+// Decode reads size of image first, so huge images are refused before their
+// pixels are allocated. Format is "jpeg", "png" or "webp".
+func Decode(r io.ReadSeeker, maxPixels int) (image.Image, string, error) {
+	config, format, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, "", fmt.Errorf("%w: %dx%d, at most %d pixels allowed", ErrTooManyPixels, config.Width, config.Height, maxPixels)
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, "", err
+	}
+
+	img, format, err := image.Decode(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+
+	return img, format, nil
+}
+
+// Square crops center square of image and scales it to size, smaller images
+// aren't scaled up
+func Square(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(
+		bounds.Min.X+(bounds.Dx()-side)/2,
+		bounds.Min.Y+(bounds.Dy()-side)/2,
+	))
+
+	size = min(size, side)
+	thumb := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(thumb, thumb.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(thumb, thumb.Bounds(), img, crop, draw.Over, nil)
+
+	return thumb
+}
+
+// EncodeJPEG is
+func EncodeJPEG(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: Quality})
+}
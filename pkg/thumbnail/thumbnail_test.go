@@ -0,0 +1,118 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// testImage is red image with blue stripe in center fifth of width
+func testImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pixel := color.NRGBA{R: 255, A: 255}
+			if x >= width/2-width/10 && x < width/2+width/10 {
+				pixel = color.NRGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, pixel)
+		}
+	}
+
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	var jpegFile bytes.Buffer
+	if err := jpeg.Encode(&jpegFile, testImage(40, 30), nil); err != nil {
+		t.Fatalf("jpeg.Encode: %v", err)
+	}
+
+	for format, file := range map[string][]byte{"png": encodePNG(t, testImage(40, 30)), "jpeg": jpegFile.Bytes()} {
+		img, got, err := Decode(bytes.NewReader(file), 40*30)
+		if err != nil {
+			t.Fatalf("Decode %s: %v", format, err)
+		}
+		if got != format || img.Bounds().Dx() != 40 || img.Bounds().Dy() != 30 {
+			t.Fatalf("Decode %s = %s %v", format, got, img.Bounds())
+		}
+	}
+}
+
+func TestDecodeTooManyPixels(t *testing.T) {
+	file := encodePNG(t, testImage(40, 30))
+
+	if _, _, err := Decode(bytes.NewReader(file), 40*30-1); !errors.Is(err, ErrTooManyPixels) {
+		t.Fatalf("Decode error = %v, want %v", err, ErrTooManyPixels)
+	}
+
+	// size is checked from header, pixels of huge image are never read
+	header := file[:33]
+	if _, _, err := Decode(bytes.NewReader(header), 100); !errors.Is(err, ErrTooManyPixels) {
+		t.Fatalf("Decode of header error = %v, want %v", err, ErrTooManyPixels)
+	}
+}
+
+func TestDecodeUnsupported(t *testing.T) {
+	tests := map[string][]byte{
+		"gif":       []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;"),
+		"text":      []byte("not an image"),
+		"empty":     nil,
+		"truncated": encodePNG(t, testImage(40, 30))[:60],
+	}
+
+	for name, file := range tests {
+		if _, _, err := Decode(bytes.NewReader(file), 1<<20); !errors.Is(err, ErrUnsupportedImage) {
+			t.Fatalf("Decode %s error = %v, want %v", name, err, ErrUnsupportedImage)
+		}
+	}
+}
+
+func TestSquare(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		size, want    int
+	}{
+		{name: "landscape", width: 200, height: 100, size: 50, want: 50},
+		{name: "portrait", width: 100, height: 300, size: 60, want: 60},
+		{name: "small image isn't scaled up", width: 40, height: 30, size: 96, want: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thumb := Square(testImage(tt.width, tt.height), tt.size)
+			if bounds := thumb.Bounds(); bounds.Dx() != tt.want || bounds.Dy() != tt.want {
+				t.Fatalf("Square = %v, want %dx%d", bounds, tt.want, tt.want)
+			}
+
+			// center of image stays in center of square
+			if r, _, b, _ := thumb.At(tt.want/2, tt.want/2).RGBA(); b <= r {
+				t.Fatalf("center of thumbnail isn't blue")
+			}
+		})
+	}
+}
+
+func TestSquarePaintsTransparentWhite(t *testing.T) {
+	thumb := Square(image.NewNRGBA(image.Rect(0, 0, 10, 10)), 10)
+
+	if r, g, b, a := thumb.At(5, 5).RGBA(); r != 0xffff || g != 0xffff || b != 0xffff || a != 0xffff {
+		t.Fatalf("transparent pixel = %v %v %v %v, want white", r, g, b, a)
+	}
+}
@@ -0,0 +1,81 @@
+package urlsign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Package urlsign signs paths with expiry, so holder of URL can fetch it
+// without token till it expires. Signature covers path and expiry only,
+// other query params are not signed.
+
+const (
+	// ParamExpires is query param keeping unix time URL expires at
+	ParamExpires = "expires"
+	// ParamSignature is query param keeping signature of path and expiry
+	ParamSignature = "signature"
+)
+
+var (
+	// ErrMissingSignature is returned when URL isn't signed
+	ErrMissingSignature = errors.New("url is not signed")
+	// ErrInvalidSignature is returned when signature doesn't match path and expiry
+	ErrInvalidSignature = errors.New("url signature doesn't match")
+	// ErrExpired is returned when signed URL is used after its expiry
+	ErrExpired = errors.New("signed url has expired")
+)
+
+// Signer is
+type Signer struct {
+	secret []byte
+}
+
+// New is
+func New(secret []byte) *Signer {
+	return &Signer{secret: secret}
+}
+
+// Sign returns query params making path valid till expires
+func (s *Signer) Sign(path string, expires time.Time) url.Values {
+	unix := strconv.FormatInt(expires.Unix(), 10)
+
+	return url.Values{
+		ParamExpires:   {unix},
+		ParamSignature: {s.signature(path, unix)},
+	}
+}
+
+// Verify checks signature of path given in query params
+func (s *Signer) Verify(path string, query url.Values, now time.Time) error {
+	unix, signature := query.Get(ParamExpires), query.Get(ParamSignature)
+	if unix == "" || signature == "" {
+		return ErrMissingSignature
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.signature(path, unix))) {
+		return ErrInvalidSignature
+	}
+
+	expires, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !now.Before(time.Unix(expires, 0)) {
+		return ErrExpired
+	}
+
+	return nil
+}
+
+// signature returns base64 HMAC-SHA256 of path and expiry
+func (s *Signer) signature(path, unix string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(path + "\n" + unix))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,61 @@
+package urlsign
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const testPath = "/api/v1/song/7/audio/stream"
+
+var testNow = time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+
+func TestVerify(t *testing.T) {
+	signer := New([]byte("s3cret"))
+	signed := signer.Sign(testPath, testNow.Add(time.Hour))
+
+	// tampered returns copy of signed query with param set to value
+	tampered := func(param, value string) url.Values {
+		query := url.Values{}
+		for key, values := range signed {
+			query[key] = values
+		}
+		query.Set(param, value)
+		return query
+	}
+
+	tests := []struct {
+		name   string
+		signer *Signer
+		path   string
+		query  url.Values
+		now    time.Time
+		want   error
+	}{
+		{name: "valid", path: testPath, query: signed, now: testNow},
+		{name: "just before expiry", path: testPath, query: signed, now: testNow.Add(time.Hour - time.Second)},
+		{name: "unsigned params", path: testPath, query: tampered("download", "1"), now: testNow},
+		{name: "at expiry", path: testPath, query: signed, now: testNow.Add(time.Hour), want: ErrExpired},
+		{name: "other path", path: "/api/v1/song/8/audio/stream", query: signed, now: testNow, want: ErrInvalidSignature},
+		{name: "extended expiry", path: testPath, query: tampered(ParamExpires, "9999999999"), now: testNow, want: ErrInvalidSignature},
+		{name: "broken expiry", path: testPath, query: tampered(ParamExpires, "soon"), now: testNow, want: ErrInvalidSignature},
+		{name: "forged signature", path: testPath, query: tampered(ParamSignature, "AAAA"), now: testNow, want: ErrInvalidSignature},
+		{name: "other secret", signer: New([]byte("other")), path: testPath, query: signed, now: testNow, want: ErrInvalidSignature},
+		{name: "missing signature", path: testPath, query: tampered(ParamSignature, ""), now: testNow, want: ErrMissingSignature},
+		{name: "not signed", path: testPath, query: url.Values{}, now: testNow, want: ErrMissingSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := signer
+			if tt.signer != nil {
+				verifier = tt.signer
+			}
+
+			if err := verifier.Verify(tt.path, tt.query, tt.now); !errors.Is(err, tt.want) {
+				t.Fatalf("Verify error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}